@@ -0,0 +1,488 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestBuildAlertGeneratorURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			GeneratorURL:         "https://grafana.local/d/gotilert?var-app={{ .AppName }}&var-id={{ .GotilertID }}",
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	alert := fwd.buildAlert(server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 42}, ruleActions{}, time.Now())
+
+	want := "https://grafana.local/d/gotilert?var-app=backup&var-id=42"
+	if alert.GeneratorURL != want {
+		t.Fatalf("expected generatorURL %q, got %q", want, alert.GeneratorURL)
+	}
+
+	override, err := compileGeneratorURL("https://runbooks.local/{{ .AppName }}")
+	if err != nil {
+		t.Fatalf("compileGeneratorURL: %v", err)
+	}
+
+	alert = fwd.buildAlert(
+		server.App{Name: "nas", GeneratorURL: override},
+		gotify.MessageRequest{Message: "m"},
+		server.MessageID{Seq: 1},
+		ruleActions{},
+		time.Now(),
+	)
+	if alert.GeneratorURL != "https://runbooks.local/nas" {
+		t.Fatalf("expected app override, got %q", alert.GeneratorURL)
+	}
+}
+
+func TestBuildAlertDropsInvalidGeneratorURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	for _, generatorURL := range []string{
+		"/d/gotilert?var-app={{ .AppName }}",
+		"ftp://files.local/{{ .AppName }}",
+		"{{ .Extras.host }}",
+		`https://grafana.local/{{ index .Title "x" }}`,
+	} {
+		compiled, err := compileGeneratorURL(generatorURL)
+		if err != nil {
+			t.Fatalf("compileGeneratorURL(%q): %v", generatorURL, err)
+		}
+
+		alert := fwd.buildAlert(
+			server.App{Name: "nas", GeneratorURL: compiled},
+			gotify.MessageRequest{Title: "t", Message: "m"},
+			server.MessageID{Seq: 1},
+			ruleActions{},
+			time.Now(),
+		)
+		if alert.GeneratorURL != "" {
+			t.Fatalf("%q: expected generatorURL omitted, got %q", generatorURL, alert.GeneratorURL)
+		}
+	}
+}
+
+func TestBuildAlertOmitsUnsetGeneratorURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	alert := fwd.buildAlert(server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 1}, ruleActions{}, time.Now())
+
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if strings.Contains(string(payload), "generatorURL") {
+		t.Fatalf("expected generatorURL to be omitted, got %s", payload)
+	}
+}
+
+func TestBuildAlertGotilertIDAsAnnotation(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:              config.DefaultAlertName,
+			TTL:                    config.Duration{Duration: time.Hour},
+			SeverityFromPriority:   map[int]string{0: "info"},
+			GotilertIDAsAnnotation: true,
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	alert := fwd.buildAlert(server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 7}, ruleActions{}, time.Now())
+
+	if _, ok := alert.Labels["gotilert_id"]; ok {
+		t.Fatalf("expected no gotilert_id label, got %v", alert.Labels)
+	}
+
+	if alert.Annotations["gotilert_id"] != "7" {
+		t.Fatalf("expected gotilert_id annotation %q, got %v", "7", alert.Annotations)
+	}
+
+	asLabel := false
+
+	alert = fwd.buildAlert(
+		server.App{Name: "backup", GotilertIDAsAnnotation: &asLabel},
+		gotify.MessageRequest{Message: "m"},
+		server.MessageID{Seq: 8},
+		ruleActions{},
+		time.Now(),
+	)
+
+	if alert.Labels["gotilert_id"] != "8" {
+		t.Fatalf("expected app override to keep the gotilert_id label, got %v", alert.Labels)
+	}
+}
+
+func TestBuildAlertForcedLabelsWinOverAppLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			Labels:               map[string]string{"env": "default"},
+			ForcedLabels:         map[string]string{"env": "prod"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	appLabels, _, err := compileTemplates(map[string]string{"env": "dev", "team": "ops"}, nil)
+	if err != nil {
+		t.Fatalf("compileTemplates: %v", err)
+	}
+
+	alert := fwd.buildAlert(
+		server.App{Name: "backup", Labels: appLabels},
+		gotify.MessageRequest{Message: "m"},
+		server.MessageID{Seq: 1},
+		ruleActions{},
+		time.Now(),
+	)
+
+	if alert.Labels["env"] != "prod" {
+		t.Fatalf("expected forced env=prod to win over the app label, got %v", alert.Labels)
+	}
+
+	if alert.Labels["team"] != "ops" {
+		t.Fatalf("expected app label team=ops to be kept, got %v", alert.Labels)
+	}
+}
+
+func TestBuildAlertBackdatesStartsAt(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: 5 * time.Minute},
+			StartsAtBackdate:     config.Duration{Duration: 30 * time.Second},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	now := time.Now()
+	alert := fwd.buildAlert(server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 1}, ruleActions{}, now)
+
+	if got, want := alert.EndsAt.Sub(alert.StartsAt), 5*time.Minute+30*time.Second; got != want {
+		t.Fatalf("expected EndsAt - StartsAt = %s, got %s", want, got)
+	}
+
+	if !alert.EndsAt.Equal(now.Add(5 * time.Minute)) {
+		t.Fatalf("expected EndsAt = now + ttl, got %v", alert.EndsAt)
+	}
+}
+
+func TestBuildAlertTTLFromPriority(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: 5 * time.Minute},
+			TTLFromPriority:      map[int]config.Duration{8: {Duration: time.Hour}},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	appOverride := server.App{Name: "backup", TTLFromPriority: map[int]time.Duration{0: time.Minute}}
+
+	cases := []struct {
+		name     string
+		app      server.App
+		priority int
+		want     time.Duration
+	}{
+		{name: "below every key uses defaults.ttl", app: server.App{Name: "misc"}, priority: 2, want: 5 * time.Minute},
+		{name: "exact key", app: server.App{Name: "misc"}, priority: 8, want: time.Hour},
+		{name: "closest lower key", app: server.App{Name: "misc"}, priority: 10, want: time.Hour},
+		{name: "app map replaces defaults", app: appOverride, priority: 10, want: time.Minute},
+	}
+
+	for _, testCase := range cases {
+		now := time.Now()
+		msg := gotify.MessageRequest{Message: "m", Priority: testCase.priority}
+
+		alert := fwd.buildAlert(testCase.app, msg, server.MessageID{Seq: 1}, ruleActions{}, now)
+
+		if got := alert.EndsAt.Sub(now); got != testCase.want {
+			t.Fatalf("%s: expected ttl %s, got %s", testCase.name, testCase.want, got)
+		}
+	}
+}
+
+func TestPickSummaryTruncatesOnRuneBoundaries(t *testing.T) {
+	t.Parallel()
+
+	// Byte slicing at 3 would cut the second "é" in half.
+	got := pickSummary("backup", "", "  éééé  ", 3)
+	if got != "éé…" {
+		t.Fatalf("expected %q, got %q", "éé…", got)
+	}
+
+	if got := pickSummary("backup", "", "ééé", 3); got != "ééé" {
+		t.Fatalf("expected message within the limit untouched, got %q", got)
+	}
+
+	if got := pickSummary("backup", " Title ", "ééé", 1); got != "Title" {
+		t.Fatalf("expected the title to win, got %q", got)
+	}
+}
+
+func TestPickSummaryKeepsEmojiAndAccentsValid(t *testing.T) {
+	t.Parallel()
+
+	message := "🔥 Sauvegarde échouée sur nœud «nas» 💾 — réessai prévu"
+
+	for maxLen := 1; maxLen <= utf8.RuneCountInString(message)+1; maxLen++ {
+		got := pickSummary("backup", "", message, maxLen)
+
+		if !utf8.ValidString(got) {
+			t.Fatalf("maxLen=%d: expected valid UTF-8, got %q", maxLen, got)
+		}
+
+		if count := utf8.RuneCountInString(got); count > maxLen {
+			t.Fatalf("maxLen=%d: expected at most %d runes, got %d (%q)", maxLen, maxLen, count, got)
+		}
+	}
+
+	if got := pickSummary("backup", "", message, 2); got != "🔥…" {
+		t.Fatalf("expected the emoji kept whole, got %q", got)
+	}
+
+	if got := pickSummary("backup", "", "caf\xc3", 10); !utf8.ValidString(got) {
+		t.Fatalf("expected invalid input repaired, got %q", got)
+	}
+}
+
+func TestBuildAlertAlertnameFromTitle(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		app   server.App
+		title string
+		want  string
+	}{
+		{
+			name:  "title sanitized",
+			app:   server.App{Name: "backup", AlertnameFromTitle: true},
+			title: "  Backup\tfailed\x00 on\n nas \xff ",
+			want:  "Backup failed on nas",
+		},
+		{
+			name: "empty title falls back to defaults",
+			app:  server.App{Name: "backup", AlertnameFromTitle: true},
+			want: config.DefaultAlertName,
+		},
+		{
+			name:  "blank title falls back to app alertname",
+			app:   server.App{Name: "backup", AlertName: "BackupJob", AlertnameFromTitle: true},
+			title: " \t\n",
+			want:  "BackupJob",
+		},
+		{
+			name:  "disabled keeps alertname",
+			app:   server.App{Name: "backup"},
+			title: "Backup failed",
+			want:  config.DefaultAlertName,
+		},
+	}
+
+	for _, testCase := range cases {
+		msg := gotify.MessageRequest{Title: testCase.title, Message: "m"}
+
+		alert := fwd.buildAlert(testCase.app, msg, server.MessageID{Seq: 1}, ruleActions{}, time.Now())
+		if alert.Labels["alertname"] != testCase.want {
+			t.Fatalf("%s: expected alertname %q, got %q", testCase.name, testCase.want, alert.Labels["alertname"])
+		}
+	}
+}
+
+func TestBuildAlertTruncatesLongValuesExceptIdentityLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			ValueMaxLen:          64,
+			Labels:               map[string]string{"team": "{{ .Message }}"},
+		},
+	}
+
+	metricsCollector := metrics.New()
+
+	fwd, err := buildForwarder(cfg, nil, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	message := strings.Repeat("x", 65)
+	title := strings.Repeat("T", 65)
+
+	alert := fwd.buildAlert(
+		server.App{Name: "backup", AlertnameFromTitle: true},
+		gotify.MessageRequest{Title: title, Message: message},
+		server.MessageID{Seq: 1},
+		ruleActions{},
+		time.Now(),
+	)
+
+	description := alert.Annotations["description"]
+	if utf8.RuneCountInString(description) != 64 || !strings.HasSuffix(description, truncatedMarker) {
+		t.Fatalf("expected a 64 character description ending with %q, got %q", truncatedMarker, description)
+	}
+
+	team := alert.Labels["team"]
+	if utf8.RuneCountInString(team) != 64 || !strings.HasSuffix(team, truncatedMarker) {
+		t.Fatalf("expected a 64 character templated label ending with %q, got %q", truncatedMarker, team)
+	}
+
+	if alert.Labels["alertname"] != title {
+		t.Fatalf("expected the alertname label kept whole, got %q", alert.Labels["alertname"])
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// description, summary (the title) and the team label are cut; the alertname label is not.
+	want := `gotilert_truncated_values_total{app="backup"} 3`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("expected %q in metrics output:\n%s", want, rec.Body.String())
+	}
+}
+
+func TestBuildAlertTemplatesNumericExtras(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			Labels:               map[string]string{"load": `{{ if gt .Extras.queue 5 }}high{{ else }}low{{ end }}`},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	msg, err := gotify.ParseMessageRequest(httptest.NewRequest(http.MethodPost, "/message",
+		strings.NewReader(`{"message":"m","extras":{"queue":12}}`)), gotify.ParseOptions{DefaultJSON: true})
+	if err != nil {
+		t.Fatalf("ParseMessageRequest: %v", err)
+	}
+
+	alert := fwd.buildAlert(server.App{Name: "backup"}, msg, server.MessageID{Seq: 1}, ruleActions{}, time.Now())
+	if alert.Labels["load"] != "high" {
+		t.Fatalf("expected numeric comparison on extras, got %q", alert.Labels["load"])
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/config"
+)
+
+func TestResolveAppAcceptsEveryTokenOfAnApp(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+		Apps: map[string]config.AppConfig{
+			"old-token": {
+				AppName: "backup",
+				Tokens:  []string{"new-token", config.HashedTokenPrefix + config.HashToken("hashed-token")},
+			},
+		},
+	}
+
+	resolve, err := newResolveAppFunc(cfg)
+	if err != nil {
+		t.Fatalf("newResolveAppFunc: %v", err)
+	}
+
+	for _, token := range []string{"old-token", "new-token", "hashed-token"} {
+		app, ok := resolve(token)
+		if !ok || app.Name != "backup" {
+			t.Fatalf("expected %q to resolve to backup, got %+v (ok=%v)", token, app, ok)
+		}
+	}
+
+	_, ok := resolve("other-token")
+	if ok {
+		t.Fatalf("expected unknown token to be rejected")
+	}
+}
+
+func TestResolveAppUsesConfiguredAppID(t *testing.T) {
+	t.Parallel()
+
+	appID := uint32(7)
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+		Apps: map[string]config.AppConfig{
+			"fixed-token":  {AppName: "backup", AppID: &appID},
+			"hashed-token": {AppName: "media"},
+		},
+	}
+
+	resolve, err := newResolveAppFunc(cfg)
+	if err != nil {
+		t.Fatalf("newResolveAppFunc: %v", err)
+	}
+
+	app, _ := resolve("fixed-token")
+	if app.ID != appID {
+		t.Fatalf("expected app id %d, got %d", appID, app.ID)
+	}
+
+	app, _ = resolve("hashed-token")
+	if app.ID != appIDFromName("media") {
+		t.Fatalf("expected hashed app id %d, got %d", appIDFromName("media"), app.ID)
+	}
+}
+
+func TestResolveAppFallsBackToCatchAllApp(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+		Apps: map[string]config.AppConfig{
+			"known-token":        {AppName: "backup"},
+			config.CatchAllToken: {AppName: "lab", SeverityFromPriority: map[int]string{0: "warning"}},
+		},
+	}
+
+	resolve, err := newResolveAppFunc(cfg)
+	if err != nil {
+		t.Fatalf("newResolveAppFunc: %v", err)
+	}
+
+	app, ok := resolve("known-token")
+	if !ok || app.Name != "backup" {
+		t.Fatalf("expected known token to resolve to backup, got %+v (ok=%v)", app, ok)
+	}
+
+	app, ok = resolve("unknown-token")
+	if !ok || app.Name != "lab" {
+		t.Fatalf("expected unknown token to resolve to the catch-all app, got %+v (ok=%v)", app, ok)
+	}
+
+	if app.SeverityFromPriority[0] != "warning" {
+		t.Fatalf("expected catch-all severity map to apply, got %v", app.SeverityFromPriority)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestAsyncForwardIsCountedOnDelivery(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			Async: config.AsyncConfig{Enabled: true, QueueSize: 1, Workers: 1},
+		},
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	release := make(chan struct{})
+	post := func(context.Context, []alertmanager.Alert) error {
+		<-release
+
+		return nil
+	}
+
+	metricsCollector := metrics.New()
+
+	queue, err := newForwardQueue(cfg, post, metricsCollector)
+	if err != nil {
+		t.Fatalf("newForwardQueue: %v", err)
+	}
+
+	fwd, err := buildForwarder(cfg, queue.PostAlerts, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	fwd.queued = func(context.Context) bool { return true }
+
+	err = fwd.forward(context.Background(), server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	want := `gotilert_forwarded_alerts_total{app="backup"} 1`
+	if strings.Contains(scrapeMetrics(metricsCollector), want) {
+		t.Fatal("expected the forward not to be counted while it is only queued")
+	}
+
+	close(release)
+
+	err = queue.Close(context.Background())
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	output := scrapeMetrics(metricsCollector)
+	if !strings.Contains(output, want) || !strings.Contains(output, `gotilert_forward_duration_seconds_count{app="backup"} 1`) {
+		t.Fatalf("expected the delivered forward to be counted and observed once:\n%s", output)
+	}
+}
+
+func scrapeMetrics(metricsCollector *metrics.Metrics) string {
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	return rec.Body.String()
+}
@@ -0,0 +1,130 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/audit"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// pendingAudit is the audit record of a forward waiting for the outcome of its post, with
+// the status of the Alertmanager response that ended it.
+type pendingAudit struct {
+	log    *audit.Log
+	record *audit.Record
+	status alertmanager.ResponseStatus
+}
+
+type pendingAuditKey struct{}
+
+// startAudit prepares the audit record of a forward, if enabled, and attaches it to ctx so
+// a queued post can complete it once delivered (see completeQueuedAudit).
+func (fwd *forwarder) startAudit(
+	ctx context.Context,
+	appName string,
+	priority int,
+	alert *alertmanager.Alert,
+	messageIdentifier server.MessageID,
+) context.Context {
+	if fwd.audit == nil {
+		return ctx
+	}
+
+	pending := &pendingAudit{
+		log: fwd.audit,
+		record: &audit.Record{
+			RequestID:  server.RequestIDFromContext(ctx),
+			App:        appName,
+			Priority:   priority,
+			Severity:   alert.Labels["severity"],
+			GotilertID: messageIdentifier.String(),
+		},
+	}
+
+	ctx = alertmanager.WithResponseStatus(ctx, &pending.status)
+
+	return context.WithValue(ctx, pendingAuditKey{}, pending)
+}
+
+// finishAudit writes the audit record of a synchronous post. A post accepted by the async
+// queue is recorded by the queue once delivered, with the real upstream outcome.
+func (fwd *forwarder) finishAudit(ctx context.Context, postErr error) {
+	if postErr == nil && fwd.isQueued(ctx) {
+		return
+	}
+
+	completeAudit(ctx, postErr)
+}
+
+// completeAudit writes the pending audit record attached to ctx, if any, with the outcome
+// of its post.
+func completeAudit(ctx context.Context, postErr error) {
+	pending, _ := ctx.Value(pendingAuditKey{}).(*pendingAudit)
+	if pending == nil {
+		return
+	}
+
+	record := pending.record
+	record.Time = time.Now().UTC()
+	record.Result = audit.ResultForwarded
+	record.UpstreamStatus = pending.status.Code()
+
+	if postErr != nil {
+		record.Result = audit.ResultFailed
+		record.Error = postErr.Error()
+
+		var statusErr alertmanager.HTTPStatusError
+		if errors.As(postErr, &statusErr) {
+			record.UpstreamStatus = statusErr.StatusCode()
+		}
+	}
+
+	pending.log.Write(record)
+}
+
+// auditErrorReporter counts every audit log failure and logs the first one, so a full disk
+// neither goes unnoticed until shutdown nor floods the logs.
+func auditErrorReporter(path string, metricsCollector *metrics.Metrics) func(err error) {
+	var logged atomic.Bool
+
+	return func(err error) {
+		metricsCollector.IncAuditError()
+
+		if logged.CompareAndSwap(false, true) {
+			logger.L().Error("audit log write failed; further failures are only counted in gotilert_audit_errors_total",
+				"err", err,
+				"path", path,
+			)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/audit"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderWritesAuditLinePerForward(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			writer.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			URL:     upstream.URL,
+			Timeout: config.Duration{Duration: 2 * time.Second},
+		},
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info", 8: "critical"},
+		},
+	}
+
+	client, err := newAlertmanagerClient(cfg, metrics.New())
+	if err != nil {
+		t.Fatalf("newAlertmanagerClient: %v", err)
+	}
+
+	fwd, err := buildForwarder(cfg, client.PostAlerts, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	var output bytes.Buffer
+
+	fwd.audit = audit.New(&output, nil)
+
+	app := server.App{Name: "backup"}
+
+	err = fwd.forward(context.Background(), app, gotify.MessageRequest{Message: "ok", Priority: 8}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	failing.Store(true)
+
+	err = fwd.forward(context.Background(), app, gotify.MessageRequest{Message: "rejected"}, server.MessageID{Seq: 2})
+	if err == nil {
+		t.Fatalf("expected the rejected forward to fail")
+	}
+
+	err = fwd.audit.Close()
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit lines, got %d:\n%s", len(lines), output.String())
+	}
+
+	var forwarded, failed audit.Record
+
+	if json.Unmarshal([]byte(lines[0]), &forwarded) != nil || json.Unmarshal([]byte(lines[1]), &failed) != nil {
+		t.Fatalf("expected JSON audit lines, got:\n%s", output.String())
+	}
+
+	if forwarded.App != "backup" || forwarded.Priority != 8 || forwarded.Severity != "critical" ||
+		forwarded.Result != audit.ResultForwarded || forwarded.UpstreamStatus != http.StatusOK || forwarded.GotilertID == "" {
+		t.Fatalf("unexpected forwarded record: %+v", forwarded)
+	}
+
+	if failed.Result != audit.ResultFailed || failed.UpstreamStatus != http.StatusBadRequest || failed.Error == "" {
+		t.Fatalf("unexpected failed record: %+v", failed)
+	}
+}
+
+func TestAsyncForwardIsAuditedOnDelivery(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			URL:     upstream.URL,
+			Timeout: config.Duration{Duration: 2 * time.Second},
+			Async:   config.AsyncConfig{Enabled: true, QueueSize: 1, Workers: 1},
+		},
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	metricsCollector := metrics.New()
+
+	client, err := newAlertmanagerClient(cfg, metricsCollector)
+	if err != nil {
+		t.Fatalf("newAlertmanagerClient: %v", err)
+	}
+
+	queue, err := newForwardQueue(cfg, client.PostAlerts, metricsCollector)
+	if err != nil {
+		t.Fatalf("newForwardQueue: %v", err)
+	}
+
+	fwd, err := buildForwarder(cfg, queue.PostAlerts, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	var output bytes.Buffer
+
+	fwd.audit = audit.New(&output, nil)
+	fwd.queued = func(context.Context) bool { return true }
+
+	err = fwd.forward(context.Background(), server.App{Name: "backup"}, gotify.MessageRequest{Message: "m"}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	err = queue.Close(context.Background())
+	if err != nil {
+		t.Fatalf("queue Close: %v", err)
+	}
+
+	err = fwd.audit.Close()
+	if err != nil {
+		t.Fatalf("audit Close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 audit line, got %d:\n%s", len(lines), output.String())
+	}
+
+	var record audit.Record
+
+	err = json.Unmarshal([]byte(lines[0]), &record)
+	if err != nil {
+		t.Fatalf("expected a JSON audit line, got %q", lines[0])
+	}
+
+	if record.Result != audit.ResultFailed || record.UpstreamStatus != http.StatusBadGateway || record.Error == "" {
+		t.Fatalf("expected the delivery failure to be audited, got %+v", record)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/metrics"
+)
+
+// maxPendingResolves bounds how many alerts can wait for an auto-resolution at once.
+const maxPendingResolves = 10000
+
+// pendingResolve is one scheduled resolution of the alerts sharing a fingerprint; its
+// identity tells a fired timer whether it was superseded by a later schedule.
+type pendingResolve struct {
+	timer   *time.Timer
+	appName string
+	alerts  []alertmanager.Alert
+}
+
+// autoResolver posts a resolved copy of forwarded alerts after defaults.autoResolveAfter.
+// At most one resolution is pending per alert fingerprint (its label set without the
+// per-message gotilert_id): re-firing the same alert restarts the wait for every copy
+// instead of queueing a second resolution.
+type autoResolver struct {
+	post    alertmanager.PostFunc
+	metrics *metrics.Metrics
+
+	// ctx is cancelled by Close to abort resolutions being posted.
+	ctx    context.Context //nolint:containedctx // lifetime of the scheduler, cancelled by Close.
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	pending map[string]*pendingResolve
+	// held counts the alerts across pending, bounded by maxPending.
+	held       int
+	maxPending int
+	closed     bool
+	posting    sync.WaitGroup
+}
+
+func newAutoResolver(post alertmanager.PostFunc, metricsCollector *metrics.Metrics) *autoResolver {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &autoResolver{
+		post:       post,
+		metrics:    metricsCollector,
+		ctx:        ctx,
+		cancel:     cancel,
+		pending:    make(map[string]*pendingResolve),
+		maxPending: maxPendingResolves,
+	}
+}
+
+// schedule resolves alert after delay, together with the copies of it pending under the same
+// fingerprint, whose wait restarts. When maxPending alerts are already waiting the alert is
+// dropped and left to end at its own endsAt.
+func (resolver *autoResolver) schedule(appName string, alert alertmanager.Alert, delay time.Duration) {
+	if resolver == nil || delay <= 0 {
+		return
+	}
+
+	fingerprint := resolveFingerprint(alert.Labels)
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	if resolver.closed {
+		return
+	}
+
+	alerts := []alertmanager.Alert{alert}
+	held := resolver.held
+
+	previous, ok := resolver.pending[fingerprint]
+	if ok {
+		alerts = append(withoutAlert(previous.alerts, alert.Labels), alert)
+		held -= len(previous.alerts)
+	}
+
+	if held+len(alerts) > resolver.maxPending {
+		resolver.metrics.IncAutoResolve(appName, metrics.AutoResolveDropped)
+		logger.L().Warn("auto-resolve dropped: too many pending resolutions",
+			"app", appName,
+			"alertname", alert.Labels["alertname"],
+			"pending", resolver.held,
+		)
+
+		return
+	}
+
+	if ok {
+		previous.timer.Stop()
+	}
+
+	entry := &pendingResolve{appName: appName, alerts: alerts}
+	entry.timer = time.AfterFunc(delay, func() {
+		resolver.fire(fingerprint, entry)
+	})
+	resolver.pending[fingerprint] = entry
+	resolver.held = held + len(alerts)
+
+	resolver.metrics.IncAutoResolve(appName, metrics.AutoResolveScheduled)
+}
+
+// cancelResolved drops the pending auto-resolutions of alerts that were resolved explicitly.
+func (resolver *autoResolver) cancelResolved(appName string, resolved []alertmanager.Alert) {
+	if resolver == nil {
+		return
+	}
+
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+
+	for _, alert := range resolved {
+		fingerprint := resolveFingerprint(alert.Labels)
+
+		entry, ok := resolver.pending[fingerprint]
+		if !ok {
+			continue
+		}
+
+		kept := withoutAlert(entry.alerts, alert.Labels)
+		if len(kept) == len(entry.alerts) {
+			continue
+		}
+
+		resolver.held -= len(entry.alerts) - len(kept)
+		entry.alerts = kept
+
+		if len(kept) == 0 {
+			entry.timer.Stop()
+			delete(resolver.pending, fingerprint)
+		}
+
+		resolver.metrics.IncAutoResolve(appName, metrics.AutoResolveCancelled)
+	}
+}
+
+func (resolver *autoResolver) fire(fingerprint string, entry *pendingResolve) {
+	resolver.mutex.Lock()
+
+	if resolver.closed || resolver.pending[fingerprint] != entry {
+		resolver.mutex.Unlock()
+
+		return
+	}
+
+	delete(resolver.pending, fingerprint)
+	resolver.held -= len(entry.alerts)
+	resolver.posting.Add(1)
+
+	now := time.Now().UTC()
+	resolved := make([]alertmanager.Alert, 0, len(entry.alerts))
+
+	for _, alert := range entry.alerts {
+		alert.EndsAt = now
+		resolved = append(resolved, alert)
+	}
+
+	resolver.mutex.Unlock()
+
+	defer resolver.posting.Done()
+
+	appName := entry.appName
+	alertname := resolved[0].Labels["alertname"]
+
+	err := resolver.post(withAppName(resolver.ctx, appName), resolved)
+	if err != nil {
+		resolver.metrics.IncAutoResolve(appName, metrics.AutoResolveFailed)
+		logger.L().Warn("auto-resolve failed",
+			"app", appName,
+			"alertname", alertname,
+			"err", err,
+		)
+
+		return
+	}
+
+	resolver.metrics.IncAutoResolve(appName, metrics.AutoResolveSent)
+	logger.L().Debug("auto-resolved alert",
+		"app", appName,
+		"alertname", alertname,
+		"count", len(resolved),
+	)
+}
+
+// Close drops pending resolutions and aborts those being posted. Alertmanager still resolves
+// the dropped alerts at their own endsAt.
+func (resolver *autoResolver) Close() {
+	if resolver == nil {
+		return
+	}
+
+	resolver.mutex.Lock()
+	resolver.closed = true
+
+	for fingerprint, entry := range resolver.pending {
+		entry.timer.Stop()
+		delete(resolver.pending, fingerprint)
+	}
+
+	resolver.held = 0
+	resolver.mutex.Unlock()
+
+	resolver.cancel()
+	resolver.posting.Wait()
+}
+
+// withoutAlert returns alerts minus the one with exactly these labels.
+func withoutAlert(alerts []alertmanager.Alert, labels map[string]string) []alertmanager.Alert {
+	return slices.DeleteFunc(slices.Clone(alerts), func(alert alertmanager.Alert) bool {
+		return maps.Equal(alert.Labels, labels)
+	})
+}
+
+// resolveFingerprint identifies an alert across re-fires: its full label set, the way
+// Alertmanager does, minus the gotilert_id that is unique to every message.
+func resolveFingerprint(labels map[string]string) string {
+	var builder strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		if name == gotilertIDKey {
+			continue
+		}
+
+		builder.WriteString(name)
+		builder.WriteByte(0)
+		builder.WriteString(labels[name])
+		builder.WriteByte(0)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,260 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func recordingPost() (alertmanager.PostFunc, chan []alertmanager.Alert) {
+	posted := make(chan []alertmanager.Alert, 10)
+
+	return func(_ context.Context, alerts []alertmanager.Alert) error {
+		posted <- alerts
+
+		return nil
+	}, posted
+}
+
+func TestForwarderAutoResolvesAfterDelay(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info", 5: "warning"},
+			AutoResolveAfter:     config.Duration{Duration: 20 * time.Millisecond},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, post, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	fwd.autoResolve = newAutoResolver(post, nil)
+	defer fwd.autoResolve.Close()
+
+	msg := gotify.MessageRequest{Title: "Backup failed", Priority: 5}
+
+	err = fwd.forward(context.Background(), server.App{Name: "backup"}, msg, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	fired := <-posted
+
+	select {
+	case resolved := <-posted:
+		if len(resolved) != 1 {
+			t.Fatalf("expected a single resolved alert, got %v", resolved)
+		}
+
+		if resolved[0].Labels["alertname"] != fired[0].Labels["alertname"] {
+			t.Fatalf("expected the fired labels, got %v", resolved[0].Labels)
+		}
+
+		if !resolved[0].EndsAt.Before(fired[0].EndsAt) || resolved[0].EndsAt.After(time.Now()) {
+			t.Fatalf("expected endsAt in the past, got %v", resolved[0].EndsAt)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected an auto-resolution, got none")
+	}
+}
+
+func TestAutoResolverReschedulesSameAlert(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	resolver := newAutoResolver(post, nil)
+	defer resolver.Close()
+
+	alert := alertmanager.Alert{Labels: map[string]string{"alertname": "Backup"}}
+
+	resolver.schedule("backup", alert, 50*time.Millisecond)
+	resolver.schedule("backup", alert, 50*time.Millisecond)
+
+	<-posted
+
+	select {
+	case alerts := <-posted:
+		t.Fatalf("expected one resolution per alert, got another: %v", alerts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAutoResolverCloseDropsPending(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	resolver := newAutoResolver(post, nil)
+	resolver.schedule("backup", alertmanager.Alert{Labels: map[string]string{"alertname": "Backup"}}, 20*time.Millisecond)
+	resolver.Close()
+
+	// Scheduling after Close is ignored too.
+	resolver.schedule("backup", alertmanager.Alert{Labels: map[string]string{"alertname": "Other"}}, time.Millisecond)
+
+	select {
+	case alerts := <-posted:
+		t.Fatalf("expected no resolution after Close, got %v", alerts)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestForwarderAutoResolvesRefiredTitleOnce(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			AutoResolveAfter:     config.Duration{Duration: 50 * time.Millisecond},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, post, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	fwd.autoResolve = newAutoResolver(post, nil)
+	defer fwd.autoResolve.Close()
+
+	msg := gotify.MessageRequest{Title: "Backup failed"}
+
+	for seq := uint64(1); seq <= 2; seq++ {
+		err = fwd.forward(context.Background(), server.App{Name: "backup"}, msg, server.MessageID{Seq: seq})
+		if err != nil {
+			t.Fatalf("forward: %v", err)
+		}
+	}
+
+	first, second := <-posted, <-posted
+
+	select {
+	case resolved := <-posted:
+		if len(resolved) != 2 {
+			t.Fatalf("expected both copies resolved together, got %v", resolved)
+		}
+
+		ids := map[string]bool{resolved[0].Labels[gotilertIDKey]: true, resolved[1].Labels[gotilertIDKey]: true}
+		if !ids[first[0].Labels[gotilertIDKey]] || !ids[second[0].Labels[gotilertIDKey]] {
+			t.Fatalf("expected the fired gotilert_id labels, got %v", resolved)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected an auto-resolution, got none")
+	}
+
+	select {
+	case alerts := <-posted:
+		t.Fatalf("expected a single resolution for the re-fired title, got another: %v", alerts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestExplicitResolveCancelsAutoResolve(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info", 1: "info"},
+			AutoResolveAfter:     config.Duration{Duration: 30 * time.Millisecond},
+		},
+	}
+
+	fwd, err := buildForwarder(cfg, post, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	fwd.autoResolve = newAutoResolver(post, nil)
+	defer fwd.autoResolve.Close()
+
+	resolvePriority := 1
+	app := server.App{Name: "backup", Resolve: server.ResolveTrigger{Priority: &resolvePriority}}
+
+	err = fwd.forward(context.Background(), app, gotify.MessageRequest{Title: "Backup failed"}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	err = fwd.forward(context.Background(), app, gotify.MessageRequest{Title: "Backup failed", Priority: 1}, server.MessageID{Seq: 2})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	<-posted
+	<-posted
+
+	select {
+	case alerts := <-posted:
+		t.Fatalf("expected the explicit resolution to cancel the auto-resolution, got %v", alerts)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAutoResolverDropsBeyondMaxPending(t *testing.T) {
+	t.Parallel()
+
+	post, posted := recordingPost()
+
+	resolver := newAutoResolver(post, nil)
+	resolver.maxPending = 1
+
+	defer resolver.Close()
+
+	resolver.schedule("backup", alertmanager.Alert{Labels: map[string]string{"alertname": "Backup"}}, 20*time.Millisecond)
+	resolver.schedule("backup", alertmanager.Alert{Labels: map[string]string{"alertname": "Other"}}, 20*time.Millisecond)
+
+	alerts := <-posted
+	if len(alerts) != 1 || alerts[0].Labels["alertname"] != "Backup" {
+		t.Fatalf("expected only the first alert resolved, got %v", alerts)
+	}
+
+	select {
+	case alerts := <-posted:
+		t.Fatalf("expected the alert beyond maxPending to be dropped, got %v", alerts)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
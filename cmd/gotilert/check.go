@@ -0,0 +1,137 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// configCheck is one step of --check-config. It returns a short detail for the summary line.
+type configCheck struct {
+	name string
+	run  func(cfg *config.Config) (string, error)
+}
+
+// checkConfig builds every component derived from an already loaded and validated config
+// without binding a port or contacting Alertmanager, printing one line per check:
+//
+//	ok <check> <detail>
+//	fail <check> <error>
+func checkConfig(stdout io.Writer, configFile string, cfg *config.Config) error {
+	if stdout == nil {
+		return ErrNilStdoutWriter
+	}
+
+	checks := []configCheck{
+		{name: "config", run: func(*config.Config) (string, error) { return "path=" + configFile, nil }},
+		{name: "defaults", run: checkDefaults},
+		{name: "alertmanager", run: checkAlertmanager},
+		{name: "apps", run: checkApps},
+		{name: "server", run: checkServer},
+	}
+
+	for _, check := range checks {
+		detail, err := check.run(cfg)
+		if err != nil {
+			_, _ = fmt.Fprintf(stdout, "fail %s %v\n", check.name, err)
+
+			return fmt.Errorf("%w: %s: %w", ErrConfigCheckFailed, check.name, err)
+		}
+
+		_, err = fmt.Fprintf(stdout, "ok %s %s\n", check.name, detail)
+		if err != nil {
+			return fmt.Errorf("print config check: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func checkDefaults(cfg *config.Config) (string, error) {
+	priorities := make([]int, 0, len(cfg.Defaults.SeverityFromPriority))
+	for priority := range cfg.Defaults.SeverityFromPriority {
+		priorities = append(priorities, priority)
+	}
+
+	slices.Sort(priorities)
+
+	severities := make([]string, 0, len(priorities))
+	for _, priority := range priorities {
+		severities = append(severities,
+			strconv.Itoa(priority)+":"+cfg.Defaults.SeverityFromPriority[priority])
+	}
+
+	return fmt.Sprintf("alertname=%s ttl=%s severityFromPriority=%s priorityRange=%d-%d",
+		cfg.Defaults.AlertName,
+		cfg.Defaults.TTL.Duration,
+		strings.Join(severities, ","),
+		cfg.Defaults.PriorityRange.Min,
+		cfg.Defaults.PriorityRange.MaxPriority(),
+	), nil
+}
+
+// checkAlertmanager builds the client, which loads credential and certificate files.
+func checkAlertmanager(cfg *config.Config) (string, error) {
+	_, err := newAlertmanagerClient(cfg, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return "urls=" + strconv.Itoa(len(cfg.Alertmanager.PeerURLs())), nil
+}
+
+func checkApps(cfg *config.Config) (string, error) {
+	_, err := newResolveAppFunc(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = newForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		return "", err
+	}
+
+	return "count=" + strconv.Itoa(len(cfg.Apps)), nil
+}
+
+// checkServer builds (but does not start) the HTTP server, which loads the TLS files.
+func checkServer(cfg *config.Config) (string, error) {
+	_, err := server.New(&server.Options{
+		Addr: cfg.Server.ListenAddr,
+		TLS:  serverTLSOptions(&cfg.Server.TLS),
+	})
+	if err != nil {
+		return "", fmt.Errorf("create http server: %w", err)
+	}
+
+	return fmt.Sprintf("listenAddr=%s tls=%t", cfg.Server.ListenAddr, cfg.Server.TLS.Enabled()), nil
+}
@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const checkConfigYAML = `
+alertmanager:
+  url: "http://localhost:9093"
+defaults:
+  ttl: "5m"
+  severityFromPriority:
+    0: info
+apps:
+  "TOKEN":
+    appName: "app"
+`
+
+func TestCheckConfigPrintsOneLinePerCheck(t *testing.T) {
+	configFile := writeCheckConfig(t, checkConfigYAML)
+
+	var stdout bytes.Buffer
+
+	err := run([]string{"--config.file", configFile, "--check-config"}, &stdout, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	for _, prefix := range []string{"ok config ", "ok defaults ", "ok alertmanager ", "ok apps count=1", "ok server "} {
+		if !hasLineWithPrefix(lines, prefix) {
+			t.Fatalf("expected a line starting with %q, got:\n%s", prefix, stdout.String())
+		}
+	}
+}
+
+func TestCheckConfigFailsWithoutStartingServer(t *testing.T) {
+	configFile := writeCheckConfig(t, checkConfigYAML+`
+server:
+  tls:
+    certFile: "/nonexistent/cert.pem"
+    keyFile: "/nonexistent/key.pem"
+`)
+
+	var stdout bytes.Buffer
+
+	err := run([]string{"--config.file", configFile, "--check-config"}, &stdout, &bytes.Buffer{})
+	if !errors.Is(err, ErrConfigCheckFailed) {
+		t.Fatalf("expected ErrConfigCheckFailed, got %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "fail server ") {
+		t.Fatalf("expected a failed server check, got:\n%s", stdout.String())
+	}
+}
+
+func writeCheckConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "gotilert.yaml")
+
+	err := os.WriteFile(configFile, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	return configFile
+}
+
+func hasLineWithPrefix(lines []string, prefix string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/metrics"
+)
+
+func TestConcurrencyLimitStillBatchesConcurrentForwards(t *testing.T) {
+	t.Parallel()
+
+	var posts, received atomic.Int64
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		var alerts []alertmanager.Alert
+
+		err := json.NewDecoder(request.Body).Decode(&alerts)
+		if err != nil {
+			t.Errorf("decode alerts: %v", err)
+		}
+
+		posts.Add(1)
+		received.Add(int64(len(alerts)))
+		responseWriter.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			URL:            upstream.URL,
+			Timeout:        config.Duration{Duration: 2 * time.Second},
+			Batching:       config.BatchingConfig{Window: config.Duration{Duration: 200 * time.Millisecond}},
+			MaxConcurrency: 1,
+		},
+	}
+
+	metricsCollector := metrics.New()
+
+	client, err := newAlertmanagerClient(cfg, metricsCollector)
+	if err != nil {
+		t.Fatalf("newAlertmanagerClient: %v", err)
+	}
+
+	postAlerts, batcher, err := newPostAlertsFunc(cfg, func() *alertmanager.Client { return client }, metricsCollector)
+	if err != nil {
+		t.Fatalf("newPostAlertsFunc: %v", err)
+	}
+
+	defer func() { _ = batcher.Close(context.Background()) }()
+
+	const forwards = 5
+
+	var waitGroup sync.WaitGroup
+
+	for index := range forwards {
+		waitGroup.Go(func() {
+			err := postAlerts(context.Background(), []alertmanager.Alert{{Labels: map[string]string{"index": strconv.Itoa(index)}}})
+			if err != nil {
+				t.Errorf("postAlerts: %v", err)
+			}
+		})
+	}
+
+	waitGroup.Wait()
+
+	if posts.Load() != 1 || received.Load() != forwards {
+		t.Fatalf("expected %d alerts coalesced into 1 POST, got %d alerts in %d POSTs", forwards, received.Load(), posts.Load())
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestClientTimeoutHeaderAbortsUpstreamRetries(t *testing.T) {
+	t.Parallel()
+
+	var requestCount atomic.Int32
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		requestCount.Add(1)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			URL:     upstream.URL,
+			Timeout: config.Duration{Duration: 10 * time.Second},
+			Retry: config.RetryConfig{
+				MaxAttempts:    10,
+				InitialBackoff: config.Duration{Duration: 500 * time.Millisecond},
+				MaxBackoff:     config.Duration{Duration: 500 * time.Millisecond},
+			},
+		},
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	metricsCollector := metrics.New()
+
+	client, err := newAlertmanagerClient(cfg, metricsCollector)
+	if err != nil {
+		t.Fatalf("newAlertmanagerClient: %v", err)
+	}
+
+	forward, err := newForwarder(cfg, client.PostAlerts, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("newForwarder: %v", err)
+	}
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp:     func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+		ForwardMessage: forward,
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader("message=hello"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+	req.Header.Set("X-Gotilert-Timeout", "100ms")
+
+	start := time.Now()
+
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the client deadline to cut retries short, took %s", elapsed)
+	}
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if got := requestCount.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream attempt before the deadline, got %d", got)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderRecordsDetails(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info", 8: "critical"},
+			MinForwardPriority:   2,
+		},
+	}
+
+	post := func(context.Context, []alertmanager.Alert) error { return nil }
+
+	fwd, err := buildForwarder(cfg, post, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	forward := func(msg gotify.MessageRequest) server.ForwardDetails {
+		var details server.ForwardDetails
+
+		ctx := server.ContextWithForwardDetails(context.Background(), &details)
+
+		err := fwd.forward(ctx, server.App{Name: "nas"}, msg, server.MessageID{Seq: 1})
+		if err != nil {
+			t.Fatalf("forward: %v", err)
+		}
+
+		return details
+	}
+
+	details := forward(gotify.MessageRequest{Title: "Disk", Message: "failing", Priority: 9})
+	if details.Severity != "critical" || details.TTL != "1h0m0s" || details.Labels["app"] != "nas" ||
+		details.Annotations["description"] != "failing" {
+		t.Fatalf("expected recorded alert details, got %+v", details)
+	}
+
+	details = forward(gotify.MessageRequest{Message: "noise", Priority: 1})
+	if details.Dropped != metrics.DropBelowMinPriority || details.Labels != nil {
+		t.Fatalf("expected a dropped message without labels, got %+v", details)
+	}
+}
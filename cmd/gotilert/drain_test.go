@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownDrainsBeforeStoppingServer(t *testing.T) {
+	t.Parallel()
+
+	const drainDelay = 50 * time.Millisecond
+
+	draining := &atomic.Bool{}
+	ready := readyUnlessDraining(draining, func() (bool, string) { return true, "" })
+
+	if ok, _ := ready(); !ok {
+		t.Fatalf("expected ready before shutdown")
+	}
+
+	svc := &service{
+		httpServer:      &http.Server{ReadHeaderTimeout: time.Second},
+		shutdownTimeout: time.Second,
+		draining:        draining,
+		drainDelay:      drainDelay,
+	}
+
+	start := time.Now()
+
+	var (
+		elapsedAtShutdown time.Duration
+		readyAtShutdown   bool
+		reason            string
+	)
+
+	done := make(chan struct{})
+
+	svc.httpServer.RegisterOnShutdown(func() {
+		elapsedAtShutdown = time.Since(start)
+		readyAtShutdown, reason = ready()
+
+		close(done)
+	})
+
+	err := svc.shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	<-done
+
+	if readyAtShutdown {
+		t.Fatalf("expected not ready when the server stops, got ready")
+	}
+
+	if reason != "shutting down" {
+		t.Fatalf("expected reason %q, got %q", "shutting down", reason)
+	}
+
+	if elapsedAtShutdown < drainDelay {
+		t.Fatalf("expected server shutdown after %s drain, got %s", drainDelay, elapsedAtShutdown)
+	}
+}
+
+var errExporterDown = errors.New("exporter down")
+
+func TestShutdownRunsEveryStepWhenServerShutdownFails(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(entered)
+		<-release
+	}))
+	defer upstream.Close()
+	defer close(release)
+
+	go func() {
+		request, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, upstream.URL, nil)
+
+		response, err := http.DefaultClient.Do(request)
+		if err == nil {
+			_ = response.Body.Close()
+		}
+	}()
+
+	<-entered
+
+	var tracingFlushed atomic.Bool
+
+	svc := &service{
+		httpServer:      upstream.Config,
+		shutdownTimeout: 20 * time.Millisecond,
+		draining:        &atomic.Bool{},
+		shutdownTracing: func(context.Context) error {
+			tracingFlushed.Store(true)
+
+			return errExporterDown
+		},
+	}
+
+	// The in-flight request outlives the shutdown timeout, so server shutdown fails.
+	err := svc.shutdown(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errExporterDown) {
+		t.Fatalf("expected both the server and the tracing error, got %v", err)
+	}
+
+	if !tracingFlushed.Load() {
+		t.Fatal("expected tracing to be flushed after a failed server shutdown")
+	}
+}
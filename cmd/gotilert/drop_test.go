@@ -0,0 +1,104 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderDropsMessagesBelowMinForwardPriority(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			PriorityRange:        config.PriorityRange{Min: 2},
+			MinForwardPriority:   3,
+		},
+	}
+
+	var posted []alertmanager.Alert
+
+	post := func(_ context.Context, alerts []alertmanager.Alert) error {
+		posted = append(posted, alerts...)
+
+		return nil
+	}
+
+	metricsCollector := metrics.New()
+
+	forward, err := newForwarder(cfg, post, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("newForwarder: %v", err)
+	}
+
+	zero := 0
+	lenient := server.App{Name: "nas", MinForwardPriority: &zero}
+
+	// Priority 0 is clamped to 2 first, which is still below 3.
+	for _, message := range []struct {
+		app      server.App
+		priority int
+	}{
+		{app: server.App{Name: "backup"}, priority: 0},
+		{app: server.App{Name: "backup"}, priority: 3},
+		{app: lenient, priority: 0},
+	} {
+		err = forward(
+			context.Background(),
+			message.app,
+			gotify.MessageRequest{Message: "m", Priority: message.priority},
+			server.MessageID{Seq: 1},
+		)
+		if err != nil {
+			t.Fatalf("expected dropped messages to succeed, got %v", err)
+		}
+	}
+
+	if len(posted) != 2 || posted[0].Labels["priority"] != "3" || posted[1].Labels["app"] != "nas" {
+		t.Fatalf("expected only the priority 3 and per-app override alerts to be posted, got %+v", posted)
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `gotilert_dropped_total{app="backup",reason="below_min_priority"} 1`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("expected %q in metrics output:\n%s", want, rec.Body.String())
+	}
+}
@@ -29,4 +29,6 @@ import "errors"
 var (
 	ErrNilStdoutWriter   = errors.New("stdout writer is nil")
 	ErrConfigFileMissing = errors.New("config file is missing")
+	ErrConfigCheckFailed = errors.New("config check failed")
+	ErrStartupNotReady   = errors.New("alertmanager not ready before startup timeout")
 )
@@ -0,0 +1,132 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+const defaultUpstreamFailureWindow = 5 * time.Minute
+
+// upstreamHealth trips /healthz after threshold consecutive Alertmanager failures that all
+// happened within window. A success resets it, and it recovers on its own once the streak
+// is older than window (e.g. when traffic stops).
+type upstreamHealth struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mutex sync.Mutex
+	// failures holds the times of the current streak, capped at threshold entries.
+	failures []time.Time
+}
+
+func newUpstreamHealth(threshold int, window time.Duration) *upstreamHealth {
+	if window <= 0 {
+		window = defaultUpstreamFailureWindow
+	}
+
+	return &upstreamHealth{threshold: threshold, window: window, now: time.Now}
+}
+
+// wrap records the outcome of every post whose ctx is still live once it returns: a caller
+// that gave up (e.g. a short X-Gotilert-Timeout) says nothing about Alertmanager. It returns
+// post unchanged when the check is disabled.
+func (health *upstreamHealth) wrap(post alertmanager.PostFunc) alertmanager.PostFunc {
+	if health.threshold <= 0 {
+		return post
+	}
+
+	return func(ctx context.Context, alerts []alertmanager.Alert) error {
+		err := post(ctx, alerts)
+		if ctx.Err() == nil {
+			health.record(err)
+		}
+
+		return err
+	}
+}
+
+func (health *upstreamHealth) record(err error) {
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, alertmanager.ErrConcurrencyLimit) {
+		// The client went away or no slot was free; neither says anything about Alertmanager.
+		return
+	}
+
+	if err == nil {
+		health.failures = health.failures[:0]
+
+		return
+	}
+
+	health.failures = append(health.failures, health.now())
+	if len(health.failures) > health.threshold {
+		health.failures = health.failures[1:]
+	}
+}
+
+// check reports unhealthy while the streak is at least threshold long and within window.
+func (health *upstreamHealth) check() (bool, string) {
+	if health.threshold <= 0 {
+		return true, ""
+	}
+
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	if len(health.failures) < health.threshold || health.now().Sub(health.failures[0]) > health.window {
+		return true, ""
+	}
+
+	return false, fmt.Sprintf("%d consecutive alertmanager failures within %s", len(health.failures), health.window)
+}
+
+// newHealthFunc combines the opt-in liveness checks configured under server.health.
+func newHealthFunc(healthConfig config.HealthConfig, configPath string, upstream *upstreamHealth) server.HealthFunc {
+	return func() (bool, string) {
+		if healthConfig.CheckConfigFile {
+			file, err := os.Open(configPath) //nolint:gosec // the operator-provided config path.
+			if err != nil {
+				return false, fmt.Sprintf("config file unreadable: %v", err)
+			}
+
+			_ = file.Close()
+		}
+
+		return upstream.check()
+	}
+}
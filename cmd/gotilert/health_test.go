@@ -0,0 +1,174 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+)
+
+var errUpstreamDown = errors.New("upstream down")
+
+func TestUpstreamHealthTripsAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	health := newUpstreamHealth(3, time.Minute)
+	health.now = func() time.Time { return now }
+
+	health.record(errUpstreamDown)
+	health.record(errUpstreamDown)
+
+	if ok, _ := health.check(); !ok {
+		t.Fatalf("expected healthy below the threshold")
+	}
+
+	health.record(errUpstreamDown)
+
+	if ok, reason := health.check(); ok || reason == "" {
+		t.Fatalf("expected unhealthy with a reason, got ok=%v reason=%q", ok, reason)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if ok, _ := health.check(); !ok {
+		t.Fatalf("expected recovery once the streak is older than the window")
+	}
+
+	health.record(errUpstreamDown)
+	health.record(nil)
+
+	if ok, _ := health.check(); !ok {
+		t.Fatalf("expected a success to reset the streak")
+	}
+}
+
+func TestHealthFuncChecksConfigFile(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "gotilert.yaml")
+
+	err := os.WriteFile(configPath, []byte("{}"), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	healthFunc := newHealthFunc(config.HealthConfig{CheckConfigFile: true}, configPath, newUpstreamHealth(0, 0))
+
+	if ok, reason := healthFunc(); !ok {
+		t.Fatalf("expected healthy, got %q", reason)
+	}
+
+	err = os.Remove(configPath)
+	if err != nil {
+		t.Fatalf("remove config: %v", err)
+	}
+
+	if ok, _ := healthFunc(); ok {
+		t.Fatalf("expected unhealthy once the config file is gone")
+	}
+}
+
+func TestShortClientDeadlineLeavesBreakerClosedAndHealthGreen(t *testing.T) {
+	t.Parallel()
+
+	var hang atomic.Bool
+
+	hang.Store(true)
+
+	post := func(ctx context.Context, _ []alertmanager.Alert) error {
+		if hang.Load() {
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+
+		return nil
+	}
+
+	breakerPost, err := withCircuitBreaker(&config.CircuitBreakerConfig{FailureThreshold: 1}, post, nil)
+	if err != nil {
+		t.Fatalf("withCircuitBreaker: %v", err)
+	}
+
+	health := newUpstreamHealth(1, time.Minute)
+	chain := health.wrap(breakerPost)
+
+	// What X-Gotilert-Timeout: 1ms does to the forward context.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err = chain(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the client deadline to end the post, got %v", err)
+	}
+
+	if ok, reason := health.check(); !ok {
+		t.Fatalf("expected healthy after a client deadline, got %q", reason)
+	}
+
+	hang.Store(false)
+
+	err = chain(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected the circuit to stay closed, got %v", err)
+	}
+}
+
+func TestUpstreamHealthIgnoresCancelledPosts(t *testing.T) {
+	t.Parallel()
+
+	health := newUpstreamHealth(2, time.Minute)
+
+	health.record(context.Canceled)
+	health.record(fmt.Errorf("post alerts: %w", context.Canceled))
+
+	if ok, reason := health.check(); !ok {
+		t.Fatalf("expected cancellations not to count, got %q", reason)
+	}
+
+	// A client disconnect cancels the request context while Alertmanager still answers.
+	post := health.wrap(func(context.Context, []alertmanager.Alert) error { return errUpstreamDown })
+
+	for range 2 {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_ = post(ctx, nil)
+	}
+
+	if ok, reason := health.check(); !ok {
+		t.Fatalf("expected posts of disconnected clients not to count, got %q", reason)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// truncatedMarker ends values cut by truncateValues.
+const truncatedMarker = ellipsis + "truncated"
+
+// sanitizeLabels makes labels acceptable to Alertmanager, which rejects a whole batch when any
+// label name does not match [a-zA-Z_][a-zA-Z0-9_]*. Invalid name characters are replaced by "_"
+// (runs collapsed) and control characters are stripped from values. A sanitized name never
+// overwrites a label that was already valid. It returns the original keys that were rewritten.
+func sanitizeLabels(labels map[string]string) (map[string]string, []string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	out := make(map[string]string, len(labels))
+
+	var changed []string
+
+	for _, key := range keys {
+		if isValidLabelName(key) {
+			out[key] = labels[key]
+		}
+	}
+
+	for _, key := range keys {
+		value := stripControlChars(labels[key])
+		name := key
+
+		if !isValidLabelName(key) {
+			name = sanitizeLabelName(key)
+			if _, taken := out[name]; taken || name == "" {
+				changed = append(changed, key)
+
+				continue
+			}
+		}
+
+		if name != key || value != labels[key] {
+			changed = append(changed, key)
+		}
+
+		out[name] = value
+	}
+
+	return out, changed
+}
+
+func isValidLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for index, char := range name {
+		if !isLabelNameChar(char, index == 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLabelNameChar(char rune, first bool) bool {
+	if char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
+		return true
+	}
+
+	return !first && char >= '0' && char <= '9'
+}
+
+func sanitizeLabelName(name string) string {
+	var builder strings.Builder
+
+	previousUnderscore := false
+
+	for _, char := range name {
+		if isLabelNameChar(char, false) && char != '_' {
+			builder.WriteRune(char)
+
+			previousUnderscore = false
+
+			continue
+		}
+
+		if !previousUnderscore {
+			builder.WriteByte('_')
+
+			previousUnderscore = true
+		}
+	}
+
+	sanitized := builder.String()
+	if sanitized != "" && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+
+	return sanitized
+}
+
+// sanitizeLabelValue turns free text into a label value: valid UTF-8 without control
+// characters, whitespace runs collapsed to one space and trimmed.
+func sanitizeLabelValue(text string) string {
+	return stripControlChars(strings.Join(strings.Fields(strings.ToValidUTF8(text, "")), " "))
+}
+
+func stripControlChars(value string) string {
+	if strings.IndexFunc(value, unicode.IsControl) < 0 {
+		return value
+	}
+
+	return strings.Map(func(char rune) rune {
+		if unicode.IsControl(char) {
+			return -1
+		}
+
+		return char
+	}, value)
+}
+
+// truncateValues cuts values longer than maxLen characters in place so they end with
+// truncatedMarker and are exactly maxLen characters long, except for the exempt keys. It
+// returns the truncated keys.
+func truncateValues(values map[string]string, maxLen int, exempt ...string) []string {
+	if maxLen <= 0 {
+		return nil
+	}
+
+	markerLen := utf8.RuneCountInString(truncatedMarker)
+
+	var truncated []string
+
+	for key, value := range values {
+		if utf8.RuneCountInString(value) <= maxLen || slices.Contains(exempt, key) {
+			continue
+		}
+
+		runes := []rune(value)
+		if maxLen <= markerLen {
+			values[key] = string(runes[:maxLen])
+		} else {
+			values[key] = string(runes[:maxLen-markerLen]) + truncatedMarker
+		}
+
+		truncated = append(truncated, key)
+	}
+
+	sort.Strings(truncated)
+
+	return truncated
+}
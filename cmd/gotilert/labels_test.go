@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeLabelsRewritesInvalidNamesAndValues(t *testing.T) {
+	t.Parallel()
+
+	labels, changed := sanitizeLabels(map[string]string{
+		"client::display": "markdown",
+		"9lives":          "cat",
+		"team":            "ops\x00\n",
+		"app":             "backup",
+		"app--":           "shadowed",
+	})
+
+	want := map[string]string{
+		"client_display": "markdown",
+		"_9lives":        "cat",
+		"team":           "ops",
+		"app":            "backup",
+		"app_":           "shadowed",
+	}
+
+	if len(labels) != len(want) {
+		t.Fatalf("expected %v, got %v", want, labels)
+	}
+
+	for key, value := range want {
+		if labels[key] != value {
+			t.Fatalf("expected %s=%q, got %q", key, value, labels[key])
+		}
+	}
+
+	if len(changed) != 4 {
+		t.Fatalf("expected 4 changed labels, got %v", changed)
+	}
+}
+
+func TestSanitizeLabelsDoesNotOverwriteValidLabel(t *testing.T) {
+	t.Parallel()
+
+	labels, changed := sanitizeLabels(map[string]string{
+		"team_name": "ops",
+		"team-name": "dropped",
+	})
+
+	if len(labels) != 1 || labels["team_name"] != "ops" {
+		t.Fatalf("expected only team_name=ops, got %v", labels)
+	}
+
+	if len(changed) != 1 || changed[0] != "team-name" {
+		t.Fatalf("expected team-name reported as changed, got %v", changed)
+	}
+}
+
+func TestTruncateValuesAtBoundary(t *testing.T) {
+	t.Parallel()
+
+	const maxLen = 20
+
+	values := map[string]string{
+		"fits":    strings.Repeat("a", maxLen),
+		"over":    strings.Repeat("b", maxLen+1),
+		"unicode": strings.Repeat("é", maxLen+5),
+	}
+
+	truncated := truncateValues(values, maxLen)
+
+	if len(truncated) != 2 || truncated[0] != "over" || truncated[1] != "unicode" {
+		t.Fatalf("expected [over unicode] truncated, got %v", truncated)
+	}
+
+	if values["fits"] != strings.Repeat("a", maxLen) {
+		t.Fatalf("expected a value of exactly maxLen to be kept, got %q", values["fits"])
+	}
+
+	for _, key := range truncated {
+		if got := utf8.RuneCountInString(values[key]); got != maxLen {
+			t.Fatalf("%s: expected %d characters, got %d (%q)", key, maxLen, got, values[key])
+		}
+
+		if !strings.HasSuffix(values[key], truncatedMarker) {
+			t.Fatalf("%s: expected the %q marker, got %q", key, truncatedMarker, values[key])
+		}
+	}
+}
+
+func TestTruncateValuesSkipsExemptKeys(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("x", 30)
+	values := map[string]string{"alertname": long, "team": long}
+
+	truncated := truncateValues(values, 20, "alertname")
+
+	if len(truncated) != 1 || truncated[0] != "team" {
+		t.Fatalf("expected only team truncated, got %v", truncated)
+	}
+
+	if values["alertname"] != long {
+		t.Fatalf("expected the exempt value kept whole, got %q", values["alertname"])
+	}
+}
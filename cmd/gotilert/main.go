@@ -32,23 +32,43 @@ import (
 	"io"
 	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/audit"
 	"github.com/leinardi/gotilert/internal/config"
 	"github.com/leinardi/gotilert/internal/gotify"
 	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/mapping"
 	"github.com/leinardi/gotilert/internal/metrics"
 	"github.com/leinardi/gotilert/internal/server"
+	"github.com/leinardi/gotilert/internal/templating"
+	"github.com/leinardi/gotilert/internal/tracing"
 )
 
 const exitCodeError = 1
 
+const (
+	// generatorURLKey is the single key of the compiled generatorURL template map.
+	generatorURLKey = "generatorURL"
+
+	// gotilertIDKey is the label (or annotation) carrying the per-message identifier.
+	gotilertIDKey = "gotilert_id"
+
+	// ellipsis ends summaries cut by pickSummary.
+	ellipsis = "…"
+)
+
 const (
 	defaultReadTimeout     = 5 * time.Second
 	defaultWriteTimeout    = 10 * time.Second
@@ -56,15 +76,29 @@ const (
 	defaultShutdownTimeout = 10 * time.Second
 
 	defaultReadyTimeout = 2 * time.Second
+
+	defaultCircuitCooldown = 30 * time.Second
+
+	defaultAsyncQueueSize = 1024
+	defaultAsyncWorkers   = 4
 )
 
 type cliOptions struct {
 	showVersion bool
+	checkConfig bool
+	printConfig bool
 	configFile  string
 
 	logFormat string
 	logLevel  string
 	logTime   bool
+	logOutput string
+
+	// logWriter is the opened --log-output, shared by the preliminary logger and, when the
+	// config does not pick another output, the reconfigured one.
+	logWriter io.Writer
+
+	pprof bool
 
 	overrides map[string]bool
 }
@@ -84,8 +118,13 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return err
 	}
 
+	options.logWriter, err = logger.OpenOutput(logger.Output{Path: options.logOutput})
+	if err != nil {
+		return fmt.Errorf("log output: %w", err)
+	}
+
 	// Preliminary logger from CLI defaults/overrides so config errors are emitted consistently.
-	logger.Configure(options.logFormat, options.logLevel, options.logTime)
+	logger.Configure(options.logWriter, options.logFormat, options.logLevel, options.logTime)
 
 	if options.showVersion {
 		err = printVersion(stdout)
@@ -96,6 +135,14 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return nil
 	}
 
+	if options.checkConfig {
+		return runConfigCheck(options, stdout)
+	}
+
+	if options.printConfig {
+		return runPrintConfig(options, stdout)
+	}
+
 	logger.L().Info("starting gotilert", "version", version, "commit", commit, "date", date)
 
 	cfg, err := loadConfigOrExit(options.configFile)
@@ -108,14 +155,19 @@ func run(args []string, stdout, stderr io.Writer) error {
 		return err
 	}
 
-	applyLoggingConfig(cfg, options)
+	err = applyLoggingConfig(cfg, options)
+	if err != nil {
+		return err
+	}
+
+	applyServerOverrides(cfg, options)
 
-	httpServer, shutdownTimeout, err := buildHTTPServer(cfg)
+	svc, err := buildService(cfg, options.configFile)
 	if err != nil {
 		return err
 	}
 
-	err = runHTTPServer(httpServer, shutdownTimeout)
+	err = runService(svc)
 	if err != nil {
 		return err
 	}
@@ -123,34 +175,200 @@ func run(args []string, stdout, stderr io.Writer) error {
 	return nil
 }
 
-func buildHTTPServer(cfg *config.Config) (*http.Server, time.Duration, error) {
+// runConfigCheck implements --check-config.
+func runConfigCheck(options cliOptions, stdout io.Writer) error {
+	silenceInfoLogs(options)
+
+	cfg, err := loadConfigOrExit(options.configFile)
+	if err != nil {
+		if stdout != nil {
+			_, _ = fmt.Fprintf(stdout, "fail config %v\n", err)
+		}
+
+		return fmt.Errorf("%w: %w", ErrConfigCheckFailed, err)
+	}
+
+	return checkConfig(stdout, options.configFile, cfg)
+}
+
+// runPrintConfig implements --print-config, applying the same CLI overrides as a normal start.
+func runPrintConfig(options cliOptions, stdout io.Writer) error {
+	silenceInfoLogs(options)
+
+	cfg, err := loadConfigOrExit(options.configFile)
+	if err != nil {
+		return err
+	}
+
+	applyServerOverrides(cfg, options)
+
+	return printConfig(stdout, cfg)
+}
+
+// silenceInfoLogs raises the log level to error (unless --log-level is given) so logs,
+// which also go to stdout, do not mix with command output.
+func silenceInfoLogs(options cliOptions) {
+	if !options.overrides["log-level"] {
+		logger.Configure(options.logWriter, options.logFormat, "error", options.logTime)
+	}
+}
+
+func serverTLSOptions(tlsConfig *config.ServerTLSConfig) *server.TLSOptions {
+	if !tlsConfig.Enabled() {
+		return nil
+	}
+
+	return &server.TLSOptions{
+		CertFile:     tlsConfig.CertFile,
+		KeyFile:      tlsConfig.KeyFile,
+		ClientCAFile: tlsConfig.ClientCAFile,
+	}
+}
+
+func corsOptions(cors *config.CORSConfig) *server.CORSOptions {
+	if !cors.Enabled() {
+		return nil
+	}
+
+	return &server.CORSOptions{
+		AllowedOrigins: cors.AllowedOrigins,
+		AllowedMethods: cors.AllowedMethods,
+		AllowedHeaders: cors.AllowedHeaders,
+		MaxAge:         cors.MaxAge.Duration,
+	}
+}
+
+func idempotencyOptions(idempotency *config.IdempotencyConfig) *server.IdempotencyOptions {
+	if idempotency.TTL.Duration <= 0 {
+		return nil
+	}
+
+	return &server.IdempotencyOptions{TTL: idempotency.TTL.Duration, MaxEntries: idempotency.MaxEntries}
+}
+
+func metricsAuthOptions(auth *config.MetricsAuthConfig) *server.MetricsAuth {
+	switch {
+	case auth.BasicAuth != nil:
+		return &server.MetricsAuth{Username: auth.BasicAuth.Username, Password: auth.BasicAuth.Password}
+	case auth.BearerToken != "":
+		return &server.MetricsAuth{BearerToken: auth.BearerToken}
+	default:
+		return nil
+	}
+}
+
+// service groups the HTTP server with the components that must be stopped alongside it.
+type service struct {
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+
+	// draining is set once shutdown starts; readiness reports false from then on.
+	draining   *atomic.Bool
+	drainDelay time.Duration
+
+	reloader *reloader
+
+	// readyPoller is nil unless server.readyInterval is set.
+	readyPoller *readyPoller
+
+	// startupGate is nil unless alertmanager.waitForReadyOnStartup is set.
+	startupGate *startupGate
+
+	// queue is nil unless alertmanager.async is enabled.
+	queue *alertmanager.Queue
+
+	// batcher is nil when alertmanager.batching is disabled.
+	batcher *alertmanager.Batcher
+
+	// shutdownTracing is nil unless tracing.enabled.
+	shutdownTracing tracing.ShutdownFunc
+}
+
+func buildService(cfg *config.Config, configPath string) (*service, error) {
 	readTimeout := pickDuration(cfg.Server.ReadTimeout.Duration, defaultReadTimeout)
 	writeTimeout := pickDuration(cfg.Server.WriteTimeout.Duration, defaultWriteTimeout)
 	idleTimeout := pickDuration(cfg.Server.IdleTimeout.Duration, defaultIdleTimeout)
 	shutdownTimeout := pickDuration(cfg.Server.ShutdownTimeout.Duration, defaultShutdownTimeout)
 
-	resolveApp := newResolveAppFunc(cfg)
-
-	amClient, err := newAlertmanagerClient(cfg)
+	shutdownTracing, err := setupTracing(&cfg.Tracing)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	metricsCollector := metrics.New()
+	metricsCollector.SetBuildInfo(version, commit, date)
 
-	readyFunc := func() (bool, string) {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultReadyTimeout)
-		defer cancel()
+	if !cfg.Metrics.DisableRuntimeMetrics {
+		metricsCollector.RegisterRuntimeCollectors()
+	}
 
-		readyErr := amClient.Ready(ctx)
-		if readyErr != nil {
-			return false, readyErr.Error()
-		}
+	rel, err := newReloader(configPath, cfg, metricsCollector)
+	if err != nil {
+		return nil, err
+	}
 
-		return true, ""
+	postAlerts, batcher, err := newPostAlertsFunc(cfg, rel.client, metricsCollector)
+	if err != nil {
+		return nil, err
+	}
+
+	postAlerts, err = withCircuitBreaker(&cfg.Alertmanager.CircuitBreaker, postAlerts, metricsCollector)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := newUpstreamHealth(
+		cfg.Server.Health.UpstreamFailureThreshold,
+		cfg.Server.Health.UpstreamFailureWindow.Duration,
+	)
+	rel.postAlerts = upstream.wrap(postAlerts)
+	rel.syncPostAlerts = rel.postAlerts
+
+	queue, err := newForwardQueue(cfg, rel.postAlerts, metricsCollector)
+	if err != nil {
+		return nil, err
+	}
+
+	if queue != nil {
+		rel.postAlerts = queue.PostAlerts
+		rel.async = true
+	}
+
+	var testAlertFunc server.TestAlertFunc
+	if cfg.Server.TestEndpoint {
+		testAlertFunc = rel.testAlert
 	}
 
-	forward := newForwarder(cfg, amClient, metricsCollector)
+	draining := &atomic.Bool{}
+
+	checkReady := alertmanagerReadyFunc(rel, metricsCollector, pickDuration(cfg.Server.ReadyTimeout.Duration, defaultReadyTimeout))
+	readyFunc := checkReady
+
+	var poller *readyPoller
+	if cfg.Server.ReadyInterval.Duration > 0 {
+		poller = newReadyPoller(readyFunc, cfg.Server.ReadyInterval.Duration)
+		readyFunc = poller.ready
+	} else {
+		readyFunc = cachedReady(readyFunc, cfg.Server.ReadyCacheTTL.Duration)
+	}
+
+	forwardFunc := server.ForwardMessageFunc(rel.forward)
+
+	gate := newStartupGate(&cfg.Alertmanager, checkReady)
+	if gate != nil {
+		readyFunc = gate.ready(readyFunc)
+		forwardFunc = gate.forward(forwardFunc)
+	}
+
+	allowedClients, err := config.ParsePrefixes(cfg.Server.AllowedCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("parse server.allowedCIDRs: %w", err)
+	}
+
+	trustedProxies, err := config.ParsePrefixes(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("parse server.trustedProxies: %w", err)
+	}
 
 	httpServer, err := server.New(&server.Options{
 		Addr:            cfg.Server.ListenAddr,
@@ -158,41 +376,214 @@ func buildHTTPServer(cfg *config.Config) (*http.Server, time.Duration, error) {
 		WriteTimeout:    writeTimeout,
 		IdleTimeout:     idleTimeout,
 		ShutdownTimeout: shutdownTimeout,
-		MaxBodyBytes:    1 << 20, // 1 MiB
-
-		Health: func() (bool, string) { return true, "" },
-		Ready:  readyFunc,
+		MaxBodyBytes:    cfg.Server.MaxBodyBytes,  // 0 -> 1 MiB default in server.New
+		MaxBatchLines:   cfg.Server.MaxBatchLines, // 0 -> 1000 default in server.New
+		RoutePrefix:     cfg.Server.RoutePrefix,
+		TLS:             serverTLSOptions(&cfg.Server.TLS),
+
+		ParseOptions: gotify.ParseOptions{
+			DefaultJSON: cfg.Server.DefaultContentType == config.ContentTypeJSON,
+			MaxPriority: cfg.Gotify.MaxPriority,
+		},
+
+		Health: newHealthFunc(cfg.Server.Health, configPath, upstream),
+		Ready:  readyUnlessDraining(draining, readyFunc),
+
+		ResolveApp:      rel.resolveApp,
+		ForwardMessage:  forwardFunc,
+		AsyncForward:    queue != nil,
+		GotifyEndpoints: cfg.Server.GotifyCompat,
+		TestAlert:       testAlertFunc,
+		GotifyErrors:    cfg.Server.GotifyCompat,
+
+		Reload:         rel.Reload,
+		AuthorizeAdmin: rel.authorizeAdmin,
+
+		Metrics:     metricsCollector,
+		MetricsAuth: metricsAuthOptions(&cfg.Server.Metrics.Auth),
+		BuildInfo:   buildInfo(),
+		EnablePprof: cfg.Server.Pprof,
+		LogProbes:   cfg.Logging.LogProbes,
+
+		AllowedClients: allowedClients,
+		TrustProxy:     cfg.Server.TrustProxy,
+		TrustedProxies: trustedProxies,
+		CORS:           corsOptions(&cfg.Server.CORS),
+		Idempotency:    idempotencyOptions(&cfg.Server.Idempotency),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create http server: %w", err)
+	}
 
-		ResolveApp:     resolveApp,
-		ForwardMessage: forward,
+	return &service{
+		httpServer:      httpServer,
+		shutdownTimeout: shutdownTimeout,
+		draining:        draining,
+		drainDelay:      cfg.Server.DrainDelay.Duration,
+		reloader:        rel,
+		readyPoller:     poller,
+		startupGate:     gate,
+		queue:           queue,
+		batcher:         batcher,
+		shutdownTracing: shutdownTracing,
+	}, nil
+}
 
-		Metrics: metricsCollector,
-	})
+func newResolveAppFunc(cfg *config.Config) (server.ResolveAppFunc, error) {
+	apps, err := buildApps(cfg)
 	if err != nil {
-		return nil, 0, fmt.Errorf("create http server: %w", err)
+		return nil, err
 	}
 
-	return httpServer, shutdownTimeout, nil
+	// Index apps by token digest so no plaintext token is kept for lookups; incoming
+	// tokens are hashed the same way (see config.TokenDigest).
+	byDigest := make(map[string]server.App, len(apps))
+
+	for token, app := range apps {
+		if token == config.CatchAllToken {
+			continue
+		}
+
+		digest, digestErr := config.TokenDigest(token)
+		if digestErr != nil {
+			return nil, fmt.Errorf("app %q: %w", app.Name, digestErr)
+		}
+
+		byDigest[digest] = app
+	}
+
+	// Unknown tokens are rejected unless the catch-all app (apps."*") is configured.
+	catchAll, hasCatchAll := apps[config.CatchAllToken]
+
+	return func(token string) (server.App, bool) {
+		app, ok := byDigest[config.HashToken(token)]
+		if !ok && hasCatchAll {
+			logger.L().Debug("unknown token, using catch-all app", "app", catchAll.Name)
+
+			return catchAll, true
+		}
+
+		return app, ok
+	}, nil
 }
 
-func newResolveAppFunc(cfg *config.Config) server.ResolveAppFunc {
+// buildApps converts the configured apps, keyed by token, into their runtime form.
+func buildApps(cfg *config.Config) (map[string]server.App, error) {
 	apps := make(map[string]server.App, len(cfg.Apps))
 
 	for token, app := range cfg.Apps {
-		apps[token] = server.App{
-			Name:                 app.AppName,
-			ID:                   appIDFromName(app.AppName),
-			AlertName:            strings.TrimSpace(app.AlertName),
-			Labels:               copyLabels(app.Labels),
-			SeverityFromPriority: copySeverityMap(app.SeverityFromPriority),
+		labels, annotations, err := compileTemplates(app.Labels, app.Annotations)
+		if err != nil {
+			return nil, fmt.Errorf("app %q: %w", app.AppName, err)
+		}
+
+		generatorURL, err := compileGeneratorURL(app.GeneratorURL)
+		if err != nil {
+			return nil, fmt.Errorf("app %q: %w", app.AppName, err)
+		}
+
+		built := server.App{
+			Name:                   app.AppName,
+			ID:                     appID(&app),
+			AlertName:              strings.TrimSpace(app.AlertName),
+			AlertnameFromTitle:     app.AlertnameFromTitle,
+			MinForwardPriority:     app.MinForwardPriority,
+			Labels:                 labels,
+			Annotations:            annotations,
+			SeverityFromPriority:   copySeverityMap(app.SeverityFromPriority),
+			TTLFromPriority:        ttlMap(app.TTLFromPriority),
+			GeneratorURL:           generatorURL,
+			GotilertIDAsAnnotation: app.GotilertIDAsAnnotation,
+			Resolve: server.ResolveTrigger{
+				Priority:  app.Resolve.Priority,
+				ExtrasKey: strings.TrimSpace(app.Resolve.ExtrasKey),
+			},
+			ExtrasPolicy:  appExtrasPolicy(app.Extras),
+			RateLimit:     appRateLimit(cfg.Server.RateLimit, app.RateLimit),
+			SigningSecret: appSigningSecret(cfg.Defaults.SigningSecret, app.SigningSecret),
+		}
+
+		apps[token] = built
+		for _, extra := range app.Tokens {
+			apps[extra] = built
 		}
 	}
 
-	return func(token string) (server.App, bool) {
-		app, ok := apps[token]
+	return apps, nil
+}
 
-		return app, ok
+func appRateLimit(defaults config.RateLimitConfig, override *config.RateLimitConfig) server.RateLimit {
+	if override != nil {
+		defaults = *override
+	}
+
+	return server.RateLimit{RPS: defaults.RPS, Burst: defaults.Burst}
+}
+
+func appSigningSecret(defaults, override string) string {
+	if override != "" {
+		return override
+	}
+
+	return defaults
+}
+
+// appExtrasPolicy returns nil when the app does not override defaults.extras.
+func appExtrasPolicy(extras *config.ExtrasConfig) *gotify.ExtrasPolicy {
+	if extras == nil {
+		return nil
+	}
+
+	policy := extrasPolicyFromConfig(*extras)
+
+	return &policy
+}
+
+func extrasPolicyFromConfig(extras config.ExtrasConfig) gotify.ExtrasPolicy {
+	return gotify.ExtrasPolicy{
+		Allow:       slices.Clone(extras.Allow),
+		Deny:        slices.Clone(extras.Deny),
+		Passthrough: extras.Passthrough,
+	}
+}
+
+// compileTemplates parses label/annotation values once; config validation already rejected bad syntax.
+func compileTemplates(labels, annotations map[string]string) (*templating.Map, *templating.Map, error) {
+	labelTemplates, err := templating.Compile(labels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compile labels: %w", err)
+	}
+
+	annotationTemplates, err := templating.Compile(annotations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compile annotations: %w", err)
 	}
+
+	return labelTemplates, annotationTemplates, nil
+}
+
+// compileGeneratorURL returns nil for an empty value, so the app falls back to the default.
+func compileGeneratorURL(generatorURL string) (*templating.Map, error) {
+	if generatorURL == "" {
+		return nil, nil //nolint:nilnil // nil means "not configured".
+	}
+
+	compiled, err := templating.Compile(map[string]string{generatorURLKey: generatorURL})
+	if err != nil {
+		return nil, fmt.Errorf("compile generatorURL: %w", err)
+	}
+
+	return compiled, nil
+}
+
+// renderTemplates evaluates tmpl against data; failures render as templating.RenderFailed and are logged.
+func renderTemplates(tmpl *templating.Map, data *templating.Data) map[string]string {
+	rendered, err := tmpl.Render(data)
+	if err != nil {
+		logger.L().Warn("template rendering failed", "err", err, "app", data.AppName)
+	}
+
+	return rendered
 }
 
 func copySeverityMap(input map[int]string) map[int]string {
@@ -202,6 +593,29 @@ func copySeverityMap(input map[int]string) map[int]string {
 	return out
 }
 
+// ttlMap converts a configured ttlFromPriority map (nil stays nil).
+func ttlMap(input map[int]config.Duration) map[int]time.Duration {
+	if len(input) == 0 {
+		return nil
+	}
+
+	out := make(map[int]time.Duration, len(input))
+	for priority, ttl := range input {
+		out[priority] = ttl.Duration
+	}
+
+	return out
+}
+
+// appID returns the configured apps[*].appId, falling back to appIDFromName.
+func appID(app *config.AppConfig) uint32 {
+	if app.AppID != nil {
+		return *app.AppID
+	}
+
+	return appIDFromName(app.AppName)
+}
+
 func appIDFromName(appName string) uint32 {
 	// Small deterministic hash (FNV-1a 32-bit) without importing hash/fnv here.
 	const (
@@ -218,7 +632,25 @@ func appIDFromName(appName string) uint32 {
 	return hash
 }
 
-func newAlertmanagerClient(cfg *config.Config) (*alertmanager.Client, error) {
+// appContextKey carries the app name through PostAlerts so retry metrics can be attributed.
+type appContextKey struct{}
+
+func withAppName(ctx context.Context, appName string) context.Context {
+	return context.WithValue(ctx, appContextKey{}, appName)
+}
+
+// appNameFromContext falls back to "batched": batched posts run on the batcher's context
+// and may mix alerts from several apps.
+func appNameFromContext(ctx context.Context) string {
+	appName, ok := ctx.Value(appContextKey{}).(string)
+	if !ok {
+		return metrics.PostModeBatched
+	}
+
+	return appName
+}
+
+func newAlertmanagerClient(cfg *config.Config, metricsCollector *metrics.Metrics) (*alertmanager.Client, error) {
 	auth := alertmanager.Auth{}
 
 	if cfg.Alertmanager.BasicAuth != nil {
@@ -229,10 +661,36 @@ func newAlertmanagerClient(cfg *config.Config) (*alertmanager.Client, error) {
 	auth.BearerToken = cfg.Alertmanager.Bearer
 
 	client, err := alertmanager.New(&alertmanager.Options{
-		BaseURL:            cfg.Alertmanager.URL,
+		BaseURLs:           cfg.Alertmanager.PeerURLs(),
 		Timeout:            cfg.Alertmanager.Timeout.Duration,
 		InsecureSkipVerify: cfg.Alertmanager.TLSConfig.InsecureSkipVerify,
+		ClientCertFile:     cfg.Alertmanager.TLSConfig.ClientCertFile,
+		ClientKeyFile:      cfg.Alertmanager.TLSConfig.ClientKeyFile,
+		CACertFile:         cfg.Alertmanager.TLSConfig.CACertFile,
+		CABundle:           cfg.Alertmanager.TLSConfig.CABundle,
 		Auth:               auth,
+		Headers:            cfg.Alertmanager.Headers,
+		ProxyURL:           cfg.Alertmanager.ProxyURL,
+		APIVersion:         cfg.Alertmanager.APIVersion,
+		AlertsPath:         cfg.Alertmanager.AlertsPath,
+		ReadyPath:          cfg.Alertmanager.ReadyPath,
+		CompressRequests:   cfg.Alertmanager.CompressRequests,
+
+		MaxIdleConns:        cfg.Alertmanager.Transport.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.Alertmanager.Transport.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.Alertmanager.Transport.MaxConnsPerHost,
+		IdleConnTimeout:     cfg.Alertmanager.Transport.IdleConnTimeout.Duration,
+		DisableKeepAlives:   cfg.Alertmanager.Transport.DisableKeepAlives,
+		ConnMaxAge:          cfg.Alertmanager.Transport.ConnMaxAge.Duration,
+
+		RetryMaxAttempts:    cfg.Alertmanager.Retry.MaxAttempts,
+		RetryInitialBackoff: cfg.Alertmanager.Retry.InitialBackoff.Duration,
+		RetryMaxBackoff:     cfg.Alertmanager.Retry.MaxBackoff.Duration,
+		RetryStatusCodes:    cfg.Alertmanager.RetryStatusCodes,
+		NoRetryStatusCodes:  cfg.Alertmanager.NoRetryStatusCodes,
+		OnRetry: func(ctx context.Context, _ int, _ error) {
+			metricsCollector.IncUpstreamRetry(appNameFromContext(ctx))
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create alertmanager client: %w", err)
@@ -241,197 +699,824 @@ func newAlertmanagerClient(cfg *config.Config) (*alertmanager.Client, error) {
 	return client, nil
 }
 
-func newForwarder(
+// newPostAlertsFunc returns how the forwarder delivers alerts: directly through the client,
+// or through a batcher when alertmanager.batching.window is set.
+//
+// The client is looked up on every call so a reload can swap it.
+func newPostAlertsFunc(
 	cfg *config.Config,
-	amClient *alertmanager.Client,
+	currentClient func() *alertmanager.Client,
 	metricsCollector *metrics.Metrics,
-) server.ForwardMessageFunc {
-	ttl := cfg.Defaults.TTL.Duration
-	defaultLabels := copyLabels(cfg.Defaults.Labels)
-	defaultSeverityMap := cfg.Defaults.SeverityFromPriority
-	defaultAlertName := cfg.Defaults.AlertName
-
-	return func(ctx context.Context, app server.App, msg gotify.MessageRequest, messageIdentifier uint64) error {
-		severityMap := defaultSeverityMap
-		if len(app.SeverityFromPriority) > 0 {
-			severityMap = app.SeverityFromPriority
-		}
+) (alertmanager.PostFunc, *alertmanager.Batcher, error) {
+	// The concurrency limit wraps the client only, so a limit of 1 still lets the batcher
+	// coalesce concurrent forwards into a single POST.
+	clientPost := func(ctx context.Context, alerts []alertmanager.Alert) error {
+		return currentClient().PostAlerts(ctx, alerts)
+	}
 
-		alertName := defaultAlertName
-		if strings.TrimSpace(app.AlertName) != "" {
-			alertName = strings.TrimSpace(app.AlertName)
-		}
+	clientPost, err := withConcurrencyLimit(&cfg.Alertmanager, clientPost, metricsCollector)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		severity := severityForPriority(severityMap, msg.Priority)
+	if cfg.Alertmanager.Batching.Window.Duration <= 0 {
+		return func(ctx context.Context, alerts []alertmanager.Alert) error {
+			metricsCollector.IncAlertmanagerPost(metrics.PostModeImmediate)
 
-		// Merge: defaults.labels + app.labels + computed labels (computed wins).
-		labels := copyLabels(defaultLabels)
-		mergeStringMap(labels, app.Labels)
+			return clientPost(ctx, alerts)
+		}, nil, nil
+	}
 
-		labels["alertname"] = alertName
-		labels["app"] = app.Name
-		labels["severity"] = severity
-		labels["priority"] = strconv.Itoa(msg.Priority)
-		labels["gotilert_id"] = strconv.FormatUint(messageIdentifier, 10)
+	batcher, err := alertmanager.NewBatcher(&alertmanager.BatcherOptions{
+		Window:  cfg.Alertmanager.Batching.Window.Duration,
+		MaxSize: cfg.Alertmanager.Batching.MaxSize,
+		Timeout: cfg.Alertmanager.Timeout.Duration,
+		Post: func(ctx context.Context, alerts []alertmanager.Alert) error {
+			metricsCollector.IncAlertmanagerPost(metrics.PostModeBatched)
+			metricsCollector.ObserveBatchSize(len(alerts))
 
-		annotations := map[string]string{
-			"summary":     pickSummary(app.Name, msg.Title, msg.Message),
-			"description": msg.Message,
-		}
+			return clientPost(ctx, alerts)
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create alertmanager batcher: %w", err)
+	}
 
-		mergeStringMap(annotations, gotify.ExtrasAnnotations(msg.Extras))
+	return batcher.PostAlerts, batcher, nil
+}
 
-		now := time.Now().UTC()
-		alert := alertmanager.Alert{
-			Labels:      labels,
-			Annotations: annotations,
-			StartsAt:    now,
-			EndsAt:      now.Add(ttl),
-		}
+// setupTracing installs the OpenTelemetry exporter when tracing.enabled is set and returns
+// its flush function, or nil when tracing is off (spans then stay no-ops).
+func setupTracing(tracingConfig *config.TracingConfig) (tracing.ShutdownFunc, error) {
+	if !tracingConfig.Enabled {
+		return nil, nil //nolint:nilnil // nil means "tracing disabled".
+	}
 
-		forwardCtx, cancel := withBoundedTimeout(ctx, cfg.Alertmanager.Timeout.Duration)
-		defer cancel()
+	shutdown, err := tracing.Setup(context.Background(), version)
+	if err != nil {
+		return nil, fmt.Errorf("set up tracing: %w", err)
+	}
 
-		postErr := amClient.PostAlerts(forwardCtx, []alertmanager.Alert{alert})
-		if postErr != nil {
-			if metricsCollector != nil {
-				metricsCollector.IncUpstreamFailure(app.Name)
-			}
+	logger.L().Info("opentelemetry tracing enabled")
 
-			// Make auth/upstream issues debuggable (e.g., 401 with WWW-Authenticate).
-			logArgs := []any{
-				"err", postErr,
-				"app", app.Name,
-				"upstream", cfg.Alertmanager.URL,
-			}
+	return shutdown, nil
+}
+
+// newForwardQueue returns a fire-and-forget queue in front of postAlerts when
+// alertmanager.async.enabled is set, and nil otherwise. Forwards are observed and counted
+// once delivered; background failures are also logged since the client was already
+// answered with 202. Audit records are completed on delivery too.
+func newForwardQueue(
+	cfg *config.Config,
+	postAlerts alertmanager.PostFunc,
+	metricsCollector *metrics.Metrics,
+) (*alertmanager.Queue, error) {
+	if !cfg.Alertmanager.Async.Enabled {
+		return nil, nil //nolint:nilnil // nil means "synchronous forwarding".
+	}
 
-			var stErr alertmanager.HTTPStatusError
-			if errors.As(postErr, &stErr) {
-				logArgs = append(logArgs,
-					"upstream_status", stErr.StatusCode(),
-					"upstream_body", stErr.Body(),
-				)
+	queue, err := alertmanager.NewQueue(&alertmanager.QueueOptions{
+		Size:    pickInt(cfg.Alertmanager.Async.QueueSize, defaultAsyncQueueSize),
+		Workers: pickInt(cfg.Alertmanager.Async.Workers, defaultAsyncWorkers),
+		Timeout: cfg.Alertmanager.Timeout.Duration,
+		Post:    postAlerts,
+		OnDepth: metricsCollector.SetForwardQueueDepth,
+		OnResult: func(ctx context.Context, elapsed time.Duration, err error) {
+			appName := appNameFromContext(ctx)
+
+			metricsCollector.ObserveForward(appName, elapsed, server.TraceFromContext(ctx))
+			completeAudit(ctx, err)
+
+			if err != nil {
+				metricsCollector.IncUpstreamFailure(appName)
+				metricsCollector.IncForwardQueueDropped(appName, metrics.QueueDropFailed)
+				logger.L().Error("async forward to alertmanager failed", forwardFailureLogArgs(ctx, cfg, appName, err)...)
+
+				return
 			}
 
-			logger.L().Error("forward to alertmanager failed", logArgs...)
+			metricsCollector.IncForwarded(appName)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create alertmanager forward queue: %w", err)
+	}
 
-			return fmt.Errorf("post alert: %w", postErr)
-		}
+	return queue, nil
+}
 
-		if metricsCollector != nil {
-			metricsCollector.IncForwarded(app.Name)
-		}
+// withConcurrencyLimit caps concurrent posts when alertmanager.maxConcurrency is set.
+// The circuit breaker and upstream health ignore ErrConcurrencyLimit, so waiting for a
+// slot is never counted as an upstream failure.
+func withConcurrencyLimit(
+	amConfig *config.AlertmanagerConfig,
+	postAlerts alertmanager.PostFunc,
+	metricsCollector *metrics.Metrics,
+) (alertmanager.PostFunc, error) {
+	if amConfig.MaxConcurrency <= 0 {
+		return postAlerts, nil
+	}
+
+	limiter, err := alertmanager.NewConcurrencyLimiter(&alertmanager.ConcurrencyLimiterOptions{
+		Max:        amConfig.MaxConcurrency,
+		Wait:       amConfig.ConcurrencyWait.Duration,
+		Post:       postAlerts,
+		OnInflight: metricsCollector.SetForwardInflight,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create alertmanager concurrency limiter: %w", err)
+	}
+
+	return limiter.PostAlerts, nil
+}
+
+// withCircuitBreaker wraps postAlerts in a circuit breaker when
+// alertmanager.circuitBreaker.failureThreshold is set, so a hard-down Alertmanager
+// fails fast instead of every /message paying the full retry cost.
+func withCircuitBreaker(
+	breakerConfig *config.CircuitBreakerConfig,
+	postAlerts alertmanager.PostFunc,
+	metricsCollector *metrics.Metrics,
+) (alertmanager.PostFunc, error) {
+	if breakerConfig.FailureThreshold <= 0 {
+		return postAlerts, nil
+	}
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: breakerConfig.FailureThreshold,
+		Cooldown:         pickDuration(breakerConfig.Cooldown.Duration, defaultCircuitCooldown),
+		Post:             postAlerts,
+		OnStateChange: func(state alertmanager.CircuitState) {
+			metricsCollector.SetCircuitState(state.String())
+			logger.L().Warn("alertmanager circuit breaker state changed", "state", state.String())
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create alertmanager circuit breaker: %w", err)
+	}
+
+	metricsCollector.SetCircuitState(alertmanager.CircuitClosed.String())
+
+	return breaker.PostAlerts, nil
+}
+
+// forwarder turns Gotify messages into Alertmanager alerts for one runtime state.
+type forwarder struct {
+	cfg        *config.Config
+	postAlerts alertmanager.PostFunc
+	metrics    *metrics.Metrics
+	firing     *firingAlerts
+
+	// audit is nil unless logging.auditFile is set.
+	audit *audit.Log
+
+	// autoResolve is nil when the forwarder is built outside a reloader (e.g. gotilert check).
+	autoResolve *autoResolver
+
+	// queued reports whether a post only enqueues the alerts (alertmanager.async); nil
+	// means forwarding is synchronous.
+	queued func(ctx context.Context) bool
+
+	defaultLabels       *templating.Map
+	defaultAnnotations  *templating.Map
+	defaultGeneratorURL *templating.Map
+	forcedLabels        *templating.Map
+	defaultTTLs         map[int]time.Duration
+	extrasPolicy        gotify.ExtrasPolicy
+	rules               []rule
+}
+
+func newForwarder(
+	cfg *config.Config,
+	postAlerts alertmanager.PostFunc,
+	metricsCollector *metrics.Metrics,
+	firing *firingAlerts,
+) (server.ForwardMessageFunc, error) {
+	fwd, err := buildForwarder(cfg, postAlerts, metricsCollector, firing)
+	if err != nil {
+		return nil, err
+	}
+
+	return fwd.forward, nil
+}
+
+func buildForwarder(
+	cfg *config.Config,
+	postAlerts alertmanager.PostFunc,
+	metricsCollector *metrics.Metrics,
+	firing *firingAlerts,
+) (*forwarder, error) {
+	defaultLabels, defaultAnnotations, err := compileTemplates(cfg.Defaults.Labels, cfg.Defaults.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+
+	defaultGeneratorURL, err := compileGeneratorURL(cfg.Defaults.GeneratorURL)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+
+	forcedLabels, err := templating.Compile(cfg.Defaults.ForcedLabels)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: compile forced labels: %w", err)
+	}
+
+	rules, err := compileRules(cfg.Defaults.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("defaults: %w", err)
+	}
+
+	return &forwarder{
+		cfg:                 cfg,
+		postAlerts:          postAlerts,
+		metrics:             metricsCollector,
+		firing:              firing,
+		defaultLabels:       defaultLabels,
+		defaultAnnotations:  defaultAnnotations,
+		defaultGeneratorURL: defaultGeneratorURL,
+		forcedLabels:        forcedLabels,
+		defaultTTLs:         ttlMap(cfg.Defaults.TTLFromPriority),
+		extrasPolicy:        extrasPolicyFromConfig(cfg.Defaults.Extras),
+		rules:               rules,
+	}, nil
+}
+
+func (fwd *forwarder) forward(
+	ctx context.Context,
+	app server.App,
+	msg gotify.MessageRequest,
+	messageIdentifier server.MessageID,
+) error {
+	// Clamp before anything looks at the priority (resolve trigger, severity, labels).
+	msg.Priority = fwd.cfg.Defaults.PriorityRange.Clamp(msg.Priority)
+
+	details := server.ForwardDetailsFromContext(ctx)
+
+	if app.Resolve.Matches(msg) {
+		details.MarkResolved()
+
+		return fwd.resolve(ctx, app, msg)
+	}
+
+	if minPriority := fwd.minForwardPriority(app); msg.Priority < minPriority {
+		fwd.metrics.IncDropped(app.Name, metrics.DropBelowMinPriority)
+		details.MarkDropped(metrics.DropBelowMinPriority)
+		logger.L().Debug("message below minForwardPriority not forwarded",
+			"request_id", server.RequestIDFromContext(ctx),
+			"app", app.Name,
+			"priority", msg.Priority,
+			"minForwardPriority", minPriority,
+		)
+
+		return nil
+	}
+
+	actions := fwd.evaluateRules(&msg)
+	if actions.dropBy != "" {
+		fwd.metrics.IncDropped(app.Name, metrics.DropRule)
+		details.MarkDropped(metrics.DropRule)
+		logger.L().Debug("message dropped by rule",
+			"request_id", server.RequestIDFromContext(ctx),
+			"app", app.Name,
+			"rule", actions.dropBy,
+		)
 
 		return nil
 	}
+
+	now := time.Now().UTC()
+	alert := fwd.buildAlert(app, msg, messageIdentifier, actions, now)
+	details.Record(alert.Labels, alert.Annotations, alert.EndsAt.Sub(now))
+
+	ctx = fwd.startAudit(ctx, app.Name, msg.Priority, &alert, messageIdentifier)
+	err := fwd.post(ctx, app.Name, []alertmanager.Alert{alert})
+	fwd.finishAudit(ctx, err)
+
+	if err != nil {
+		return err
+	}
+
+	if app.Resolve.Enabled() {
+		fwd.firing.track(app.Name, msg.Title, alert)
+	}
+
+	fwd.autoResolve.schedule(app.Name, alert, fwd.cfg.Defaults.AutoResolveAfter.Duration)
+
+	return nil
 }
 
-func mergeStringMap(dst, src map[string]string) {
-	if len(src) == 0 {
-		return
+func (fwd *forwarder) buildAlert(
+	app server.App,
+	msg gotify.MessageRequest,
+	messageIdentifier server.MessageID,
+	actions ruleActions,
+	now time.Time,
+) alertmanager.Alert {
+	gotilertID := messageIdentifier.String()
+
+	templateData := &templating.Data{
+		Title:      msg.Title,
+		Message:    msg.Message,
+		Priority:   msg.Priority,
+		AppName:    app.Name,
+		Extras:     templating.NumericExtras(msg.Extras),
+		GotilertID: gotilertID,
 	}
 
-	maps.Copy(dst, src)
+	// Merge: defaults.labels + app.labels + computed labels + defaults.forcedLabels (last wins).
+	labels := renderTemplates(fwd.defaultLabels, templateData)
+	mergeStringMap(labels, renderTemplates(app.Labels, templateData))
+
+	labels["alertname"] = fwd.alertNameFor(app, msg)
+	labels["app"] = app.Name
+	labels["severity"] = mapping.Severity(fwd.severityMap(app), msg.Priority, fwd.cfg.Defaults.PriorityMatch)
+	labels["priority"] = strconv.Itoa(msg.Priority)
+
+	if !fwd.gotilertIDAsAnnotation(app) {
+		labels[gotilertIDKey] = gotilertID
+	}
+
+	for _, ruleLabels := range actions.labels {
+		mergeStringMap(labels, renderTemplates(ruleLabels, templateData))
+	}
+
+	if actions.severity != "" {
+		labels["severity"] = actions.severity
+	}
+
+	mergeStringMap(labels, renderTemplates(fwd.forcedLabels, templateData))
+
+	labels, changedLabels := sanitizeLabels(labels)
+	if len(changedLabels) > 0 {
+		fwd.metrics.AddSanitizedLabels(app.Name, len(changedLabels))
+		logger.L().Debug("sanitized alert labels", "app", app.Name, "keys", strings.Join(changedLabels, ","))
+	}
+
+	// Merge: defaults.annotations + app.annotations + computed annotations (computed wins).
+	annotations := renderTemplates(fwd.defaultAnnotations, templateData)
+	mergeStringMap(annotations, renderTemplates(app.Annotations, templateData))
+
+	annotations["summary"] = pickSummary(app.Name, msg.Title, msg.Message, fwd.cfg.Defaults.SummaryMaxLen)
+	annotations["description"] = msg.Message
+
+	if fwd.gotilertIDAsAnnotation(app) {
+		annotations[gotilertIDKey] = gotilertID
+	}
+
+	mergeStringMap(annotations, gotify.ExtrasAnnotations(msg.Extras, fwd.extrasPolicyFor(app)))
+
+	// alertname and gotilert_id are never cut: they identify the alert, and truncation would
+	// merge distinct alerts that only differ past the limit.
+	truncated := truncateValues(labels, fwd.cfg.Defaults.ValueMaxLen, "alertname", gotilertIDKey)
+	truncated = append(truncated, truncateValues(annotations, fwd.cfg.Defaults.ValueMaxLen)...)
+	if len(truncated) > 0 {
+		fwd.metrics.AddTruncatedValues(app.Name, len(truncated))
+		logger.L().Debug("truncated alert label/annotation values", "app", app.Name, "keys", strings.Join(truncated, ","))
+	}
+
+	return alertmanager.Alert{
+		Labels:       labels,
+		Annotations:  annotations,
+		StartsAt:     now.Add(-fwd.cfg.Defaults.StartsAtBackdate.Duration),
+		EndsAt:       now.Add(fwd.ttlFor(app, msg.Priority)),
+		GeneratorURL: fwd.renderGeneratorURL(app, templateData),
+	}
 }
 
-func severityForPriority(mapping map[int]string, priority int) string {
-	if sev, ok := mapping[priority]; ok {
-		return sev
+// severityMap returns the app's severity mapping, falling back to defaults.severityFromPriority.
+func (fwd *forwarder) severityMap(app server.App) map[int]string {
+	if len(app.SeverityFromPriority) > 0 {
+		return app.SeverityFromPriority
 	}
 
-	// Choose the closest lower key if possible; otherwise the smallest key.
-	bestKey := 0
-	bestSet := false
+	return fwd.cfg.Defaults.SeverityFromPriority
+}
 
-	for key := range mapping {
-		if !bestSet {
-			bestKey = key
-			bestSet = true
+// ttlFor returns the TTL for priority: the app's (or else the default) ttlFromPriority entry
+// for the closest key at or below priority, falling back to defaults.ttl.
+func (fwd *forwarder) ttlFor(app server.App, priority int) time.Duration {
+	ttls := fwd.ttlMapFor(app)
 
-			continue
-		}
+	key, ok := mapping.FloorKey(ttls, priority)
+	if !ok {
+		return fwd.cfg.Defaults.TTL.Duration
+	}
 
-		if key <= priority && bestKey <= priority {
-			if key > bestKey {
-				bestKey = key
-			}
+	return ttls[key]
+}
 
-			continue
+// ttlMapFor returns the app's ttlFromPriority, falling back to defaults.ttlFromPriority.
+func (fwd *forwarder) ttlMapFor(app server.App) map[int]time.Duration {
+	if len(app.TTLFromPriority) > 0 {
+		return app.TTLFromPriority
+	}
+
+	return fwd.defaultTTLs
+}
+
+// alertName returns the app's alertname, falling back to defaults.alertname.
+func (fwd *forwarder) alertName(app server.App) string {
+	if alertName := strings.TrimSpace(app.AlertName); alertName != "" {
+		return alertName
+	}
+
+	return fwd.cfg.Defaults.AlertName
+}
+
+// minForwardPriority returns the app's forwarding threshold, falling back to
+// defaults.minForwardPriority.
+func (fwd *forwarder) minForwardPriority(app server.App) int {
+	if app.MinForwardPriority != nil {
+		return *app.MinForwardPriority
+	}
+
+	return fwd.cfg.Defaults.MinForwardPriority
+}
+
+// alertNameFor returns the sanitized message title when the app sets alertnameFromTitle and
+// the title is usable, else the configured alertname.
+func (fwd *forwarder) alertNameFor(app server.App, msg gotify.MessageRequest) string {
+	if app.AlertnameFromTitle {
+		if alertName := sanitizeLabelValue(msg.Title); alertName != "" {
+			return alertName
 		}
+	}
+
+	return fwd.alertName(app)
+}
+
+// generatorURLFor returns the app's generatorURL template, falling back to defaults.generatorURL
+// (nil when neither is set).
+func (fwd *forwarder) generatorURLFor(app server.App) *templating.Map {
+	if app.GeneratorURL != nil {
+		return app.GeneratorURL
+	}
+
+	return fwd.defaultGeneratorURL
+}
+
+// renderGeneratorURL renders the app's generatorURL. A failed render or a result that is not an
+// absolute http(s) URL is logged and omitted rather than sent to Alertmanager.
+func (fwd *forwarder) renderGeneratorURL(app server.App, data *templating.Data) string {
+	tmpl := fwd.generatorURLFor(app)
+	if tmpl == nil {
+		return ""
+	}
+
+	rendered, err := tmpl.Render(data)
+	if err != nil {
+		logger.L().Warn("generatorURL rendering failed, omitted", "err", err, "app", app.Name)
+
+		return ""
+	}
+
+	generatorURL := strings.TrimSpace(rendered[generatorURLKey])
+	if generatorURL == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(generatorURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		logger.L().Warn("generatorURL is not an absolute http(s) URL, omitted",
+			"app", app.Name,
+			"generatorURL", logger.RedactURL(generatorURL),
+		)
+
+		return ""
+	}
 
-		if bestKey > priority && key < bestKey {
-			bestKey = key
+	return generatorURL
+}
+
+// gotilertIDAsAnnotation reports whether gotilert_id is sent as an annotation instead of a label,
+// falling back to defaults.gotilertIdAsAnnotation.
+func (fwd *forwarder) gotilertIDAsAnnotation(app server.App) bool {
+	if app.GotilertIDAsAnnotation != nil {
+		return *app.GotilertIDAsAnnotation
+	}
+
+	return fwd.cfg.Defaults.GotilertIDAsAnnotation
+}
+
+// extrasPolicyFor returns the app's extras policy, falling back to defaults.extras.
+func (fwd *forwarder) extrasPolicyFor(app server.App) gotify.ExtrasPolicy {
+	if app.ExtrasPolicy != nil {
+		return *app.ExtrasPolicy
+	}
+
+	return fwd.extrasPolicy
+}
+
+// post sends alerts upstream, recording metrics and logging failures with upstream details.
+func (fwd *forwarder) post(ctx context.Context, appName string, alerts []alertmanager.Alert) error {
+	forwardCtx, cancel := withBoundedTimeout(withAppName(ctx, appName), fwd.cfg.Alertmanager.Timeout.Duration)
+	defer cancel()
+
+	start := time.Now()
+	postErr := fwd.postAlerts(forwardCtx, alerts)
+
+	// A queued post is observed and counted by the queue once it is delivered.
+	queued := fwd.isQueued(ctx)
+	if !queued {
+		fwd.metrics.ObserveForward(appName, time.Since(start), server.TraceFromContext(ctx))
+	}
+
+	if errors.Is(postErr, alertmanager.ErrQueueFull) {
+		fwd.metrics.IncForwardQueueDropped(appName, metrics.QueueDropFull)
+		logger.L().Warn("async forward queue is full; rejecting message",
+			"request_id", server.RequestIDFromContext(ctx),
+			"app", appName,
+		)
+
+		return fmt.Errorf("%w: %w", server.ErrForwardQueueFull, postErr)
+	}
+
+	if errors.Is(postErr, alertmanager.ErrConcurrencyLimit) {
+		logger.L().Warn("no free alertmanager forward slot; rejecting message",
+			"err", postErr,
+			"request_id", server.RequestIDFromContext(ctx),
+			"app", appName,
+		)
+
+		return fmt.Errorf("%w: %w", server.ErrForwardBusy, postErr)
+	}
+
+	if postErr != nil {
+		if fwd.metrics != nil {
+			fwd.metrics.IncUpstreamFailure(appName)
 		}
+
+		logger.L().Error("forward to alertmanager failed", forwardFailureLogArgs(ctx, fwd.cfg, appName, postErr)...)
+
+		return fmt.Errorf("post alert: %w", postErr)
 	}
 
-	if sev, ok := mapping[bestKey]; ok {
-		return sev
+	if fwd.metrics != nil && !queued {
+		fwd.metrics.IncForwarded(appName)
 	}
 
-	return "info"
+	return nil
 }
 
-func copyLabels(input map[string]string) map[string]string {
-	out := make(map[string]string, len(input))
-	maps.Copy(out, input)
+func (fwd *forwarder) isQueued(ctx context.Context) bool {
+	return fwd.queued != nil && fwd.queued(ctx)
+}
 
-	return out
+// forwardFailureLogArgs makes auth/upstream issues debuggable (e.g., 401 with WWW-Authenticate).
+func forwardFailureLogArgs(ctx context.Context, cfg *config.Config, appName string, err error) []any {
+	logArgs := []any{
+		"err", err,
+		"request_id", server.RequestIDFromContext(ctx),
+		"app", appName,
+		"upstream", strings.Join(logger.RedactURLs(cfg.Alertmanager.PeerURLs()), ","),
+	}
+
+	var stErr alertmanager.HTTPStatusError
+	if errors.As(err, &stErr) {
+		logArgs = append(logArgs,
+			"upstream_status", stErr.StatusCode(),
+			"upstream_body", stErr.Body(),
+		)
+	}
+
+	return logArgs
+}
+
+func mergeStringMap(dst, src map[string]string) {
+	if len(src) == 0 {
+		return
+	}
+
+	maps.Copy(dst, src)
 }
 
-func pickSummary(appName, title, message string) string {
+// pickSummary returns the title, else the message cut to at most maxLen runes (the last one
+// an ellipsis when cut), else the app name. Invalid UTF-8 is replaced, so the result is
+// always valid.
+func pickSummary(appName, title, message string, maxLen int) string {
 	trimmedTitle := strings.TrimSpace(title)
 	if trimmedTitle != "" {
 		return trimmedTitle
 	}
 
-	trimmedMessage := strings.TrimSpace(message)
+	trimmedMessage := strings.ToValidUTF8(strings.TrimSpace(message), string(utf8.RuneError))
 	if trimmedMessage == "" {
 		return appName
 	}
 
-	const maxLen = 120
-	if len(trimmedMessage) <= maxLen {
+	if maxLen <= 0 {
+		maxLen = config.DefaultSummaryMaxLen
+	}
+
+	if utf8.RuneCountInString(trimmedMessage) <= maxLen {
 		return trimmedMessage
 	}
 
-	return trimmedMessage[:maxLen] + "…"
+	runes := []rune(trimmedMessage)
+
+	return string(runes[:maxLen-1]) + ellipsis
 }
 
-func runHTTPServer(httpServer *http.Server, shutdownTimeout time.Duration) error {
+func runService(svc *service) error {
 	errorChan := make(chan error, 1)
 
+	if svc.readyPoller != nil {
+		svc.readyPoller.start()
+	}
+
+	startupErrChan := make(chan error, 1)
+	cancelStartup := func() {}
+
+	if svc.startupGate != nil {
+		startupCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cancelStartup = cancel
+
+		go func() {
+			startupErrChan <- svc.startupGate.wait(startupCtx)
+		}()
+	}
+
 	go func() {
-		errorChan <- server.ListenAndServe(httpServer)
+		errorChan <- server.ListenAndServe(svc.httpServer)
 	}()
 
-	logger.L().Info("http server listening", "addr", httpServer.Addr)
+	logger.L().Info("http server listening", "addr", svc.httpServer.Addr, "tls", svc.httpServer.TLSConfig != nil)
 
 	signalChan := make(chan os.Signal, 1)
 
-	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer signal.Stop(signalChan)
 
-	select {
-	case sig := <-signalChan:
-		logger.L().Info("shutdown requested", "signal", sig.String())
+	for {
+		select {
+		case sig := <-signalChan:
+			if sig == syscall.SIGHUP {
+				svc.reloadOnSignal()
+
+				continue
+			}
+
+			// Stop waiting for Alertmanager before draining, so the startup gate cannot
+			// report a late failure while shutting down.
+			cancelStartup()
+			logger.L().Info("shutdown requested", "signal", sig.String())
+
+			err := svc.shutdown(context.Background())
+			if err != nil {
+				return err
+			}
+
+			logger.L().Info("shutdown complete")
+
+			return nil
+
+		case err := <-startupErrChan:
+			if err == nil {
+				continue
+			}
+
+			shutdownErr := svc.shutdown(context.Background())
+			if shutdownErr != nil {
+				logger.L().Warn("shutdown after failed startup wait", "err", shutdownErr)
+			}
+
+			return err
+
+		case err := <-errorChan:
+			if err == nil || errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("http server error: %w", err)
+		}
+	}
+}
+
+// reloadOnSignal runs the same reload as POST /-/reload; failures keep the previous config.
+func (svc *service) reloadOnSignal() {
+	logger.L().Info("reload requested", "signal", syscall.SIGHUP.String())
+
+	err := svc.reloader.Reload(context.Background())
+	if err != nil {
+		logger.L().Warn("reload failed; keeping previous configuration", "err", err)
+	}
+}
+
+// shutdown flips readiness off and waits drainDelay for load balancers to notice, then stops
+// the HTTP server (so no new alerts arrive) and finally drains queued and buffered alerts.
+func (svc *service) shutdown(ctx context.Context) error {
+	svc.drain(ctx)
+
+	// Every step runs even when an earlier one failed, so background workers and buffered
+	// alerts are never abandoned; the errors are reported together.
+	var errs []error
+
+	err := server.Shutdown(ctx, svc.httpServer, svc.shutdownTimeout)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("shutdown http server: %w", err))
+	}
+
+	if svc.readyPoller != nil {
+		svc.readyPoller.Close()
+	}
+
+	// Pending resolutions would post into the queue being closed below.
+	if svc.reloader != nil {
+		svc.reloader.autoResolve.Close()
+	}
 
-		ctx := context.Background()
+	// The queue drains into the batcher, so it must be closed first.
+	if svc.queue != nil {
+		drainCtx, cancel := context.WithTimeout(ctx, svc.shutdownTimeout)
+		defer cancel()
 
-		err := server.Shutdown(ctx, httpServer, shutdownTimeout)
+		err = svc.queue.Close(drainCtx)
 		if err != nil {
-			return fmt.Errorf("shutdown http server: %w", err)
+			errs = append(errs, fmt.Errorf("drain alertmanager forward queue: %w", err))
 		}
+	}
 
-		logger.L().Info("shutdown complete")
+	if svc.batcher != nil {
+		flushCtx, cancel := context.WithTimeout(ctx, svc.shutdownTimeout)
+		defer cancel()
 
-		return nil
+		err = svc.batcher.Close(flushCtx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("flush alertmanager batcher: %w", err))
+		}
+	}
 
-	case err := <-errorChan:
-		if err == nil || errors.Is(err, http.ErrServerClosed) {
-			return nil
+	if svc.reloader != nil {
+		err = svc.reloader.audit.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("close audit log: %w", err))
 		}
+	}
 
-		return fmt.Errorf("http server error: %w", err)
+	if svc.shutdownTracing != nil {
+		flushCtx, cancel := context.WithTimeout(ctx, svc.shutdownTimeout)
+		defer cancel()
+
+		err = svc.shutdownTracing(flushCtx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// alertmanagerReadyFunc checks the current Alertmanager client and feeds the readiness metrics.
+func alertmanagerReadyFunc(rel *reloader, metricsCollector *metrics.Metrics, timeout time.Duration) server.ReadyFunc {
+	return func() (bool, string) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		start := time.Now()
+		readyErr := rel.client().Ready(ctx)
+
+		metricsCollector.ObserveReadyCheck(time.Since(start))
+		metricsCollector.SetAlertmanagerReady(readyErr == nil)
+
+		if readyErr != nil {
+			return false, readyErr.Error()
+		}
+
+		return true, ""
+	}
+}
+
+// readyUnlessDraining reports not ready once draining is set, without running the upstream check.
+func readyUnlessDraining(draining *atomic.Bool, ready server.ReadyFunc) server.ReadyFunc {
+	return func() (bool, string) {
+		if draining.Load() {
+			return false, "shutting down"
+		}
+
+		return ready()
+	}
+}
+
+// drain marks the service as not ready and keeps serving for drainDelay (or until ctx is done).
+func (svc *service) drain(ctx context.Context) {
+	if svc.draining != nil {
+		svc.draining.Store(true)
+	}
+
+	if svc.drainDelay <= 0 {
+		return
+	}
+
+	logger.L().Info("draining before shutdown", "delay", svc.drainDelay.String())
+
+	timer := time.NewTimer(svc.drainDelay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
 	}
 }
 
@@ -440,11 +1525,16 @@ func parseCLI(args []string, stderr io.Writer) (cliOptions, error) {
 	flagSet.SetOutput(stderr)
 
 	showVersion := flagSet.Bool("version", false, "Print version information and exit.")
-	configFile := flagSet.String("config.file", "", "Path to gotilert YAML configuration file.")
+	configFile := flagSet.String("config.file", "", "Path to gotilert YAML configuration file, or a directory of *.yaml fragments.")
+	checkConfig := flagSet.Bool("check-config", false, "Validate the configuration file, print one line per check and exit.")
+	printConfig := flagSet.Bool("print-config", false, "Print the effective configuration (secrets redacted) as YAML and exit.")
 
-	logFormat := flagSet.String("log-format", "plain", "Log format: plain, text, json.")
+	logFormat := flagSet.String("log-format", "plain", "Log format: plain, text, logfmt, json.")
 	logLevel := flagSet.String("log-level", "info", "Log level: debug, info, warn, error.")
 	logTime := flagSet.Bool("log-time", false, "Include time field in logs.")
+	logOutput := flagSet.String("log-output", logger.OutputStdout, "Log output: stdout, stderr or a file path.")
+
+	pprofEnabled := flagSet.Bool("pprof", false, "Expose net/http/pprof under /debug/pprof/ (overrides server.pprof).")
 
 	err := flagSet.Parse(args)
 	if err != nil {
@@ -459,10 +1549,14 @@ func parseCLI(args []string, stderr io.Writer) (cliOptions, error) {
 
 	return cliOptions{
 		showVersion: *showVersion,
+		checkConfig: *checkConfig,
+		printConfig: *printConfig,
 		configFile:  *configFile,
 		logFormat:   *logFormat,
 		logLevel:    *logLevel,
 		logTime:     *logTime,
+		logOutput:   *logOutput,
+		pprof:       *pprofEnabled,
 		overrides:   overrides,
 	}, nil
 }
@@ -475,7 +1569,7 @@ func loadConfigOrExit(configFile string) (*config.Config, error) {
 		return nil, ErrConfigFileMissing
 	}
 
-	cfg, err := config.LoadFile(configFile)
+	cfg, err := config.Load(configFile)
 	if err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
@@ -485,10 +1579,27 @@ func loadConfigOrExit(configFile string) (*config.Config, error) {
 	return cfg, nil
 }
 
-func applyLoggingConfig(cfg *config.Config, options cliOptions) {
+// applyServerOverrides applies explicitly set CLI flags on top of the server config.
+func applyServerOverrides(cfg *config.Config, options cliOptions) {
+	if options.overrides["pprof"] {
+		cfg.Server.Pprof = options.pprof
+	}
+
+	if cfg.Server.Pprof {
+		logger.L().Warn("pprof enabled on /debug/pprof/; restrict access at the network layer")
+	}
+}
+
+func applyLoggingConfig(cfg *config.Config, options cliOptions) error {
 	effectiveFormat := options.logFormat
 	effectiveLevel := options.logLevel
 	effectiveIncludeTime := options.logTime
+	effectiveOutput := logger.Output{
+		Path:       options.logOutput,
+		MaxSizeMB:  cfg.Logging.MaxSizeMB,
+		MaxBackups: cfg.Logging.MaxBackups,
+		MaxAgeDays: cfg.Logging.MaxAgeDays,
+	}
 
 	if !options.overrides["log-format"] && cfg.Logging.Format != "" {
 		effectiveFormat = cfg.Logging.Format
@@ -502,17 +1613,44 @@ func applyLoggingConfig(cfg *config.Config, options cliOptions) {
 		effectiveIncludeTime = cfg.Logging.IncludeTime
 	}
 
+	if !options.overrides["log-output"] && cfg.Logging.Output != "" {
+		effectiveOutput.Path = cfg.Logging.Output
+	}
+
+	sampling := logger.Sampling{
+		Initial:    cfg.Logging.Sampling.Initial,
+		Thereafter: cfg.Logging.Sampling.Thereafter,
+	}
+
+	sameOutput := effectiveOutput == logger.Output{Path: options.logOutput}
+
 	if effectiveFormat == options.logFormat && effectiveLevel == options.logLevel &&
-		effectiveIncludeTime == options.logTime {
-		return
+		effectiveIncludeTime == options.logTime && sameOutput && !sampling.Enabled() {
+		return nil
 	}
 
-	logger.Configure(effectiveFormat, effectiveLevel, effectiveIncludeTime)
+	writer := options.logWriter
+	if !sameOutput {
+		var err error
+
+		writer, err = logger.OpenOutput(effectiveOutput)
+		if err != nil {
+			return fmt.Errorf("logging.output: %w", err)
+		}
+	}
+
+	logger.Configure(writer, effectiveFormat, effectiveLevel, effectiveIncludeTime)
+	logger.EnableSampling(sampling)
 	logger.L().Info("logger configured from config (unless overridden by CLI)",
 		"format", effectiveFormat,
 		"level", effectiveLevel,
 		"includeTime", effectiveIncludeTime,
+		"output", effectiveOutput.Path,
+		"samplingInitial", sampling.Initial,
+		"samplingThereafter", sampling.Thereafter,
 	)
+
+	return nil
 }
 
 func printVersion(writer io.Writer) error {
@@ -528,6 +1666,16 @@ func printVersion(writer io.Writer) error {
 	return nil
 }
 
+// buildInfo returns the metadata printed by --version, plus the Go version, for GET /version.
+func buildInfo() *server.BuildInfo {
+	return &server.BuildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+		Go:      runtime.Version(),
+	}
+}
+
 func pickDuration(value, fallback time.Duration) time.Duration {
 	if value == 0 {
 		return fallback
@@ -536,6 +1684,14 @@ func pickDuration(value, fallback time.Duration) time.Duration {
 	return value
 }
 
+func pickInt(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+
+	return value
+}
+
 func withBoundedTimeout(
 	parent context.Context,
 	timeout time.Duration,
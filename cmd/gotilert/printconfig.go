@@ -0,0 +1,192 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// effectiveConfig is what --print-config emits: the redacted config with every app
+// resolved against the defaults exactly as the forwarder does at runtime.
+type effectiveConfig struct {
+	Server       config.ServerConfig       `yaml:"server"`
+	Gotify       config.GotifyConfig       `yaml:"gotify"`
+	Logging      config.LoggingConfig      `yaml:"logging"`
+	Metrics      config.MetricsConfig      `yaml:"metrics"`
+	Tracing      config.TracingConfig      `yaml:"tracing"`
+	Alertmanager config.AlertmanagerConfig `yaml:"alertmanager"`
+	Defaults     config.DefaultsConfig     `yaml:"defaults"`
+	Apps         map[string]effectiveApp   `yaml:"apps"`
+}
+
+// effectiveApp holds an app's merged settings. Labels and annotations are the unrendered
+// templates; computed labels (alertname, severity, gotilert_id, ...) are added per message.
+type effectiveApp struct {
+	Tokens                 []string                `yaml:"tokens,omitempty"`
+	AppName                string                  `yaml:"appName"`
+	AppID                  uint32                  `yaml:"appId"`
+	AlertName              string                  `yaml:"alertname"`
+	AlertnameFromTitle     bool                    `yaml:"alertnameFromTitle"`
+	MinForwardPriority     int                     `yaml:"minForwardPriority"`
+	SeverityFromPriority   map[int]string          `yaml:"severityFromPriority"`
+	TTL                    config.Duration         `yaml:"ttl"`
+	TTLFromPriority        map[int]config.Duration `yaml:"ttlFromPriority,omitempty"`
+	Labels                 map[string]string       `yaml:"labels"`
+	Annotations            map[string]string       `yaml:"annotations"`
+	GeneratorURL           string                  `yaml:"generatorURL"`
+	GotilertIDAsAnnotation bool                    `yaml:"gotilertIdAsAnnotation"`
+	Resolve                config.ResolveConfig    `yaml:"resolve"`
+	Extras                 config.ExtrasConfig     `yaml:"extras"`
+	RateLimit              config.RateLimitConfig  `yaml:"rateLimit"`
+	SigningSecret          string                  `yaml:"signingSecret,omitempty"`
+}
+
+// printConfig writes the effective configuration as YAML, with secrets redacted.
+func printConfig(stdout io.Writer, cfg *config.Config) error {
+	if stdout == nil {
+		return ErrNilStdoutWriter
+	}
+
+	effective, err := newEffectiveConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(stdout)
+	encoder.SetIndent(2)
+
+	err = encoder.Encode(effective)
+	if err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	return nil
+}
+
+func newEffectiveConfig(cfg *config.Config) (*effectiveConfig, error) {
+	apps, err := buildApps(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err := buildForwarder(cfg, nil, nil, newFiringAlerts())
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := cfg.Redacted()
+	keys := cfg.RedactedAppKeys()
+
+	effective := &effectiveConfig{
+		Server:       redacted.Server,
+		Gotify:       redacted.Gotify,
+		Logging:      redacted.Logging,
+		Metrics:      redacted.Metrics,
+		Tracing:      redacted.Tracing,
+		Alertmanager: redacted.Alertmanager,
+		Defaults:     redacted.Defaults,
+		Apps:         make(map[string]effectiveApp, len(apps)),
+	}
+
+	// Additional tokens resolve to the same app; print each app once, under its key.
+	for token, appConfig := range cfg.Apps {
+		app := fwd.effectiveApp(apps[token])
+		app.Tokens = config.RedactTokens(appConfig.Tokens)
+		effective.Apps[keys[token]] = app
+	}
+
+	return effective, nil
+}
+
+func (fwd *forwarder) effectiveApp(app server.App) effectiveApp {
+	labels := fwd.defaultLabels.Source()
+	mergeStringMap(labels, app.Labels.Source())
+	mergeStringMap(labels, fwd.forcedLabels.Source())
+
+	annotations := fwd.defaultAnnotations.Source()
+	mergeStringMap(annotations, app.Annotations.Source())
+
+	extrasPolicy := fwd.extrasPolicyFor(app)
+
+	return effectiveApp{
+		AppName:                app.Name,
+		AppID:                  app.ID,
+		AlertName:              fwd.alertName(app),
+		AlertnameFromTitle:     app.AlertnameFromTitle,
+		MinForwardPriority:     fwd.minForwardPriority(app),
+		SeverityFromPriority:   fwd.severityMap(app),
+		TTL:                    fwd.cfg.Defaults.TTL,
+		TTLFromPriority:        configTTLMap(fwd.ttlMapFor(app)),
+		Labels:                 labels,
+		Annotations:            annotations,
+		GeneratorURL:           fwd.generatorURLFor(app).Source()[generatorURLKey],
+		GotilertIDAsAnnotation: fwd.gotilertIDAsAnnotation(app),
+		Resolve: config.ResolveConfig{
+			Priority:  app.Resolve.Priority,
+			ExtrasKey: app.Resolve.ExtrasKey,
+		},
+		Extras: config.ExtrasConfig{
+			Allow:       extrasPolicy.Allow,
+			Deny:        extrasPolicy.Deny,
+			Passthrough: extrasPolicy.Passthrough,
+		},
+		RateLimit:     config.RateLimitConfig{RPS: app.RateLimit.RPS, Burst: app.RateLimit.Burst},
+		SigningSecret: redactedSecret(app.SigningSecret),
+	}
+}
+
+func configTTLMap(input map[int]time.Duration) map[int]config.Duration {
+	if len(input) == 0 {
+		return nil
+	}
+
+	out := make(map[int]config.Duration, len(input))
+	for priority, ttl := range input {
+		out[priority] = config.Duration{Duration: ttl}
+	}
+
+	return out
+}
+
+// redactedSecret masks a non-empty secret like config.Redacted does.
+func redactedSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return config.RedactedValue
+}
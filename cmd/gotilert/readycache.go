@@ -0,0 +1,72 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// readyCache memoizes a ReadyFunc result for ttl. The mutex is held during the check, so
+// concurrent probes on a miss share a single upstream call.
+type readyCache struct {
+	ready server.ReadyFunc
+	ttl   time.Duration
+	now   func() time.Time
+
+	mutex     sync.Mutex
+	checkedAt time.Time
+	ok        bool
+	reason    string
+}
+
+// cachedReady wraps ready so repeated calls within ttl reuse the last result; ttl <= 0
+// returns ready unchanged.
+func cachedReady(ready server.ReadyFunc, ttl time.Duration) server.ReadyFunc {
+	if ttl <= 0 {
+		return ready
+	}
+
+	cache := &readyCache{ready: ready, ttl: ttl, now: time.Now}
+
+	return cache.check
+}
+
+func (cache *readyCache) check() (bool, string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := cache.now()
+	if !cache.checkedAt.IsZero() && now.Sub(cache.checkedAt) < cache.ttl {
+		return cache.ok, cache.reason
+	}
+
+	cache.ok, cache.reason = cache.ready()
+	cache.checkedAt = now
+
+	return cache.ok, cache.reason
+}
@@ -0,0 +1,85 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCachedReadyReusesResultWithinTTL(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	now := time.Now()
+
+	cache := &readyCache{
+		ready: func() (bool, string) {
+			calls++
+
+			return false, "alertmanager down"
+		},
+		ttl: 5 * time.Second,
+		now: func() time.Time { return now },
+	}
+
+	for range 3 {
+		ok, reason := cache.check()
+		if ok || reason != "alertmanager down" {
+			t.Fatalf("expected cached not-ready result, got ok=%t reason=%q", ok, reason)
+		}
+
+		now = now.Add(time.Second)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected 1 upstream call within the TTL, got %d", calls)
+	}
+
+	now = now.Add(5 * time.Second)
+	cache.check()
+
+	if calls != 2 {
+		t.Fatalf("expected a new upstream call after the TTL, got %d calls", calls)
+	}
+}
+
+func TestCachedReadyDisabledWithoutTTL(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	ready := cachedReady(func() (bool, string) {
+		calls++
+
+		return true, ""
+	}, 0)
+
+	ready()
+	ready()
+
+	if calls != 2 {
+		t.Fatalf("expected every call to reach the check, got %d calls", calls)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// readyNotChecked is reported until the poller's first check completes.
+const readyNotChecked = "readiness not checked yet"
+
+type readyResult struct {
+	ok     bool
+	reason string
+}
+
+// readyPoller runs a readiness check every interval in the background and serves the last
+// result, so /readyz answers instantly regardless of Alertmanager latency.
+type readyPoller struct {
+	check    server.ReadyFunc
+	interval time.Duration
+
+	result atomic.Pointer[readyResult]
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newReadyPoller(check server.ReadyFunc, interval time.Duration) *readyPoller {
+	poller := &readyPoller{
+		check:    check,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	poller.result.Store(&readyResult{reason: readyNotChecked})
+
+	return poller
+}
+
+// start runs the first check immediately, then one per interval until Close.
+func (poller *readyPoller) start() {
+	go func() {
+		defer close(poller.done)
+
+		ticker := time.NewTicker(poller.interval)
+		defer ticker.Stop()
+
+		for {
+			ok, reason := poller.check()
+			poller.result.Store(&readyResult{ok: ok, reason: reason})
+
+			select {
+			case <-poller.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// ready returns the last polled result without calling the check.
+func (poller *readyPoller) ready() (bool, string) {
+	result := poller.result.Load()
+
+	return result.ok, result.reason
+}
+
+// Close stops polling and waits for an in-flight check to finish. It must follow start.
+func (poller *readyPoller) Close() {
+	poller.stopOnce.Do(func() { close(poller.stop) })
+	<-poller.done
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadyPollerServesLastResult(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	poller := newReadyPoller(func() (bool, string) {
+		calls.Add(1)
+
+		return false, "alertmanager down"
+	}, time.Hour)
+
+	if ok, reason := poller.ready(); ok || reason != readyNotChecked {
+		t.Fatalf("expected not ready before the first check, got ok=%t reason=%q", ok, reason)
+	}
+
+	poller.start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	poller.Close()
+
+	for range 10 {
+		if ok, reason := poller.ready(); ok || reason != "alertmanager down" {
+			t.Fatalf("expected polled not-ready result, got ok=%t reason=%q", ok, reason)
+		}
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 background check, got %d", got)
+	}
+}
+
+func TestReadyPollerRefreshesEveryInterval(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	poller := newReadyPoller(func() (bool, string) {
+		return calls.Add(1) > 1, ""
+	}, 5*time.Millisecond)
+	poller.start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if ok, _ := poller.ready(); ok {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	poller.Close()
+
+	if ok, _ := poller.ready(); !ok {
+		t.Fatalf("expected ready after a later poll succeeded")
+	}
+
+	stopped := calls.Load()
+
+	time.Sleep(20 * time.Millisecond)
+
+	if got := calls.Load(); got != stopped {
+		t.Fatalf("expected no checks after Close, got %d more", got-stopped)
+	}
+}
@@ -0,0 +1,290 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/audit"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// tracer resolves through the global provider, so spans are no-ops unless tracing.enabled.
+var tracer = otel.Tracer("github.com/leinardi/gotilert/cmd/gotilert")
+
+// runtimeState is everything derived from a loaded config that can be swapped on reload.
+// Listener settings (address, timeouts) and the post chain (batching, async, circuit
+// breaker, concurrency limit) still require a restart.
+type runtimeState struct {
+	cfg        *config.Config
+	amClient   *alertmanager.Client
+	resolveApp server.ResolveAppFunc
+	forward    server.ForwardMessageFunc
+	testAlert  server.TestAlertFunc
+}
+
+// reloader owns the current runtimeState and swaps it atomically, so in-flight requests
+// keep using the state they started with.
+type reloader struct {
+	configPath string
+	metrics    *metrics.Metrics
+	postAlerts alertmanager.PostFunc
+
+	// syncPostAlerts is postAlerts without the async queue, used for posts marked by
+	// withSyncPost (e.g. POST /-/test). It equals postAlerts when async is off.
+	syncPostAlerts alertmanager.PostFunc
+
+	// async is set once the alertmanager.async queue sits in front of postAlerts; the
+	// queue is built at startup and cannot be toggled by a reload.
+	async bool
+
+	// firing outlives reloads so resolutions still match alerts fired before a reload.
+	firing *firingAlerts
+
+	// audit is opened once from logging.auditFile; changing the path needs a restart.
+	audit *audit.Log
+
+	// autoResolve outlives reloads so resolutions scheduled before a reload still fire.
+	autoResolve *autoResolver
+
+	// mutex serializes reloads; readers only use the atomic pointer.
+	mutex sync.Mutex
+	state atomic.Pointer[runtimeState]
+}
+
+func newReloader(configPath string, cfg *config.Config, metricsCollector *metrics.Metrics) (*reloader, error) {
+	auditLog, err := audit.Open(cfg.Logging.AuditFile, auditErrorReporter(cfg.Logging.AuditFile, metricsCollector))
+	if err != nil {
+		return nil, fmt.Errorf("logging.auditFile: %w", err)
+	}
+
+	rel := &reloader{
+		configPath: configPath,
+		metrics:    metricsCollector,
+		firing:     newFiringAlerts(),
+		audit:      auditLog,
+	}
+	rel.autoResolve = newAutoResolver(rel.forwardPost, metricsCollector)
+
+	state, err := rel.buildState(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rel.state.Store(state)
+
+	return rel, nil
+}
+
+// Reload re-reads the config file and swaps the runtime state. An invalid config leaves
+// the running state untouched and is reported as server.ErrReloadRejected.
+func (rel *reloader) Reload(_ context.Context) error {
+	rel.mutex.Lock()
+	defer rel.mutex.Unlock()
+
+	cfg, err := config.Load(rel.configPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", server.ErrReloadRejected, err)
+	}
+
+	previous := rel.state.Load()
+
+	// buildState only fails on settings Validate cannot check (e.g. unreadable TLS files).
+	state, err := rel.buildState(cfg, previous)
+	if err != nil {
+		return fmt.Errorf("%w: %w", server.ErrReloadRejected, err)
+	}
+
+	rel.state.Store(state)
+
+	logger.L().Info("configuration reloaded",
+		"path", rel.configPath,
+		"apps", len(cfg.Apps),
+		"alertmanagerClientRebuilt", state.amClient != previous.amClient,
+	)
+
+	if !reflect.DeepEqual(listenerSettings(previous.cfg.Server), listenerSettings(cfg.Server)) ||
+		postChainSettingsOf(&previous.cfg.Alertmanager) != postChainSettingsOf(&cfg.Alertmanager) ||
+		previous.cfg.Metrics != cfg.Metrics {
+		logger.L().Warn("server, alertmanager batching/async/circuitBreaker/maxConcurrency and metrics settings " +
+			"changed but require a restart to apply")
+	}
+
+	return nil
+}
+
+// listenerSettings drops the server fields that are applied per request (and thus reloadable).
+func listenerSettings(serverConfig config.ServerConfig) config.ServerConfig {
+	serverConfig.AdminToken = ""
+	serverConfig.RateLimit = config.RateLimitConfig{}
+
+	return serverConfig
+}
+
+// postChainSettings holds the alertmanager settings baked into the post chain at startup.
+type postChainSettings struct {
+	batching        config.BatchingConfig
+	async           config.AsyncConfig
+	circuitBreaker  config.CircuitBreakerConfig
+	maxConcurrency  int
+	concurrencyWait config.Duration
+}
+
+func postChainSettingsOf(amConfig *config.AlertmanagerConfig) postChainSettings {
+	return postChainSettings{
+		batching:        amConfig.Batching,
+		async:           amConfig.Async,
+		circuitBreaker:  amConfig.CircuitBreaker,
+		maxConcurrency:  amConfig.MaxConcurrency,
+		concurrencyWait: amConfig.ConcurrencyWait,
+	}
+}
+
+// buildState derives a runtimeState from cfg, reusing the previous Alertmanager client
+// when its configuration is unchanged.
+func (rel *reloader) buildState(cfg *config.Config, previous *runtimeState) (*runtimeState, error) {
+	var amClient *alertmanager.Client
+
+	if previous != nil && reflect.DeepEqual(previous.cfg.Alertmanager, cfg.Alertmanager) {
+		amClient = previous.amClient
+	} else {
+		newClient, err := newAlertmanagerClient(cfg, rel.metrics)
+		if err != nil {
+			return nil, err
+		}
+
+		amClient = newClient
+	}
+
+	resolveApp, err := newResolveAppFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err := buildForwarder(cfg, rel.forwardPost, rel.metrics, rel.firing)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd.audit = rel.audit
+	fwd.autoResolve = rel.autoResolve
+	fwd.queued = rel.queued
+
+	return &runtimeState{
+		cfg:        cfg,
+		amClient:   amClient,
+		resolveApp: resolveApp,
+		forward:    fwd.forward,
+		testAlert:  fwd.testAlert,
+	}, nil
+}
+
+func (rel *reloader) current() *runtimeState {
+	return rel.state.Load()
+}
+
+func (rel *reloader) client() *alertmanager.Client {
+	return rel.current().amClient
+}
+
+// forwardPost indirects through postAlerts, which is wired after construction
+// (it depends on the batcher, which in turn uses the current client).
+func (rel *reloader) forwardPost(ctx context.Context, alerts []alertmanager.Alert) error {
+	if isSyncPost(ctx) && rel.syncPostAlerts != nil {
+		return rel.syncPostAlerts(ctx, alerts)
+	}
+
+	return rel.postAlerts(ctx, alerts)
+}
+
+// queued reports whether forwardPost only enqueues the alerts for ctx, so delivery
+// metrics and audit records are left to the queue.
+func (rel *reloader) queued(ctx context.Context) bool {
+	return rel.async && !isSyncPost(ctx)
+}
+
+func (rel *reloader) resolveApp(token string) (server.App, bool) {
+	return rel.current().resolveApp(token)
+}
+
+func (rel *reloader) forward(
+	ctx context.Context,
+	app server.App,
+	msg gotify.MessageRequest,
+	messageIdentifier server.MessageID,
+) error {
+	ctx, span := tracer.Start(ctx, "gotilert.forward", trace.WithAttributes(
+		attribute.String("gotilert.app", app.Name),
+		attribute.Int("gotify.priority", msg.Priority),
+	))
+	defer span.End()
+
+	err := rel.current().forward(ctx, app, msg, messageIdentifier)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (rel *reloader) testAlert(ctx context.Context, app server.App, messageIdentifier server.MessageID) error {
+	ctx, span := tracer.Start(ctx, "gotilert.test_alert", trace.WithAttributes(
+		attribute.String("gotilert.app", app.Name),
+	))
+	defer span.End()
+
+	err := rel.current().testAlert(ctx, app, messageIdentifier)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (rel *reloader) authorizeAdmin(token string) bool {
+	adminToken := rel.current().cfg.Server.AdminToken
+	if adminToken == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(adminToken), []byte(token)) == 1
+}
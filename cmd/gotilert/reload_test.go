@@ -0,0 +1,73 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestReloadRejectsUnbuildableAlertmanagerClient(t *testing.T) {
+	t.Parallel()
+
+	configFile := writeCheckConfig(t, checkConfigYAML)
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+
+	rel, err := newReloader(configFile, cfg, nil)
+	if err != nil {
+		t.Fatalf("newReloader: %v", err)
+	}
+
+	// Valid for config.Load; only building the Alertmanager client fails.
+	err = os.WriteFile(configFile, []byte(strings.Replace(checkConfigYAML, `url: "http://localhost:9093"`, `url: "http://localhost:9093"
+  tlsConfig:
+    caFile: "/nonexistent/ca.pem"`, 1)), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	err = rel.Reload(context.Background())
+	if !errors.Is(err, server.ErrReloadRejected) {
+		t.Fatalf("expected ErrReloadRejected, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "ca.pem") {
+		t.Fatalf("expected the CA file error, got: %v", err)
+	}
+
+	if rel.current().cfg != cfg {
+		t.Fatalf("expected the running state to be kept")
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// maxFiringPerApp bounds how many firing alerts are remembered per app for later resolution.
+const maxFiringPerApp = 1000
+
+type firingAlert struct {
+	title string
+	alert alertmanager.Alert
+}
+
+// firingAlerts remembers alerts fired by apps with a resolve trigger. Alertmanager matches
+// alerts by their full label set, so a resolution must re-send the original labels.
+type firingAlerts struct {
+	mutex sync.Mutex
+	byApp map[string][]firingAlert
+}
+
+func newFiringAlerts() *firingAlerts {
+	return &firingAlerts{byApp: make(map[string][]firingAlert)}
+}
+
+func (firing *firingAlerts) track(appName, title string, alert alertmanager.Alert) {
+	firing.mutex.Lock()
+	defer firing.mutex.Unlock()
+
+	entries := append(pruneExpired(firing.byApp[appName], time.Now()), firingAlert{
+		title: strings.TrimSpace(title),
+		alert: alert,
+	})
+
+	if len(entries) > maxFiringPerApp {
+		entries = entries[len(entries)-maxFiringPerApp:]
+	}
+
+	firing.byApp[appName] = entries
+}
+
+// take removes and returns the app's firing alerts with the given title (all of them when
+// title is empty). Alerts already past their EndsAt are dropped.
+func (firing *firingAlerts) take(appName, title string, now time.Time) []firingAlert {
+	firing.mutex.Lock()
+	defer firing.mutex.Unlock()
+
+	title = strings.TrimSpace(title)
+
+	var taken, kept []firingAlert
+
+	for _, entry := range pruneExpired(firing.byApp[appName], now) {
+		if title == "" || entry.title == title {
+			taken = append(taken, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(firing.byApp, appName)
+	} else {
+		firing.byApp[appName] = kept
+	}
+
+	return taken
+}
+
+func pruneExpired(entries []firingAlert, now time.Time) []firingAlert {
+	kept := entries[:0]
+
+	for _, entry := range entries {
+		if entry.alert.EndsAt.After(now) {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept
+}
+
+// resolve re-posts the matching firing alerts with EndsAt=now so Alertmanager resolves them.
+func (fwd *forwarder) resolve(ctx context.Context, app server.App, msg gotify.MessageRequest) error {
+	now := time.Now().UTC()
+
+	entries := fwd.firing.take(app.Name, msg.Title, now)
+	if len(entries) == 0 {
+		logger.L().Info("no firing alerts to resolve",
+			"request_id", server.RequestIDFromContext(ctx),
+			"app", app.Name,
+			"title", msg.Title,
+		)
+
+		return nil
+	}
+
+	alerts := make([]alertmanager.Alert, 0, len(entries))
+
+	for _, entry := range entries {
+		resolved := entry.alert
+		resolved.EndsAt = now
+		alerts = append(alerts, resolved)
+	}
+
+	err := fwd.post(ctx, app.Name, alerts)
+	if err != nil {
+		// Keep them resolvable by a retried message.
+		for _, entry := range entries {
+			fwd.firing.track(app.Name, entry.title, entry.alert)
+		}
+
+		return err
+	}
+
+	fwd.autoResolve.cancelResolved(app.Name, alerts)
+
+	logger.L().Info("resolved alerts",
+		"request_id", server.RequestIDFromContext(ctx),
+		"app", app.Name,
+		"count", len(alerts),
+	)
+
+	return nil
+}
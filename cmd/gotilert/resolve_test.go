@@ -0,0 +1,106 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderResolvesFiringAlertOnTriggerPriority(t *testing.T) {
+	t.Parallel()
+
+	var posted [][]alertmanager.Alert
+
+	postAlerts := func(_ context.Context, alerts []alertmanager.Alert) error {
+		posted = append(posted, alerts)
+
+		return nil
+	}
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info", 5: "warning"},
+		},
+	}
+
+	forward, err := newForwarder(cfg, postAlerts, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("newForwarder: %v", err)
+	}
+
+	resolvePriority := 0
+	app := server.App{Name: "backup", Resolve: server.ResolveTrigger{Priority: &resolvePriority}}
+
+	err = forward(context.Background(), app, gotify.MessageRequest{Title: "Backup failed", Priority: 5}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("fire: %v", err)
+	}
+
+	err = forward(context.Background(), app, gotify.MessageRequest{Title: "Backup failed", Priority: 0}, server.MessageID{Seq: 2})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+
+	if len(posted) != 2 || len(posted[1]) != 1 {
+		t.Fatalf("expected a fire and a single-alert resolution, got %v", posted)
+	}
+
+	fired, resolved := posted[0][0], posted[1][0]
+
+	if resolved.Labels["gotilert_id"] != fired.Labels["gotilert_id"] {
+		t.Fatalf("expected resolution to reuse labels %v, got %v", fired.Labels, resolved.Labels)
+	}
+
+	if !resolved.EndsAt.Before(fired.EndsAt) || resolved.EndsAt.After(time.Now()) {
+		t.Fatalf("expected resolution EndsAt <= now, got %v", resolved.EndsAt)
+	}
+}
+
+func TestResolveTriggerMatchesExtrasKey(t *testing.T) {
+	t.Parallel()
+
+	trigger := server.ResolveTrigger{ExtrasKey: "gotilert::resolve"}
+
+	if !trigger.Matches(gotify.MessageRequest{Extras: map[string]any{"gotilert::resolve": true}}) {
+		t.Fatalf("expected extras bool true to match")
+	}
+
+	if trigger.Matches(gotify.MessageRequest{Extras: map[string]any{"gotilert::resolve": false}}) {
+		t.Fatalf("expected extras bool false not to match")
+	}
+
+	if trigger.Matches(gotify.MessageRequest{Priority: 0}) {
+		t.Fatalf("expected missing extras key not to match")
+	}
+}
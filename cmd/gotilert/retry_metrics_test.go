@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderCountsUpstreamRetriesPerApp(t *testing.T) {
+	t.Parallel()
+
+	var requestCount atomic.Int32
+
+	// 500, 500, 200: two retries.
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		if requestCount.Add(1) <= 2 {
+			writer.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Alertmanager: config.AlertmanagerConfig{
+			URL:     upstream.URL,
+			Timeout: config.Duration{Duration: 2 * time.Second},
+			Retry: config.RetryConfig{
+				InitialBackoff: config.Duration{Duration: time.Millisecond},
+				MaxBackoff:     config.Duration{Duration: time.Millisecond},
+			},
+		},
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+		},
+	}
+
+	metricsCollector := metrics.New()
+
+	client, err := newAlertmanagerClient(cfg, metricsCollector)
+	if err != nil {
+		t.Fatalf("newAlertmanagerClient: %v", err)
+	}
+
+	forward, err := newForwarder(cfg, client.PostAlerts, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("newForwarder: %v", err)
+	}
+
+	err = forward(context.Background(), server.App{Name: "backup"}, gotify.MessageRequest{Message: "hello"}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("forward: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `gotilert_upstream_retries_total{app="backup"} 2`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("expected %q in metrics output:\n%s", want, rec.Body.String())
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/templating"
+)
+
+// rule is a compiled defaults.rules entry.
+type rule struct {
+	name string
+
+	title       *regexp.Regexp
+	message     *regexp.Regexp
+	minPriority *int
+	maxPriority *int
+
+	drop        bool
+	setLabels   *templating.Map
+	setSeverity string
+}
+
+// ruleActions is what the rules matching one message asked for, in rule order.
+type ruleActions struct {
+	dropBy   string
+	labels   []*templating.Map
+	severity string
+}
+
+// compileRules compiles the (already validated) rules; patterns and templates that fail
+// here fail the config load.
+func compileRules(configs []config.RuleConfig) ([]rule, error) {
+	rules := make([]rule, 0, len(configs))
+
+	for index := range configs {
+		ruleConfig := &configs[index]
+
+		compiled := rule{
+			name:        ruleConfig.Name,
+			minPriority: ruleConfig.Match.MinPriority,
+			maxPriority: ruleConfig.Match.MaxPriority,
+			drop:        ruleConfig.Drop,
+			setSeverity: ruleConfig.SetSeverity,
+		}
+
+		var err error
+
+		compiled.title, err = compileOptionalRegexp(ruleConfig.Match.Title)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: title: %w", ruleConfig.Name, err)
+		}
+
+		compiled.message, err = compileOptionalRegexp(ruleConfig.Match.Message)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: message: %w", ruleConfig.Name, err)
+		}
+
+		compiled.setLabels, err = templating.Compile(ruleConfig.SetLabels)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: compile setLabels: %w", ruleConfig.Name, err)
+		}
+
+		rules = append(rules, compiled)
+	}
+
+	return rules, nil
+}
+
+// compileOptionalRegexp returns nil for an empty pattern ("match anything").
+func compileOptionalRegexp(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil //nolint:nilnil // nil means "not configured".
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compile %q: %w", pattern, err)
+	}
+
+	return compiled, nil
+}
+
+func (r *rule) matches(msg *gotify.MessageRequest) bool {
+	if r.minPriority != nil && msg.Priority < *r.minPriority {
+		return false
+	}
+
+	if r.maxPriority != nil && msg.Priority > *r.maxPriority {
+		return false
+	}
+
+	if r.title != nil && !r.title.MatchString(msg.Title) {
+		return false
+	}
+
+	return r.message == nil || r.message.MatchString(msg.Message)
+}
+
+// evaluateRules runs the rules in order, counting each match. A matching drop rule stops
+// the evaluation; otherwise the actions of every matching rule accumulate.
+func (fwd *forwarder) evaluateRules(msg *gotify.MessageRequest) ruleActions {
+	var actions ruleActions
+
+	for index := range fwd.rules {
+		current := &fwd.rules[index]
+		if !current.matches(msg) {
+			continue
+		}
+
+		fwd.metrics.IncRuleMatch(current.name)
+
+		if current.drop {
+			actions.dropBy = current.name
+
+			return actions
+		}
+
+		actions.labels = append(actions.labels, current.setLabels)
+
+		if current.setSeverity != "" {
+			actions.severity = current.setSeverity
+		}
+	}
+
+	return actions
+}
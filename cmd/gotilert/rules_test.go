@@ -0,0 +1,116 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderAppliesRulesInOrder(t *testing.T) {
+	t.Parallel()
+
+	five := 5
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			ForcedLabels:         map[string]string{"env": "prod"},
+			Rules: []config.RuleConfig{
+				{Name: "ignore-heartbeats", Match: config.RuleMatch{Title: "(?i)^heartbeat"}, Drop: true},
+				{
+					Name:      "backups",
+					Match:     config.RuleMatch{Message: "backup"},
+					SetLabels: map[string]string{"team": "storage", "app": "backup-{{ .AppName }}", "env": "dev"},
+				},
+				{Name: "urgent", Match: config.RuleMatch{MinPriority: &five}, SetSeverity: "critical"},
+			},
+		},
+	}
+
+	var posted []alertmanager.Alert
+
+	post := func(_ context.Context, alerts []alertmanager.Alert) error {
+		posted = append(posted, alerts...)
+
+		return nil
+	}
+
+	metricsCollector := metrics.New()
+
+	forward, err := newForwarder(cfg, post, metricsCollector, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("newForwarder: %v", err)
+	}
+
+	for _, msg := range []gotify.MessageRequest{
+		{Title: "Heartbeat", Message: "still alive"},
+		{Title: "Nightly", Message: "backup failed", Priority: 8},
+	} {
+		err = forward(context.Background(), server.App{Name: "nas"}, msg, server.MessageID{Seq: 1})
+		if err != nil {
+			t.Fatalf("forward: %v", err)
+		}
+	}
+
+	if len(posted) != 1 {
+		t.Fatalf("expected the heartbeat to be dropped, got %d alerts", len(posted))
+	}
+
+	labels := posted[0].Labels
+	if labels["team"] != "storage" || labels["app"] != "backup-nas" || labels["severity"] != "critical" {
+		t.Fatalf("expected rule labels and severity, got %v", labels)
+	}
+
+	if labels["env"] != "prod" {
+		t.Fatalf("expected forcedLabels to win over rule labels, got %v", labels)
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, want := range []string{
+		`gotilert_rule_matches_total{rule="ignore-heartbeats"} 1`,
+		`gotilert_rule_matches_total{rule="backups"} 1`,
+		`gotilert_rule_matches_total{rule="urgent"} 1`,
+		`gotilert_dropped_total{app="nas",reason="rule"} 1`,
+	} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Fatalf("expected %q in metrics output:\n%s", want, rec.Body.String())
+		}
+	}
+}
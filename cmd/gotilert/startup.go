@@ -0,0 +1,134 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+const (
+	defaultStartupWaitTimeout = 60 * time.Second
+	startupPollInterval       = time.Second
+)
+
+// startupGate holds readiness and forwarding until Alertmanager first reports ready, so a
+// cold start does not answer early /message requests with 502s.
+type startupGate struct {
+	check         server.ReadyFunc
+	timeout       time.Duration
+	interval      time.Duration
+	failOnTimeout bool
+
+	open atomic.Bool
+}
+
+// newStartupGate returns nil unless alertmanager.waitForReadyOnStartup is set.
+func newStartupGate(amConfig *config.AlertmanagerConfig, check server.ReadyFunc) *startupGate {
+	if !amConfig.WaitForReadyOnStartup {
+		return nil
+	}
+
+	return &startupGate{
+		check:         check,
+		timeout:       pickDuration(amConfig.WaitForReadyTimeout.Duration, defaultStartupWaitTimeout),
+		interval:      startupPollInterval,
+		failOnTimeout: amConfig.WaitForReadyFailOnTimeout,
+	}
+}
+
+// wait polls the check until it succeeds, the timeout elapses or ctx is done, then opens the
+// gate. It returns an error only when failOnTimeout is set and Alertmanager never became ready.
+func (gate *startupGate) wait(ctx context.Context) error {
+	start := time.Now()
+	deadline := start.Add(gate.timeout)
+
+	ticker := time.NewTicker(gate.interval)
+	defer ticker.Stop()
+
+	for {
+		ok, reason := gate.check()
+		if ok {
+			logger.L().Info("alertmanager ready; accepting messages", "waited", time.Since(start).String())
+			gate.open.Store(true)
+
+			return nil
+		}
+
+		if !time.Now().Before(deadline) {
+			if gate.failOnTimeout {
+				return fmt.Errorf("%w after %s: %s", ErrStartupNotReady, gate.timeout, reason)
+			}
+
+			logger.L().Warn("alertmanager not ready before startup timeout; starting anyway",
+				"timeout", gate.timeout.String(),
+				"reason", reason,
+			)
+			gate.open.Store(true)
+
+			return nil
+		}
+
+		logger.L().Info("waiting for alertmanager to become ready",
+			"elapsed", time.Since(start).Round(time.Millisecond).String(),
+			"reason", reason,
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for alertmanager: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ready reports not ready until the gate opens, then defers to next.
+func (gate *startupGate) ready(next server.ReadyFunc) server.ReadyFunc {
+	return func() (bool, string) {
+		if !gate.open.Load() {
+			return false, server.ErrNotReady.Error()
+		}
+
+		return next()
+	}
+}
+
+// forward rejects messages with server.ErrNotReady (503) until the gate opens.
+func (gate *startupGate) forward(next server.ForwardMessageFunc) server.ForwardMessageFunc {
+	return func(ctx context.Context, app server.App, req gotify.MessageRequest, messageID server.MessageID) error {
+		if !gate.open.Load() {
+			return fmt.Errorf("%w", server.ErrNotReady)
+		}
+
+		return next(ctx, app, req, messageID)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestStartupGateHoldsForwardsUntilReady(t *testing.T) {
+	t.Parallel()
+
+	var checks atomic.Int32
+
+	gate := &startupGate{
+		check: func() (bool, string) {
+			return checks.Add(1) >= 3, "connection refused"
+		},
+		timeout:  time.Second,
+		interval: time.Millisecond,
+	}
+
+	forwarded := 0
+	forward := gate.forward(func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+		forwarded++
+
+		return nil
+	})
+	ready := gate.ready(func() (bool, string) { return true, "" })
+
+	err := forward(context.Background(), server.App{}, gotify.MessageRequest{}, server.MessageID{})
+	if !errors.Is(err, server.ErrNotReady) {
+		t.Fatalf("expected ErrNotReady before startup, got %v", err)
+	}
+
+	if ok, _ := ready(); ok {
+		t.Fatalf("expected not ready before startup")
+	}
+
+	err = gate.wait(context.Background())
+	if err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	if got := checks.Load(); got != 3 {
+		t.Fatalf("expected 3 readiness checks, got %d", got)
+	}
+
+	err = forward(context.Background(), server.App{}, gotify.MessageRequest{}, server.MessageID{})
+	if err != nil || forwarded != 1 {
+		t.Fatalf("expected the message to be forwarded after startup, got err=%v forwarded=%d", err, forwarded)
+	}
+
+	if ok, _ := ready(); !ok {
+		t.Fatalf("expected ready after startup")
+	}
+}
+
+func TestStartupGateTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, failOnTimeout := range []bool{false, true} {
+		gate := &startupGate{
+			check:         func() (bool, string) { return false, "connection refused" },
+			timeout:       10 * time.Millisecond,
+			interval:      time.Millisecond,
+			failOnTimeout: failOnTimeout,
+		}
+
+		err := gate.wait(context.Background())
+
+		if failOnTimeout {
+			if !errors.Is(err, ErrStartupNotReady) || gate.open.Load() {
+				t.Fatalf("expected ErrStartupNotReady with a closed gate, got err=%v open=%t", err, gate.open.Load())
+			}
+
+			continue
+		}
+
+		if err != nil || !gate.open.Load() {
+			t.Fatalf("expected to start anyway after the timeout, got err=%v open=%t", err, gate.open.Load())
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// testAlertLabel marks synthetic alerts sent by POST /-/test so routes can silence them.
+const testAlertLabel = "gotilert_test"
+
+const (
+	testAlertTitle   = "Gotilert test alert"
+	testAlertMessage = "Synthetic alert sent by POST /-/test to verify delivery to Alertmanager."
+)
+
+// syncPostContextKey marks posts that must reach Alertmanager before returning, even when
+// alertmanager.async queues regular messages.
+type syncPostContextKey struct{}
+
+func withSyncPost(ctx context.Context) context.Context {
+	return context.WithValue(ctx, syncPostContextKey{}, true)
+}
+
+func isSyncPost(ctx context.Context) bool {
+	syncPost, _ := ctx.Value(syncPostContextKey{}).(bool)
+
+	return syncPost
+}
+
+// testAlert builds a synthetic alert for app with the regular label and annotation mapping,
+// marks it with gotilert_test="true" and posts it synchronously. Priority filters and
+// content rules are skipped so the alert always reaches Alertmanager.
+func (fwd *forwarder) testAlert(ctx context.Context, app server.App, messageIdentifier server.MessageID) error {
+	msg := gotify.MessageRequest{
+		Title:    testAlertTitle,
+		Message:  testAlertMessage,
+		Priority: fwd.cfg.Defaults.PriorityRange.Clamp(gotify.DefaultPriority),
+	}
+
+	alert := fwd.buildAlert(app, msg, messageIdentifier, ruleActions{}, time.Now().UTC())
+	alert.Labels[testAlertLabel] = "true"
+
+	logger.L().Info("sending test alert",
+		"request_id", server.RequestIDFromContext(ctx),
+		"app", app.Name,
+	)
+
+	return fwd.post(withSyncPost(ctx), app.Name, []alertmanager.Alert{alert})
+}
@@ -0,0 +1,84 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestForwarderTestAlert(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{
+		Defaults: config.DefaultsConfig{
+			AlertName:            config.DefaultAlertName,
+			TTL:                  config.Duration{Duration: time.Hour},
+			SeverityFromPriority: map[int]string{0: "info"},
+			MinForwardPriority:   9,
+			Rules:                []config.RuleConfig{{Name: "drop-all", Match: config.RuleMatch{Message: "."}, Drop: true}},
+		},
+	}
+
+	var (
+		posted   []alertmanager.Alert
+		syncPost bool
+	)
+
+	post := func(ctx context.Context, alerts []alertmanager.Alert) error {
+		posted = append(posted, alerts...)
+		syncPost = isSyncPost(ctx)
+
+		return nil
+	}
+
+	fwd, err := buildForwarder(cfg, post, nil, newFiringAlerts())
+	if err != nil {
+		t.Fatalf("buildForwarder: %v", err)
+	}
+
+	err = fwd.testAlert(context.Background(), server.App{Name: "nas"}, server.MessageID{Seq: 1})
+	if err != nil {
+		t.Fatalf("testAlert: %v", err)
+	}
+
+	if len(posted) != 1 {
+		t.Fatalf("expected the test alert to bypass filters and rules, got %d alerts", len(posted))
+	}
+
+	labels := posted[0].Labels
+	if labels[testAlertLabel] != "true" || labels["app"] != "nas" || labels["alertname"] != config.DefaultAlertName {
+		t.Fatalf("expected a labeled test alert, got %v", labels)
+	}
+
+	if !syncPost {
+		t.Fatalf("expected the test alert to be posted synchronously")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported Alertmanager alerts API versions.
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// alertV1 mirrors the legacy v1 payload (prometheus/common model.Alert): annotations and
+// generatorURL are always present.
+type alertV1 struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+}
+
+// v1ErrorEnvelope is the body of a failed v1 API call.
+type v1ErrorEnvelope struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+}
+
+// normalizeAPIVersion defaults an empty version to v2 and rejects unknown ones.
+func normalizeAPIVersion(version string) (string, error) {
+	switch normalized := strings.ToLower(strings.TrimSpace(version)); normalized {
+	case "", APIVersionV2:
+		return APIVersionV2, nil
+	case APIVersionV1:
+		return APIVersionV1, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported apiVersion %q", ErrInvalidConfiguration, version)
+	}
+}
+
+func alertsPath(version string) string {
+	return "/api/" + version + "/alerts"
+}
+
+func encodeAlerts(version string, alerts []Alert) ([]byte, error) {
+	if version != APIVersionV1 {
+		return json.Marshal(alerts) //nolint:wrapcheck // wrapped by the caller.
+	}
+
+	legacy := make([]alertV1, 0, len(alerts))
+
+	for _, alert := range alerts {
+		annotations := alert.Annotations
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		legacy = append(legacy, alertV1{
+			Labels:       alert.Labels,
+			Annotations:  annotations,
+			StartsAt:     alert.StartsAt,
+			EndsAt:       alert.EndsAt,
+			GeneratorURL: alert.GeneratorURL,
+		})
+	}
+
+	return json.Marshal(legacy) //nolint:wrapcheck // wrapped by the caller.
+}
+
+// errorMessage extracts the error text of a non-2xx response body: v1 wraps it in a JSON
+// envelope, v2 returns it as-is.
+func errorMessage(version string, body []byte) string {
+	if version == APIVersionV1 {
+		var envelope v1ErrorEnvelope
+
+		if json.Unmarshal(body, &envelope) == nil && envelope.Error != "" {
+			return envelope.Error
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
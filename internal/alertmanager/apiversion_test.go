@@ -0,0 +1,124 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestClientPostsV1AlertsPayload(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotPath string
+		gotBody []map[string]any
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		gotPath = request.URL.Path
+
+		body, _ := io.ReadAll(request.Body)
+		_ = json.Unmarshal(body, &gotBody)
+
+		_, _ = writer.Write([]byte(`{"status":"success"}`))
+	}))
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{BaseURL: upstream.URL, APIVersion: alertmanager.APIVersionV1})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = client.PostAlerts(context.Background(), []alertmanager.Alert{{
+		Labels:   map[string]string{"alertname": "Test"},
+		StartsAt: time.Now().UTC(),
+		EndsAt:   time.Now().UTC().Add(time.Minute),
+	}})
+	if err != nil {
+		t.Fatalf("PostAlerts: %v", err)
+	}
+
+	if gotPath != "/api/v1/alerts" {
+		t.Fatalf("expected path %q, got %q", "/api/v1/alerts", gotPath)
+	}
+
+	if len(gotBody) != 1 {
+		t.Fatalf("expected one alert, got %v", gotBody)
+	}
+
+	for _, key := range []string{"labels", "annotations", "startsAt", "endsAt", "generatorURL"} {
+		if _, ok := gotBody[0][key]; !ok {
+			t.Fatalf("expected v1 alert to contain %q, got %v", key, gotBody[0])
+		}
+	}
+}
+
+func TestClientV1ErrorEnvelopeIsUnwrapped(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+		_, _ = writer.Write([]byte(`{"status":"error","errorType":"bad_data","error":"start time must be before end time"}`))
+	}))
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:          upstream.URL,
+		APIVersion:       alertmanager.APIVersionV1,
+		RetryMaxAttempts: 1,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = client.PostAlerts(context.Background(), []alertmanager.Alert{{Labels: map[string]string{"alertname": "Test"}}})
+
+	var statusErr alertmanager.HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected HTTPStatusError, got %v", err)
+	}
+
+	if statusErr.Body() != "start time must be before end time" {
+		t.Fatalf("expected unwrapped v1 error, got %q", statusErr.Body())
+	}
+}
+
+func TestNewRejectsUnknownAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	_, err := alertmanager.New(&alertmanager.Options{BaseURL: "http://localhost:9093", APIVersion: "v3"})
+	if !errors.Is(err, alertmanager.ErrInvalidConfiguration) {
+		t.Fatalf("expected ErrInvalidConfiguration, got %v", err)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -162,3 +163,53 @@ func TestClientUnauthorizedReturnsStatusErrorWithBody(t *testing.T) {
 		t.Fatalf("expected body %q, got %q", "unauthorized", stErr.Body())
 	}
 }
+
+func TestClientCustomHeadersReachUpstreamWithoutOverridingAuth(t *testing.T) {
+	t.Parallel()
+
+	var gotOrgID, gotAuthorization atomic.Value
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+			gotOrgID.Store(r.Header.Get("X-Scope-OrgID"))
+			gotAuthorization.Store(r.Header.Get("Authorization"))
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer server.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL: server.URL,
+		Timeout: 2 * time.Second,
+		Auth:    alertmanager.Auth{BearerToken: "real-token"},
+		Headers: map[string]string{
+			"x-scope-orgid": "tenant-a",
+			"Authorization": "Bearer hijacked",
+		},
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = client.PostAlerts(ctx, []alertmanager.Alert{
+		{
+			Labels:   map[string]string{"alertname": "Test"},
+			StartsAt: time.Now().UTC(),
+			EndsAt:   time.Now().UTC().Add(1 * time.Minute),
+		},
+	})
+	if err != nil {
+		t.Fatalf("PostAlerts: expected no error, got %v", err)
+	}
+
+	if got := gotOrgID.Load(); got != "tenant-a" {
+		t.Fatalf("expected X-Scope-OrgID %q, got %q", "tenant-a", got)
+	}
+
+	if got := gotAuthorization.Load(); got != "Bearer real-token" {
+		t.Fatalf("expected Authorization %q, got %q", "Bearer real-token", got)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestJitteredBackoffStaysWithinBounds(t *testing.T) {
+	t.Parallel()
+
+	client, err := New(&Options{
+		BaseURL:         "http://alertmanager.example.local",
+		RetryMaxBackoff: 800 * time.Millisecond,
+		RandSource:      rand.NewPCG(1, 2),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		base := computeBackoff(attempt, client.retryInitial, client.retryMaxBackoff)
+
+		got := client.jitteredBackoff(base)
+		if got < base/2 || got > base || got > client.retryMaxBackoff {
+			t.Fatalf("attempt %d: jittered backoff %s outside [%s, %s]", attempt, got, base/2, base)
+		}
+	}
+}
+
+func TestJitteredBackoffDisabledIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	client, err := New(&Options{
+		BaseURL:       "http://alertmanager.example.local",
+		DisableJitter: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if got := client.jitteredBackoff(400 * time.Millisecond); got != 400*time.Millisecond {
+		t.Fatalf("expected %s, got %s", 400*time.Millisecond, got)
+	}
+}
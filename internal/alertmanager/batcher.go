@@ -0,0 +1,226 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultBatchMaxSize = 64
+
+// PostFunc delivers a set of alerts upstream (typically Client.PostAlerts).
+type PostFunc func(ctx context.Context, alerts []Alert) error
+
+type BatcherOptions struct {
+	// Window is how long alerts are accumulated before being flushed. Must be > 0.
+	Window time.Duration
+	// MaxSize flushes early once this many alerts are pending (0 = default).
+	MaxSize int
+	// Timeout bounds each flush (0 = no extra bound beyond the HTTP client timeout).
+	Timeout time.Duration
+
+	Post PostFunc
+}
+
+// Batcher coalesces alerts from concurrent callers into a single PostFunc call.
+// PostAlerts blocks until the batch containing the caller's alerts has been delivered
+// and returns that batch's result, so callers keep synchronous error semantics.
+type Batcher struct {
+	window  time.Duration
+	maxSize int
+	timeout time.Duration
+	post    PostFunc
+
+	mutex        sync.Mutex
+	pending      []batchEntry
+	pendingCount int
+	timer        *time.Timer
+	closed       bool
+
+	inFlight sync.WaitGroup
+}
+
+type batchEntry struct {
+	alerts []Alert
+	done   chan error
+
+	// status is the caller's ResponseStatus, if any; it gets the status of the batch's POST.
+	status *ResponseStatus
+}
+
+func NewBatcher(opts *BatcherOptions) (*Batcher, error) {
+	if opts == nil || opts.Post == nil || opts.Window <= 0 {
+		return nil, fmt.Errorf("%w: batcher requires a positive window and a post func", ErrInvalidConfiguration)
+	}
+
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBatchMaxSize
+	}
+
+	return &Batcher{
+		window:  opts.Window,
+		maxSize: maxSize,
+		timeout: opts.Timeout,
+		post:    opts.Post,
+	}, nil
+}
+
+// PostAlerts enqueues alerts and waits for the batch to be flushed. If ctx ends first the
+// alerts stay queued and will still be delivered; only the wait is abandoned.
+func (batcher *Batcher) PostAlerts(ctx context.Context, alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	done := make(chan error, 1)
+
+	batcher.mutex.Lock()
+
+	if batcher.closed {
+		batcher.mutex.Unlock()
+
+		return ErrBatcherClosed
+	}
+
+	batcher.pending = append(batcher.pending, batchEntry{alerts: alerts, done: done, status: responseStatusFromContext(ctx)})
+	batcher.pendingCount += len(alerts)
+
+	if batcher.pendingCount >= batcher.maxSize {
+		entries := batcher.takePendingLocked()
+		batcher.inFlight.Add(1)
+		batcher.mutex.Unlock()
+
+		go batcher.flush(entries)
+	} else {
+		if batcher.timer == nil {
+			batcher.timer = time.AfterFunc(batcher.window, batcher.flushPending)
+		}
+
+		batcher.mutex.Unlock()
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+	}
+}
+
+// Close stops accepting alerts and flushes anything still buffered, waiting for
+// in-flight flushes to complete or ctx to end.
+func (batcher *Batcher) Close(ctx context.Context) error {
+	batcher.mutex.Lock()
+	batcher.closed = true
+	entries := batcher.takePendingLocked()
+
+	if len(entries) > 0 {
+		batcher.inFlight.Add(1)
+	}
+
+	batcher.mutex.Unlock()
+
+	if len(entries) > 0 {
+		go batcher.flush(entries)
+	}
+
+	finished := make(chan struct{})
+
+	go func() {
+		batcher.inFlight.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+	}
+}
+
+func (batcher *Batcher) flushPending() {
+	batcher.mutex.Lock()
+	entries := batcher.takePendingLocked()
+
+	if len(entries) == 0 {
+		batcher.mutex.Unlock()
+
+		return
+	}
+
+	batcher.inFlight.Add(1)
+	batcher.mutex.Unlock()
+
+	batcher.flush(entries)
+}
+
+// takePendingLocked must be called with the mutex held.
+func (batcher *Batcher) takePendingLocked() []batchEntry {
+	if batcher.timer != nil {
+		batcher.timer.Stop()
+		batcher.timer = nil
+	}
+
+	entries := batcher.pending
+	batcher.pending = nil
+	batcher.pendingCount = 0
+
+	return entries
+}
+
+func (batcher *Batcher) flush(entries []batchEntry) {
+	defer batcher.inFlight.Done()
+
+	alerts := make([]Alert, 0, len(entries))
+	for _, entry := range entries {
+		alerts = append(alerts, entry.alerts...)
+	}
+
+	var status ResponseStatus
+
+	ctx := WithResponseStatus(context.Background(), &status)
+
+	if batcher.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, batcher.timeout)
+		defer cancel()
+	}
+
+	err := batcher.post(ctx, alerts)
+
+	for _, entry := range entries {
+		if entry.status != nil {
+			entry.status.set(status.Code())
+		}
+
+		entry.done <- err
+	}
+}
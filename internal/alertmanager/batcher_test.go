@@ -0,0 +1,171 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+type recordingPoster struct {
+	mutex   sync.Mutex
+	batches [][]alertmanager.Alert
+}
+
+func (poster *recordingPoster) post(_ context.Context, alerts []alertmanager.Alert) error {
+	poster.mutex.Lock()
+	defer poster.mutex.Unlock()
+
+	poster.batches = append(poster.batches, alerts)
+
+	return nil
+}
+
+func (poster *recordingPoster) batchSizes() []int {
+	poster.mutex.Lock()
+	defer poster.mutex.Unlock()
+
+	sizes := make([]int, 0, len(poster.batches))
+	for _, batch := range poster.batches {
+		sizes = append(sizes, len(batch))
+	}
+
+	return sizes
+}
+
+func TestBatcherCoalescesConcurrentAlertsWithinWindow(t *testing.T) {
+	t.Parallel()
+
+	poster := &recordingPoster{}
+
+	batcher, err := alertmanager.NewBatcher(&alertmanager.BatcherOptions{
+		Window:  50 * time.Millisecond,
+		MaxSize: 100,
+		Post:    poster.post,
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+
+	var waitGroup sync.WaitGroup
+
+	for range 3 {
+		waitGroup.Go(func() {
+			postErr := batcher.PostAlerts(context.Background(), []alertmanager.Alert{testAlert()})
+			if postErr != nil {
+				t.Errorf("PostAlerts: %v", postErr)
+			}
+		})
+	}
+
+	waitGroup.Wait()
+
+	sizes := poster.batchSizes()
+	if len(sizes) != 1 || sizes[0] != 3 {
+		t.Fatalf("expected a single batch of 3 alerts, got %v", sizes)
+	}
+}
+
+func TestBatcherFlushesWhenMaxSizeReached(t *testing.T) {
+	t.Parallel()
+
+	poster := &recordingPoster{}
+
+	batcher, err := alertmanager.NewBatcher(&alertmanager.BatcherOptions{
+		Window:  1 * time.Hour,
+		MaxSize: 2,
+		Post:    poster.post,
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = batcher.PostAlerts(ctx, []alertmanager.Alert{testAlert(), testAlert()})
+	if err != nil {
+		t.Fatalf("PostAlerts: expected flush at max size, got %v", err)
+	}
+
+	if sizes := poster.batchSizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Fatalf("expected a single batch of 2 alerts, got %v", sizes)
+	}
+}
+
+func TestBatcherCloseFlushesPendingAndRejectsNewAlerts(t *testing.T) {
+	t.Parallel()
+
+	var posted atomic.Int32
+
+	batcher, err := alertmanager.NewBatcher(&alertmanager.BatcherOptions{
+		Window: 1 * time.Hour,
+		Post: func(_ context.Context, alerts []alertmanager.Alert) error {
+			posted.Add(int32(len(alerts)))
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+
+	// The caller gives up waiting, but the alert stays buffered until Close flushes it.
+	waitCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = batcher.PostAlerts(waitCtx, []alertmanager.Alert{testAlert()})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected caller deadline, got %v", err)
+	}
+
+	err = batcher.Close(context.Background())
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := posted.Load(); got != 1 {
+		t.Fatalf("expected buffered alert to be flushed on close, got %d", got)
+	}
+
+	err = batcher.PostAlerts(context.Background(), []alertmanager.Alert{testAlert()})
+	if !errors.Is(err, alertmanager.ErrBatcherClosed) {
+		t.Fatalf("expected ErrBatcherClosed, got %v", err)
+	}
+}
+
+func testAlert() alertmanager.Alert {
+	return alertmanager.Alert{
+		Labels:   map[string]string{"alertname": "Test"},
+		StartsAt: time.Now().UTC(),
+		EndsAt:   time.Now().UTC().Add(1 * time.Minute),
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed passes every call through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every call fast with ErrCircuitOpen until the cooldown elapses.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe through; its result closes or re-opens the circuit.
+	CircuitHalfOpen
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type CircuitBreakerOptions struct {
+	// FailureThreshold opens the circuit after this many consecutive failures. Must be > 0.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a probe is let through. Must be > 0.
+	Cooldown time.Duration
+
+	Post PostFunc
+
+	// OnStateChange is called (with the breaker lock held) on every transition. Optional.
+	OnStateChange func(state CircuitState)
+}
+
+// CircuitBreaker wraps a PostFunc so a hard-down Alertmanager fails fast instead of
+// every caller paying the full retry/backoff cost.
+type CircuitBreaker struct {
+	threshold     int
+	cooldown      time.Duration
+	post          PostFunc
+	onStateChange func(state CircuitState)
+	now           func() time.Time
+
+	mutex    sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(opts *CircuitBreakerOptions) (*CircuitBreaker, error) {
+	if opts == nil || opts.Post == nil || opts.FailureThreshold <= 0 || opts.Cooldown <= 0 {
+		return nil, fmt.Errorf(
+			"%w: circuit breaker requires a positive failure threshold, cooldown and a post func",
+			ErrInvalidConfiguration,
+		)
+	}
+
+	breaker := &CircuitBreaker{
+		threshold:     opts.FailureThreshold,
+		cooldown:      opts.Cooldown,
+		post:          opts.Post,
+		onStateChange: opts.OnStateChange,
+		now:           time.Now,
+	}
+
+	return breaker, nil
+}
+
+// State returns the current state, moving an expired open circuit to half-open.
+func (breaker *CircuitBreaker) State() CircuitState {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if breaker.state == CircuitOpen && breaker.now().Sub(breaker.openedAt) >= breaker.cooldown {
+		breaker.transition(CircuitHalfOpen)
+	}
+
+	return breaker.state
+}
+
+// PostAlerts delivers alerts through the wrapped PostFunc unless the circuit is open.
+// Calls whose ctx ended (a caller cancellation or its own deadline, e.g. X-Gotilert-Timeout)
+// are not counted as upstream failures.
+func (breaker *CircuitBreaker) PostAlerts(ctx context.Context, alerts []Alert) error {
+	probe, err := breaker.acquire()
+	if err != nil {
+		return err
+	}
+
+	postErr := breaker.post(ctx, alerts)
+
+	breaker.release(ctx, probe, postErr)
+
+	return postErr
+}
+
+// acquire admits a call; probe reports whether it is the half-open probe, whose result alone
+// decides whether the circuit closes or re-opens.
+func (breaker *CircuitBreaker) acquire() (bool, error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	switch breaker.state {
+	case CircuitClosed:
+		return false, nil
+	case CircuitOpen:
+		remaining := breaker.cooldown - breaker.now().Sub(breaker.openedAt)
+		if remaining > 0 {
+			return false, fmt.Errorf("%w: retrying in %s", ErrCircuitOpen, remaining.Round(time.Millisecond))
+		}
+
+		breaker.transition(CircuitHalfOpen)
+	case CircuitHalfOpen:
+	}
+
+	// Half-open: only one probe at a time.
+	if breaker.probing {
+		return false, fmt.Errorf("%w: probe in flight", ErrCircuitOpen)
+	}
+
+	breaker.probing = true
+
+	return true, nil
+}
+
+// release records the result of a call admitted by acquire. Calls admitted while the circuit
+// was closed only count towards the failure threshold while it is still closed. A call whose
+// ctx is done records nothing: the caller gave up, whatever Alertmanager would have answered.
+func (breaker *CircuitBreaker) release(ctx context.Context, probe bool, err error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if probe {
+		breaker.probing = false
+	}
+
+	switch {
+	case ctx.Err() != nil, errors.Is(err, context.Canceled), errors.Is(err, ErrConcurrencyLimit):
+		// The caller went away or found no free slot; this says nothing about Alertmanager.
+	case probe && !isUpstreamFailure(err):
+		breaker.transition(CircuitClosed)
+	case probe:
+		breaker.open()
+	case breaker.state != CircuitClosed:
+		// A straggler admitted before the circuit opened; the probe decides.
+	case !isUpstreamFailure(err):
+		breaker.failures = 0
+	default:
+		breaker.failures++
+		if breaker.failures >= breaker.threshold {
+			breaker.open()
+		}
+	}
+}
+
+// isUpstreamFailure reports whether err means Alertmanager is unhealthy: a transport error, a
+// 5xx or a 429. Other statuses (e.g. 400/422 for one app's bad payload) prove it answered.
+func isUpstreamFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+
+	code := statusErr.StatusCode()
+
+	return code >= http.StatusInternalServerError || code == http.StatusTooManyRequests
+}
+
+func (breaker *CircuitBreaker) open() {
+	breaker.openedAt = breaker.now()
+	breaker.transition(CircuitOpen)
+}
+
+func (breaker *CircuitBreaker) transition(state CircuitState) {
+	breaker.state = state
+	if state == CircuitClosed {
+		breaker.failures = 0
+	}
+
+	if breaker.onStateChange != nil {
+		breaker.onStateChange(state)
+	}
+}
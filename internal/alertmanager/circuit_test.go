@@ -0,0 +1,277 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+var errUpstreamDown = errors.New("upstream down")
+
+func TestCircuitBreakerOpensAfterThresholdAndFailsFast(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var states []alertmanager.CircuitState
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: 2,
+		Cooldown:         time.Hour,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			calls++
+
+			return errUpstreamDown
+		},
+		OnStateChange: func(state alertmanager.CircuitState) {
+			states = append(states, state)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new circuit breaker: %v", err)
+	}
+
+	for range 2 {
+		err = breaker.PostAlerts(context.Background(), nil)
+		if !errors.Is(err, errUpstreamDown) {
+			t.Fatalf("expected upstream error, got %v", err)
+		}
+	}
+
+	err = breaker.PostAlerts(context.Background(), nil)
+	if !errors.Is(err, alertmanager.ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls)
+	}
+
+	if breaker.State() != alertmanager.CircuitOpen {
+		t.Fatalf("expected open, got %s", breaker.State())
+	}
+
+	if len(states) != 1 || states[0] != alertmanager.CircuitOpen {
+		t.Fatalf("expected a single transition to open, got %v", states)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeClosesOrReopens(t *testing.T) {
+	t.Parallel()
+
+	var upstreamErr error
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         20 * time.Millisecond,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			return upstreamErr
+		},
+	})
+	if err != nil {
+		t.Fatalf("new circuit breaker: %v", err)
+	}
+
+	upstreamErr = errUpstreamDown
+	_ = breaker.PostAlerts(context.Background(), nil)
+
+	time.Sleep(30 * time.Millisecond)
+
+	if breaker.State() != alertmanager.CircuitHalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %s", breaker.State())
+	}
+
+	// A failed probe re-opens the circuit for another cooldown.
+	err = breaker.PostAlerts(context.Background(), nil)
+	if !errors.Is(err, errUpstreamDown) {
+		t.Fatalf("expected probe to reach upstream, got %v", err)
+	}
+
+	if breaker.State() != alertmanager.CircuitOpen {
+		t.Fatalf("expected open after failed probe, got %s", breaker.State())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	upstreamErr = nil
+
+	err = breaker.PostAlerts(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected successful probe, got %v", err)
+	}
+
+	if breaker.State() != alertmanager.CircuitClosed {
+		t.Fatalf("expected closed after successful probe, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresCallerCancellation(t *testing.T) {
+	t.Parallel()
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+		Post: func(ctx context.Context, _ []alertmanager.Alert) error {
+			return ctx.Err()
+		},
+	})
+	if err != nil {
+		t.Fatalf("new circuit breaker: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = breaker.PostAlerts(ctx, nil)
+
+	if breaker.State() != alertmanager.CircuitClosed {
+		t.Fatalf("expected closed after a canceled call, got %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerIgnoresConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			return fmt.Errorf("%w: no slot freed within 1s", alertmanager.ErrConcurrencyLimit)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new circuit breaker: %v", err)
+	}
+
+	_ = breaker.PostAlerts(context.Background(), nil)
+
+	if breaker.State() != alertmanager.CircuitClosed {
+		t.Fatalf("expected closed after waiting for a concurrency slot, got %s", breaker.State())
+	}
+}
+
+// fakeStatusError stands in for the client's HTTPStatusError.
+type fakeStatusError int
+
+func (e fakeStatusError) Error() string   { return fmt.Sprintf("alertmanager returned %d", int(e)) }
+func (e fakeStatusError) StatusCode() int { return int(e) }
+func (e fakeStatusError) Body() string    { return "" }
+
+func TestCircuitBreakerCountsOnlyUpstreamFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err    error
+		opened bool
+	}{
+		{fakeStatusError(http.StatusBadRequest), false},
+		{fakeStatusError(http.StatusUnprocessableEntity), false},
+		{fakeStatusError(http.StatusTooManyRequests), true},
+		{fakeStatusError(http.StatusServiceUnavailable), true},
+		{errUpstreamDown, true},
+	} {
+		breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+			FailureThreshold: 1,
+			Cooldown:         time.Hour,
+			Post: func(context.Context, []alertmanager.Alert) error {
+				return fmt.Errorf("post alerts: %w", tc.err)
+			},
+		})
+		if err != nil {
+			t.Fatalf("new circuit breaker: %v", err)
+		}
+
+		_ = breaker.PostAlerts(context.Background(), nil)
+
+		if opened := breaker.State() == alertmanager.CircuitOpen; opened != tc.opened {
+			t.Fatalf("%v: expected opened=%v, got state %s", tc.err, tc.opened, breaker.State())
+		}
+	}
+}
+
+type resultChanKey struct{}
+
+func TestCircuitBreakerIgnoresStragglerWhileProbing(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+
+	breaker, err := alertmanager.NewCircuitBreaker(&alertmanager.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Cooldown:         20 * time.Millisecond,
+		Post: func(ctx context.Context, _ []alertmanager.Alert) error {
+			started <- struct{}{}
+
+			result, _ := ctx.Value(resultChanKey{}).(chan error)
+
+			return <-result
+		},
+	})
+	if err != nil {
+		t.Fatalf("new circuit breaker: %v", err)
+	}
+
+	post := func() (chan error, chan error) {
+		result, done := make(chan error), make(chan error, 1)
+		ctx := context.WithValue(context.Background(), resultChanKey{}, result)
+
+		go func() { done <- breaker.PostAlerts(ctx, nil) }()
+
+		<-started
+
+		return result, done
+	}
+
+	// Admitted while closed, still in flight when the circuit opens.
+	stragglerResult, stragglerDone := post()
+
+	failingResult, failingDone := post()
+	failingResult <- errUpstreamDown
+	<-failingDone
+
+	time.Sleep(30 * time.Millisecond)
+
+	probeResult, probeDone := post()
+
+	stragglerResult <- nil
+	<-stragglerDone
+
+	if breaker.State() != alertmanager.CircuitHalfOpen {
+		t.Fatalf("expected a straggler's success not to close the circuit, got %s", breaker.State())
+	}
+
+	probeResult <- errUpstreamDown
+	<-probeDone
+
+	if breaker.State() != alertmanager.CircuitOpen {
+		t.Fatalf("expected the failed probe to re-open the circuit, got %s", breaker.State())
+	}
+}
@@ -26,18 +26,27 @@ package alertmanager
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -46,10 +55,22 @@ const (
 	defaultRetryMaxAttempts = 3
 	defaultRetryInitial     = 200 * time.Millisecond
 	defaultRetryMaxBackoff  = 1 * time.Second
+
+	// compressMinBytes is the smallest body worth gzipping when CompressRequests is set.
+	compressMinBytes = 1024
+
+	// Connection pool defaults. net/http keeps only two idle connections per host, which
+	// forces new dials when a single Alertmanager receives bursts of concurrent posts.
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
 )
 
 var ErrContextDone = errors.New("context done")
 
+// tracer resolves through the global provider, so spans are no-ops unless tracing is set up.
+var tracer = otel.Tracer("github.com/leinardi/gotilert/internal/alertmanager")
+
 type Auth struct {
 	BasicUsername string
 	BasicPassword string
@@ -57,20 +78,103 @@ type Auth struct {
 }
 
 type Options struct {
-	BaseURL            string
+	BaseURL string
+	// BaseURLs lists Alertmanager cluster peers. Requests try peers in order and fail over
+	// to the next one on connection-level failures. BaseURL, when set, is tried first.
+	BaseURLs           []string
 	Timeout            time.Duration
 	InsecureSkipVerify bool
 	Auth               Auth
+
+	// Optional mutual TLS: client certificate/key pair and a CA bundle for server verification.
+	ClientCertFile string
+	ClientKeyFile  string
+	CACertFile     string
+	// CABundle is an inline PEM bundle, combined with CACertFile when both are set.
+	CABundle string
+
+	// ProxyURL routes requests through an http://, https:// or socks5:// proxy.
+	// When empty, HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment are honored.
+	ProxyURL string
+
+	// Connection pool tuning for the HTTP transport. Zero values fall back to the built-in
+	// defaults; MaxConnsPerHost=0 leaves connections per host unlimited.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+
+	// DisableKeepAlives dials a new connection (and re-resolves DNS) for every request.
+	// ConnMaxAge, when > 0, stops reusing pooled connections older than this, so DNS is
+	// re-resolved periodically without paying a dial per request. Both trade latency for
+	// following Alertmanager endpoints whose IP changes (e.g. Kubernetes pods).
+	DisableKeepAlives bool
+	ConnMaxAge        time.Duration
+
+	// APIVersion selects the alerts API: APIVersionV2 (default, /api/v2/alerts) or
+	// APIVersionV1 (/api/v1/alerts) for Alertmanager releases without the v2 API.
+	APIVersion string
+
+	// AlertsPath and ReadyPath override the alerts endpoint (default /api/<APIVersion>/alerts)
+	// and the readiness endpoint (default /-/ready) for proxies that rewrite paths. They are
+	// joined to each base URL's path, so a base URL mounted under a subpath keeps its prefix.
+	AlertsPath string
+	ReadyPath  string
+
+	// CompressRequests gzips request bodies larger than compressMinBytes and sets
+	// Content-Encoding: gzip. Alertmanager does not decode compressed requests, so this only
+	// works behind a proxy that decompresses them.
+	CompressRequests bool
+
+	// Headers are added to every outgoing request (e.g. X-Scope-OrgID for Mimir/Cortex).
+	// Authorization and Content-Type are always controlled by the client and cannot be overridden.
+	Headers map[string]string
+
+	// Retry tuning for PostAlerts. Zero values fall back to the built-in defaults;
+	// RetryMaxAttempts=1 disables retries.
+	RetryMaxAttempts    int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+
+	// RetryStatusCodes adds upstream statuses to the default retryable set (429 + 5xx);
+	// NoRetryStatusCodes removes them and wins when a code is in both.
+	RetryStatusCodes   []int
+	NoRetryStatusCodes []int
+
+	// OnRetry, when set, is called before each retry with the request context, the number
+	// of the attempt that just failed and its error.
+	OnRetry RetryHook
+
+	// DisableJitter makes retry backoff a deterministic doubling sequence.
+	DisableJitter bool
+	// RandSource seeds the jitter generator; nil means a time-seeded source.
+	RandSource rand.Source
 }
 
+// RetryHook observes PostAlerts retries (e.g. for metrics).
+type RetryHook func(ctx context.Context, attempt int, err error)
+
 type Client struct {
-	baseURL    *url.URL
+	baseURLs   []*url.URL
 	httpClient *http.Client
 	auth       Auth
+	headers    http.Header
+	apiVersion string
+	alertsPath string
+	readyPath  string
+	compress   bool
 
 	retryMaxAttempts int
 	retryInitial     time.Duration
 	retryMaxBackoff  time.Duration
+	onRetry          RetryHook
+
+	// retryStatus overrides the default retry decision for the listed status codes.
+	retryStatus map[int]bool
+
+	// jitterRand is nil when jitter is disabled. *rand.Rand is not goroutine-safe.
+	jitterRand  *rand.Rand
+	jitterMutex sync.Mutex
 }
 
 // HTTPStatusError is returned (wrapped) when Alertmanager responds with a non-2xx status.
@@ -103,14 +207,24 @@ func New(opts *Options) (*Client, error) {
 		return nil, ErrInvalidConfiguration
 	}
 
-	baseURLRaw := strings.TrimSpace(opts.BaseURL)
-	if baseURLRaw == "" {
-		return nil, ErrBaseURLMissing
+	baseURLs, err := parseBaseURLs(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	parsed, err := url.Parse(baseURLRaw)
+	apiVersion, err := normalizeAPIVersion(opts.APIVersion)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrInvalidConfiguration, err)
+		return nil, err
+	}
+
+	alertsEndpoint, err := endpointPath(opts.AlertsPath, alertsPath(apiVersion))
+	if err != nil {
+		return nil, err
+	}
+
+	readyEndpoint, err := endpointPath(opts.ReadyPath, defaultReadyPath)
+	if err != nil {
+		return nil, err
 	}
 
 	timeout := opts.Timeout
@@ -118,8 +232,10 @@ func New(opts *Options) (*Client, error) {
 		timeout = defaultHTTPTimeout
 	}
 
-	tlsConfig := &tls.Config{} //nolint:gosec // user-configured option; explicitly supported for self-signed homelab setups.
-	tlsConfig.InsecureSkipVerify = opts.InsecureSkipVerify
+	tlsConfig, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	baseTransport, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
@@ -128,23 +244,158 @@ func New(opts *Options) (*Client, error) {
 
 	transport := baseTransport.Clone()
 	transport.TLSClientConfig = tlsConfig
+	applyConnectionPool(transport, opts)
+
+	err = applyProxy(transport, opts.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if opts.ConnMaxAge > 0 && !opts.DisableKeepAlives {
+		roundTripper = newMaxAgeTransport(transport, opts.ConnMaxAge)
+	}
 
 	httpClient := &http.Client{
-		Transport: transport,
+		Transport: roundTripper,
 		Timeout:   timeout,
 	}
 
 	return &Client{
-		baseURL:    parsed,
+		baseURLs:   baseURLs,
 		httpClient: httpClient,
 		auth:       normalizeAuth(opts.Auth),
-
-		retryMaxAttempts: defaultRetryMaxAttempts,
-		retryInitial:     defaultRetryInitial,
-		retryMaxBackoff:  defaultRetryMaxBackoff,
+		headers:    normalizeHeaders(opts.Headers),
+		apiVersion: apiVersion,
+		alertsPath: alertsEndpoint,
+		readyPath:  readyEndpoint,
+		compress:   opts.CompressRequests,
+
+		retryMaxAttempts: pickInt(opts.RetryMaxAttempts, defaultRetryMaxAttempts),
+		retryInitial:     pickDuration(opts.RetryInitialBackoff, defaultRetryInitial),
+		retryMaxBackoff:  pickDuration(opts.RetryMaxBackoff, defaultRetryMaxBackoff),
+		onRetry:          opts.OnRetry,
+		retryStatus:      retryStatusOverrides(opts),
+
+		jitterRand: newJitterRand(opts),
 	}, nil
 }
 
+func applyConnectionPool(transport *http.Transport, opts *Options) {
+	transport.MaxIdleConns = pickInt(opts.MaxIdleConns, defaultMaxIdleConns)
+	transport.MaxIdleConnsPerHost = pickInt(opts.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+	transport.MaxConnsPerHost = max(opts.MaxConnsPerHost, 0)
+	transport.IdleConnTimeout = pickDuration(opts.IdleConnTimeout, defaultIdleConnTimeout)
+	transport.DisableKeepAlives = opts.DisableKeepAlives
+}
+
+func applyProxy(transport *http.Transport, rawProxyURL string) error {
+	trimmed := strings.TrimSpace(rawProxyURL)
+	if trimmed == "" {
+		// Keep the DefaultTransport behavior (ProxyFromEnvironment).
+		return nil
+	}
+
+	proxyURL, err := url.Parse(trimmed)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProxyURL, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+		// ok
+	default:
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidProxyURL, proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidProxyURL)
+	}
+
+	transport.Proxy = http.ProxyURL(proxyURL)
+
+	return nil
+}
+
+func parseBaseURLs(opts *Options) ([]*url.URL, error) {
+	rawURLs := make([]string, 0, len(opts.BaseURLs)+1)
+	rawURLs = append(rawURLs, opts.BaseURL)
+	rawURLs = append(rawURLs, opts.BaseURLs...)
+
+	baseURLs := make([]*url.URL, 0, len(rawURLs))
+
+	for _, rawURL := range rawURLs {
+		trimmed := strings.TrimSpace(rawURL)
+		if trimmed == "" {
+			continue
+		}
+
+		parsed, err := url.Parse(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfiguration, err)
+		}
+
+		baseURLs = append(baseURLs, parsed)
+	}
+
+	if len(baseURLs) == 0 {
+		return nil, ErrBaseURLMissing
+	}
+
+	return baseURLs, nil
+}
+
+// endpointPath returns override (or fallback when empty) as an absolute path. Queries and
+// fragments are rejected: the path is joined to every base URL.
+func endpointPath(override, fallback string) (string, error) {
+	endpoint := strings.TrimSpace(override)
+	if endpoint == "" {
+		return fallback, nil
+	}
+
+	if strings.ContainsAny(endpoint, "?#") {
+		return "", fmt.Errorf("%w: endpoint path %q must not contain a query or fragment", ErrInvalidConfiguration, endpoint)
+	}
+
+	return "/" + strings.TrimLeft(endpoint, "/"), nil
+}
+
+// endpointURL joins endpoint to the base URL's path rather than replacing it, so a base URL
+// like https://host/alertmanager posts to https://host/alertmanager/api/v2/alerts.
+func endpointURL(baseURL *url.URL, endpoint string) *url.URL {
+	return baseURL.JoinPath(endpoint)
+}
+
+func newJitterRand(opts *Options) *rand.Rand {
+	if opts.DisableJitter {
+		return nil
+	}
+
+	source := opts.RandSource
+	if source == nil {
+		seed := uint64(time.Now().UnixNano()) //nolint:gosec // wall clock is non-negative; only used as a seed.
+		source = rand.NewPCG(seed, seed>>1)
+	}
+
+	return rand.New(source) //nolint:gosec // jitter does not need a cryptographically secure source.
+}
+
+func pickInt(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func pickDuration(value, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
 func normalizeAuth(auth Auth) Auth {
 	auth.BasicUsername = strings.TrimSpace(auth.BasicUsername)
 	auth.BasicPassword = strings.TrimSpace(auth.BasicPassword)
@@ -154,7 +405,7 @@ func normalizeAuth(auth Auth) Auth {
 }
 
 func (client *Client) PostAlerts(ctx context.Context, alerts []Alert) error {
-	if client == nil || client.httpClient == nil || client.baseURL == nil {
+	if client == nil || client.httpClient == nil || len(client.baseURLs) == 0 {
 		return ErrClientNil
 	}
 
@@ -173,11 +424,17 @@ func (client *Client) PostAlerts(ctx context.Context, alerts []Alert) error {
 		}
 
 		// Decide whether retry is appropriate.
-		if !shouldRetry(err) || attempt == attempts {
+		if !client.shouldRetry(err) || attempt == attempts {
 			return err
 		}
 
-		backoff := computeBackoff(attempt, client.retryInitial, client.retryMaxBackoff)
+		if client.onRetry != nil {
+			client.onRetry(ctx, attempt, err)
+		}
+
+		backoff := client.jitteredBackoff(
+			computeBackoff(attempt, client.retryInitial, client.retryMaxBackoff),
+		)
 
 		sleepErr := sleepWithContext(ctx, backoff)
 		if sleepErr != nil {
@@ -188,6 +445,44 @@ func (client *Client) PostAlerts(ctx context.Context, alerts []Alert) error {
 	return ErrDoRequest
 }
 
+// isReservedHeader reports whether name is a header the client always sets itself.
+func isReservedHeader(name string) bool {
+	switch http.CanonicalHeaderKey(strings.TrimSpace(name)) {
+	case "Authorization", "Content-Type":
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeHeaders(headers map[string]string) http.Header {
+	normalized := make(http.Header, len(headers))
+
+	for name, value := range headers {
+		trimmedName := strings.TrimSpace(name)
+		if trimmedName == "" || isReservedHeader(trimmedName) {
+			continue
+		}
+
+		normalized.Set(trimmedName, strings.TrimSpace(value))
+	}
+
+	return normalized
+}
+
+// applyHeaders sets custom headers first and auth last, so auth can never be overridden.
+func (client *Client) applyHeaders(req *http.Request) {
+	if req == nil {
+		return
+	}
+
+	for name, values := range client.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+
+	client.applyAuth(req)
+}
+
 func (client *Client) applyAuth(req *http.Request) {
 	if req == nil {
 		return
@@ -206,14 +501,89 @@ func (client *Client) applyAuth(req *http.Request) {
 	}
 }
 
-func (client *Client) postAlertsOnce(ctx context.Context, alerts []Alert) error {
-	endpoint := client.baseURL.ResolveReference(&url.URL{Path: "/api/v2/alerts"})
+// postAlertsOnce performs a single delivery attempt, failing over across peers.
+// Only connection-level failures move on to the next peer; an HTTP response (of any status)
+// from a peer ends the attempt.
+func (client *Client) postAlertsOnce(ctx context.Context, alerts []Alert) (err error) {
+	ctx, span := tracer.Start(ctx, "alertmanager.PostAlerts",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("gotilert.alerts", len(alerts))),
+	)
+
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
 
-	bodyBytes, err := json.Marshal(alerts)
+		span.End()
+	}()
+
+	bodyBytes, err := encodeAlerts(client.apiVersion, alerts)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrEncodeRequest, err)
 	}
 
+	contentEncoding := ""
+
+	if client.compress && len(bodyBytes) > compressMinBytes {
+		bodyBytes, err = gzipBytes(bodyBytes)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrEncodeRequest, err)
+		}
+
+		contentEncoding = "gzip"
+	}
+
+	var lastErr error
+
+	for _, baseURL := range client.baseURLs {
+		lastErr = client.postAlertsToPeer(ctx, baseURL, bodyBytes, contentEncoding)
+		if !isConnectionFailure(ctx, lastErr) {
+			return lastErr
+		}
+	}
+
+	return lastErr
+}
+
+// gzipBytes compresses an encoded request body.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, fmt.Errorf("gzip request body: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("gzip request body: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// isConnectionFailure reports whether err means the peer could not be reached at all
+// (as opposed to a peer response or a caller-driven cancellation).
+func isConnectionFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	return errors.Is(err, ErrDoRequest)
+}
+
+func (client *Client) postAlertsToPeer(
+	ctx context.Context,
+	baseURL *url.URL,
+	bodyBytes []byte,
+	contentEncoding string,
+) error {
+	endpoint := endpointURL(baseURL, client.alertsPath)
+
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -224,8 +594,13 @@ func (client *Client) postAlertsOnce(ctx context.Context, alerts []Alert) error
 		return fmt.Errorf("%w: %w", ErrCreateRequest, err)
 	}
 
+	client.applyHeaders(req)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	client.applyAuth(req)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
 
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
@@ -234,6 +609,12 @@ func (client *Client) postAlertsOnce(ctx context.Context, alerts []Alert) error
 
 	defer func() { _ = resp.Body.Close() }()
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		semconv.ServerAddress(baseURL.Hostname()),
+		semconv.HTTPResponseStatusCode(resp.StatusCode),
+	)
+	recordResponseStatus(ctx, resp.StatusCode)
+
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		limitedReader := io.LimitReader(resp.Body, maxErrorBodyBytes)
 
@@ -242,7 +623,7 @@ func (client *Client) postAlertsOnce(ctx context.Context, alerts []Alert) error
 			return fmt.Errorf("%w: %w", ErrReadResponseBody, readErr)
 		}
 
-		msg := strings.TrimSpace(string(data))
+		msg := errorMessage(client.apiVersion, data)
 		if msg == "" {
 			msg = resp.Status
 		}
@@ -265,6 +646,36 @@ func ShouldRetry(err error) bool {
 	return shouldRetry(err)
 }
 
+// retryStatusOverrides returns nil when the default 429 + 5xx policy applies unchanged.
+func retryStatusOverrides(opts *Options) map[int]bool {
+	if len(opts.RetryStatusCodes) == 0 && len(opts.NoRetryStatusCodes) == 0 {
+		return nil
+	}
+
+	overrides := make(map[int]bool, len(opts.RetryStatusCodes)+len(opts.NoRetryStatusCodes))
+	for _, code := range opts.RetryStatusCodes {
+		overrides[code] = true
+	}
+
+	for _, code := range opts.NoRetryStatusCodes {
+		overrides[code] = false
+	}
+
+	return overrides
+}
+
+// shouldRetry applies the client's status code overrides before the default policy.
+func (client *Client) shouldRetry(err error) bool {
+	var statusErr *statusError
+	if client.retryStatus != nil && errors.As(err, &statusErr) {
+		if retry, ok := client.retryStatus[statusErr.StatusCode()]; ok {
+			return retry
+		}
+	}
+
+	return shouldRetry(err)
+}
+
 func shouldRetry(err error) bool {
 	if err == nil {
 		return false
@@ -357,6 +768,23 @@ func computeBackoff(attempt int, initial, maxBackoff time.Duration) time.Duratio
 	return backoff
 }
 
+// jitteredBackoff applies "equal jitter": half of the backoff is kept and the other half
+// is randomized, so concurrent instances don't retry in lockstep. The result stays
+// within [backoff/2, backoff] and therefore never exceeds retryMaxBackoff.
+func (client *Client) jitteredBackoff(backoff time.Duration) time.Duration {
+	if client.jitterRand == nil || backoff <= 0 {
+		return backoff
+	}
+
+	half := backoff / 2
+
+	client.jitterMutex.Lock()
+	jitter := time.Duration(client.jitterRand.Int64N(int64(backoff-half) + 1))
+	client.jitterMutex.Unlock()
+
+	return half + jitter
+}
+
 func sleepWithContext(ctx context.Context, d time.Duration) error {
 	timer := time.NewTimer(d)
 	defer timer.Stop()
@@ -0,0 +1,140 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestPostAlertsCompressesLargeBodiesOnEveryAttempt(t *testing.T) {
+	t.Parallel()
+
+	var requestCount atomic.Int32
+
+	upstream := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Header.Get("Content-Encoding") != "gzip" {
+				t.Errorf("expected Content-Encoding gzip, got %q", request.Header.Get("Content-Encoding"))
+				writer.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			alerts, err := decodeGzipAlerts(request.Body)
+			if err != nil || len(alerts) != 1 {
+				t.Errorf("expected one gzipped alert, got %d (err=%v)", len(alerts), err)
+				writer.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			// Fail once so the retry has to resend the compressed body.
+			if requestCount.Add(1) == 1 {
+				writer.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:             upstream.URL,
+		Timeout:             2 * time.Second,
+		CompressRequests:    true,
+		RetryInitialBackoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	postErr := client.PostAlerts(context.Background(), []alertmanager.Alert{
+		{Labels: map[string]string{"alertname": "Test"}, Annotations: map[string]string{"description": strings.Repeat("x", 4096)}},
+	})
+	if postErr != nil {
+		t.Fatalf("PostAlerts: expected success, got %v", postErr)
+	}
+
+	if gotCount := requestCount.Load(); gotCount != 2 {
+		t.Fatalf("expected 2 attempts, got %d", gotCount)
+	}
+}
+
+func TestPostAlertsLeavesSmallBodiesUncompressed(t *testing.T) {
+	t.Parallel()
+
+	var encoding atomic.Value
+
+	upstream := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			encoding.Store(request.Header.Get("Content-Encoding"))
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:          upstream.URL,
+		CompressRequests: true,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	postErr := client.PostAlerts(context.Background(), []alertmanager.Alert{{Labels: map[string]string{"alertname": "Test"}}})
+	if postErr != nil {
+		t.Fatalf("PostAlerts: %v", postErr)
+	}
+
+	if got := encoding.Load(); got != "" {
+		t.Fatalf("expected no Content-Encoding for a small body, got %q", got)
+	}
+}
+
+func decodeGzipAlerts(body io.Reader) ([]alertmanager.Alert, error) {
+	reader, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []alertmanager.Alert
+
+	err = json.NewDecoder(reader).Decode(&alerts)
+
+	return alerts, err
+}
@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type ConcurrencyLimiterOptions struct {
+	// Max is how many posts may run at once. Must be > 0.
+	Max int
+	// Wait bounds how long a post waits for a free slot (0 = until ctx ends).
+	Wait time.Duration
+
+	Post PostFunc
+
+	// OnInflight observes the number of running posts after each change (e.g. for metrics).
+	OnInflight func(inflight int)
+}
+
+// ConcurrencyLimiter caps concurrent posts so a burst of /message requests cannot open an
+// unbounded number of connections to Alertmanager. Callers over the limit wait for a slot
+// and get ErrConcurrencyLimit when none frees up in time.
+type ConcurrencyLimiter struct {
+	wait       time.Duration
+	post       PostFunc
+	onInflight func(inflight int)
+
+	slots    chan struct{}
+	inflight atomic.Int64
+}
+
+func NewConcurrencyLimiter(opts *ConcurrencyLimiterOptions) (*ConcurrencyLimiter, error) {
+	if opts == nil || opts.Post == nil || opts.Max <= 0 || opts.Wait < 0 {
+		return nil, fmt.Errorf("%w: concurrency limiter requires a positive max and a post func", ErrInvalidConfiguration)
+	}
+
+	return &ConcurrencyLimiter{
+		wait:       opts.Wait,
+		post:       opts.Post,
+		onInflight: opts.OnInflight,
+		slots:      make(chan struct{}, opts.Max),
+	}, nil
+}
+
+func (limiter *ConcurrencyLimiter) PostAlerts(ctx context.Context, alerts []Alert) error {
+	err := limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer limiter.release()
+
+	return limiter.post(ctx, alerts)
+}
+
+func (limiter *ConcurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case limiter.slots <- struct{}{}:
+		limiter.report(limiter.inflight.Add(1))
+
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+
+	if limiter.wait > 0 {
+		timer := time.NewTimer(limiter.wait)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case limiter.slots <- struct{}{}:
+		limiter.report(limiter.inflight.Add(1))
+
+		return nil
+	case <-timeout:
+		return fmt.Errorf("%w: no slot freed within %s", ErrConcurrencyLimit, limiter.wait)
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w: %w", ErrConcurrencyLimit, ErrContextDone, ctx.Err())
+	}
+}
+
+func (limiter *ConcurrencyLimiter) release() {
+	<-limiter.slots
+	limiter.report(limiter.inflight.Add(-1))
+}
+
+func (limiter *ConcurrencyLimiter) report(inflight int64) {
+	if limiter.onInflight != nil {
+		limiter.onInflight(int(inflight))
+	}
+}
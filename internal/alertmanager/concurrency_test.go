@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestConcurrencyLimiterRejectsAfterWait(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	inflight := make(chan int, 8)
+
+	limiter, err := alertmanager.NewConcurrencyLimiter(&alertmanager.ConcurrencyLimiterOptions{
+		Max:  1,
+		Wait: 20 * time.Millisecond,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			close(started)
+			<-release
+
+			return nil
+		},
+		OnInflight: func(value int) { inflight <- value },
+	})
+	if err != nil {
+		t.Fatalf("new concurrency limiter: %v", err)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- limiter.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	}()
+
+	<-started
+
+	err = limiter.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	if !errors.Is(err, alertmanager.ErrConcurrencyLimit) {
+		t.Fatalf("expected ErrConcurrencyLimit, got %v", err)
+	}
+
+	close(release)
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("first PostAlerts: %v", err)
+	}
+
+	if first, second := <-inflight, <-inflight; first != 1 || second != 0 {
+		t.Fatalf("expected inflight 1 then 0, got %d then %d", first, second)
+	}
+}
+
+func TestConcurrencyLimiterWaitsForFreeSlot(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	limiter, err := alertmanager.NewConcurrencyLimiter(&alertmanager.ConcurrencyLimiterOptions{
+		Max: 1,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			started <- struct{}{}
+			<-release
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new concurrency limiter: %v", err)
+	}
+
+	done := make(chan error, 2)
+
+	for range 2 {
+		go func() {
+			done <- limiter.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+		}()
+	}
+
+	<-started
+
+	select {
+	case <-started:
+		t.Fatalf("expected the second post to wait for a slot")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+
+	for range 2 {
+		err = <-done
+		if err != nil {
+			t.Fatalf("PostAlerts: %v", err)
+		}
+	}
+}
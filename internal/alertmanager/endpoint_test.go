@@ -0,0 +1,149 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+// pathRecorder is an upstream that answers 200 and records every request path.
+func pathRecorder(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mutex sync.Mutex
+		paths []string
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mutex.Lock()
+		paths = append(paths, request.URL.Path)
+		mutex.Unlock()
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	return upstream, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]string(nil), paths...)
+	}
+}
+
+func TestClientCustomEndpointPaths(t *testing.T) {
+	t.Parallel()
+
+	upstream, paths := pathRecorder(t)
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:    upstream.URL + "/am",
+		AlertsPath: "custom/alerts",
+		ReadyPath:  "/health/ready",
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = client.PostAlerts(context.Background(), []alertmanager.Alert{{
+		Labels:   map[string]string{"alertname": "Test"},
+		StartsAt: time.Now().UTC(),
+		EndsAt:   time.Now().UTC().Add(time.Minute),
+	}})
+	if err != nil {
+		t.Fatalf("PostAlerts: %v", err)
+	}
+
+	err = client.Ready(context.Background())
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+
+	got := paths()
+	if len(got) != 2 || got[0] != "/am/custom/alerts" || got[1] != "/am/health/ready" {
+		t.Fatalf("expected [/am/custom/alerts /am/health/ready], got %v", got)
+	}
+}
+
+func TestClientRejectsEndpointPathWithQuery(t *testing.T) {
+	t.Parallel()
+
+	_, err := alertmanager.New(&alertmanager.Options{BaseURL: "http://localhost:9093", AlertsPath: "/alerts?x=1"})
+	if !errors.Is(err, alertmanager.ErrInvalidConfiguration) {
+		t.Fatalf("expected ErrInvalidConfiguration, got: %v", err)
+	}
+}
+
+func TestClientKeepsBaseURLSubpath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		basePath  string
+		wantPaths []string
+	}{
+		{basePath: "", wantPaths: []string{"/api/v2/alerts", "/-/ready"}},
+		{basePath: "/", wantPaths: []string{"/api/v2/alerts", "/-/ready"}},
+		{basePath: "/alertmanager", wantPaths: []string{"/alertmanager/api/v2/alerts", "/alertmanager/-/ready"}},
+		{basePath: "/alertmanager/", wantPaths: []string{"/alertmanager/api/v2/alerts", "/alertmanager/-/ready"}},
+		{basePath: "/proxy/am//", wantPaths: []string{"/proxy/am/api/v2/alerts", "/proxy/am/-/ready"}},
+	}
+
+	for _, testCase := range cases {
+		upstream, paths := pathRecorder(t)
+
+		client, err := alertmanager.New(&alertmanager.Options{BaseURL: upstream.URL + testCase.basePath})
+		if err != nil {
+			t.Fatalf("%q: alertmanager.New: %v", testCase.basePath, err)
+		}
+
+		err = client.PostAlerts(context.Background(), []alertmanager.Alert{{
+			Labels:   map[string]string{"alertname": "Test"},
+			StartsAt: time.Now().UTC(),
+			EndsAt:   time.Now().UTC().Add(time.Minute),
+		}})
+		if err != nil {
+			t.Fatalf("%q: PostAlerts: %v", testCase.basePath, err)
+		}
+
+		err = client.Ready(context.Background())
+		if err != nil {
+			t.Fatalf("%q: Ready: %v", testCase.basePath, err)
+		}
+
+		got := paths()
+		if len(got) != 2 || got[0] != testCase.wantPaths[0] || got[1] != testCase.wantPaths[1] {
+			t.Fatalf("%q: expected %v, got %v", testCase.basePath, testCase.wantPaths, got)
+		}
+	}
+}
@@ -36,4 +36,12 @@ var (
 	ErrReadResponseBody     = errors.New("read response body failed")
 	ErrInvalidConfiguration = errors.New("invalid alertmanager configuration")
 	ErrNotReady             = errors.New("alertmanager not ready")
+	ErrTLSConfig            = errors.New("invalid alertmanager tls configuration")
+	ErrInvalidCABundle      = errors.New("invalid alertmanager ca bundle")
+	ErrInvalidProxyURL      = errors.New("invalid alertmanager proxy url")
+	ErrBatcherClosed        = errors.New("alertmanager batcher is closed")
+	ErrCircuitOpen          = errors.New("alertmanager circuit breaker is open")
+	ErrQueueFull            = errors.New("alertmanager forward queue is full")
+	ErrQueueClosed          = errors.New("alertmanager forward queue is closed")
+	ErrConcurrencyLimit     = errors.New("alertmanager concurrency limit reached")
 )
@@ -0,0 +1,86 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxAgeTransport bounds how long pooled connections are reused. Once the active transport
+// is older than maxAge it is replaced by a fresh clone, so the next request dials (and
+// re-resolves DNS) again; the retired transport's idle connections are closed right away.
+// Connections still serving a request finish normally and age out via IdleConnTimeout.
+type maxAgeTransport struct {
+	template *http.Transport
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	current *http.Transport
+	created time.Time
+}
+
+func newMaxAgeTransport(template *http.Transport, maxAge time.Duration) *maxAgeTransport {
+	return &maxAgeTransport{
+		template: template,
+		maxAge:   maxAge,
+		now:      time.Now,
+		current:  template.Clone(),
+		created:  time.Now(),
+	}
+}
+
+func (transport *maxAgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return transport.active().RoundTrip(req)
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach the active transport.
+func (transport *maxAgeTransport) CloseIdleConnections() {
+	transport.mutex.Lock()
+	current := transport.current
+	transport.mutex.Unlock()
+
+	current.CloseIdleConnections()
+}
+
+func (transport *maxAgeTransport) active() *http.Transport {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	now := transport.now()
+	if now.Sub(transport.created) < transport.maxAge {
+		return transport.current
+	}
+
+	retired := transport.current
+	transport.current = transport.template.Clone()
+	transport.created = now
+
+	retired.CloseIdleConnections()
+
+	return transport.current
+}
@@ -0,0 +1,158 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func clientTransport(t *testing.T, client *Client) *http.Transport {
+	t.Helper()
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+
+	return transport
+}
+
+func TestConnectionPoolDefaults(t *testing.T) {
+	t.Parallel()
+
+	client, err := New(&Options{BaseURL: "http://alertmanager.example.local"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	transport := clientTransport(t, client)
+	if transport.MaxIdleConns != defaultMaxIdleConns ||
+		transport.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost ||
+		transport.MaxConnsPerHost != 0 ||
+		transport.IdleConnTimeout != defaultIdleConnTimeout {
+		t.Fatalf(
+			"expected default pool (%d, %d, 0, %s), got (%d, %d, %d, %s)",
+			defaultMaxIdleConns, defaultMaxIdleConnsPerHost, defaultIdleConnTimeout,
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout,
+		)
+	}
+}
+
+func TestConnectionPoolOverrides(t *testing.T) {
+	t.Parallel()
+
+	client, err := New(&Options{
+		BaseURL:             "http://alertmanager.example.local",
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		MaxConnsPerHost:     20,
+		IdleConnTimeout:     30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	transport := clientTransport(t, client)
+	if transport.MaxIdleConns != 10 || transport.MaxIdleConnsPerHost != 5 ||
+		transport.MaxConnsPerHost != 20 || transport.IdleConnTimeout != 30*time.Second {
+		t.Fatalf(
+			"expected pool (10, 5, 20, 30s), got (%d, %d, %d, %s)",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout,
+		)
+	}
+}
+
+func TestMaxAgeTransportStopsReusingOldConnections(t *testing.T) {
+	t.Parallel()
+
+	var newConns atomic.Int32
+
+	upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	upstream.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	upstream.Start()
+	defer upstream.Close()
+
+	now := time.Now()
+	transport := newMaxAgeTransport(&http.Transport{}, time.Minute)
+	transport.now = func() time.Time { return now }
+	transport.created = now
+	httpClient := &http.Client{Transport: transport}
+
+	get := func() {
+		t.Helper()
+
+		resp, err := httpClient.Get(upstream.URL)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	get()
+	get()
+
+	if got := newConns.Load(); got != 1 {
+		t.Fatalf("expected 1 connection before max age, got %d", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	get()
+
+	if got := newConns.Load(); got != 2 {
+		t.Fatalf("expected a new connection after max age, got %d connections", got)
+	}
+}
+
+func TestDisableKeepAlivesSkipsMaxAgeTransport(t *testing.T) {
+	t.Parallel()
+
+	client, err := New(&Options{
+		BaseURL:           "http://alertmanager.example.local",
+		DisableKeepAlives: true,
+		ConnMaxAge:        time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if !clientTransport(t, client).DisableKeepAlives {
+		t.Fatalf("expected keep-alives to be disabled")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type QueueOptions struct {
+	// Size bounds how many posts may wait for a worker. Must be > 0.
+	Size int
+	// Workers is how many goroutines drain the queue. Must be > 0.
+	Workers int
+	// Timeout bounds each post (0 = no extra bound beyond the HTTP client timeout).
+	Timeout time.Duration
+
+	Post PostFunc
+
+	// OnDepth observes the number of queued posts after each change (e.g. for metrics).
+	OnDepth func(depth int)
+	// OnResult observes every background post once it finished (err is nil on success);
+	// the caller got 202 long ago.
+	OnResult func(ctx context.Context, elapsed time.Duration, err error)
+}
+
+// Queue makes PostAlerts fire-and-forget: it enqueues the alerts and returns at once,
+// while a fixed pool of workers delivers them through Post. A full queue rejects new
+// alerts with ErrQueueFull instead of blocking the caller.
+type Queue struct {
+	timeout  time.Duration
+	post     PostFunc
+	onDepth  func(depth int)
+	onResult func(ctx context.Context, elapsed time.Duration, err error)
+
+	// mutex guards closed and sends on items, so Close never races an enqueue.
+	mutex  sync.RWMutex
+	closed bool
+	items  chan queueItem
+
+	workers sync.WaitGroup
+}
+
+type queueItem struct {
+	ctx    context.Context //nolint:containedctx // carries request values (e.g. request id) to the worker.
+	alerts []Alert
+}
+
+func NewQueue(opts *QueueOptions) (*Queue, error) {
+	if opts == nil || opts.Post == nil || opts.Size <= 0 || opts.Workers <= 0 {
+		return nil, fmt.Errorf("%w: queue requires a positive size, workers and a post func", ErrInvalidConfiguration)
+	}
+
+	queue := &Queue{
+		timeout:  opts.Timeout,
+		post:     opts.Post,
+		onDepth:  opts.OnDepth,
+		onResult: opts.OnResult,
+		items:    make(chan queueItem, opts.Size),
+	}
+
+	queue.workers.Add(opts.Workers)
+
+	for range opts.Workers {
+		go queue.work()
+	}
+
+	return queue, nil
+}
+
+// PostAlerts enqueues alerts and returns without waiting for delivery. ctx only supplies
+// values to the background post; its cancellation does not abort delivery.
+func (queue *Queue) PostAlerts(ctx context.Context, alerts []Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	queue.mutex.RLock()
+	defer queue.mutex.RUnlock()
+
+	if queue.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case queue.items <- queueItem{ctx: context.WithoutCancel(ctx), alerts: alerts}:
+		queue.reportDepth()
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %d posts pending", ErrQueueFull, cap(queue.items))
+	}
+}
+
+// Len returns the number of posts waiting for a worker.
+func (queue *Queue) Len() int {
+	return len(queue.items)
+}
+
+// Close stops accepting alerts and waits for the workers to deliver everything already
+// queued, or for ctx to end.
+func (queue *Queue) Close(ctx context.Context) error {
+	queue.mutex.Lock()
+
+	if !queue.closed {
+		queue.closed = true
+		close(queue.items)
+	}
+
+	queue.mutex.Unlock()
+
+	finished := make(chan struct{})
+
+	go func() {
+		queue.workers.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+	}
+}
+
+func (queue *Queue) work() {
+	defer queue.workers.Done()
+
+	for item := range queue.items {
+		queue.reportDepth()
+		queue.deliver(item)
+	}
+}
+
+func (queue *Queue) deliver(item queueItem) {
+	ctx := item.ctx
+
+	if queue.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, queue.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := queue.post(ctx, item.alerts)
+
+	if queue.onResult != nil {
+		queue.onResult(item.ctx, time.Since(start), err)
+	}
+}
+
+func (queue *Queue) reportDepth() {
+	if queue.onDepth != nil {
+		queue.onDepth(len(queue.items))
+	}
+}
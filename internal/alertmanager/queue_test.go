@@ -0,0 +1,147 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestQueueDeliversInBackgroundAndDrainsOnClose(t *testing.T) {
+	t.Parallel()
+
+	var delivered, succeeded atomic.Int64
+
+	release := make(chan struct{})
+
+	queue, err := alertmanager.NewQueue(&alertmanager.QueueOptions{
+		Size:    4,
+		Workers: 2,
+		Post: func(_ context.Context, alerts []alertmanager.Alert) error {
+			<-release
+			delivered.Add(int64(len(alerts)))
+
+			return nil
+		},
+		OnResult: func(_ context.Context, _ time.Duration, err error) {
+			if err == nil {
+				succeeded.Add(1)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("new queue: %v", err)
+	}
+
+	for range 3 {
+		// Returns without waiting for the (blocked) post.
+		err = queue.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+		if err != nil {
+			t.Fatalf("PostAlerts: %v", err)
+		}
+	}
+
+	close(release)
+
+	err = queue.Close(context.Background())
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if delivered.Load() != 3 {
+		t.Fatalf("expected 3 alerts delivered before Close returned, got %d", delivered.Load())
+	}
+
+	if succeeded.Load() != 3 {
+		t.Fatalf("expected 3 successful deliveries reported, got %d", succeeded.Load())
+	}
+
+	err = queue.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	if !errors.Is(err, alertmanager.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed, got %v", err)
+	}
+}
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	var failures atomic.Int64
+
+	queue, err := alertmanager.NewQueue(&alertmanager.QueueOptions{
+		Size:    1,
+		Workers: 1,
+		Post: func(context.Context, []alertmanager.Alert) error {
+			started <- struct{}{}
+			<-release
+
+			return errUpstreamDown
+		},
+		OnResult: func(_ context.Context, _ time.Duration, err error) {
+			if err != nil {
+				failures.Add(1)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("new queue: %v", err)
+	}
+
+	// The first post occupies the only worker, the second fills the queue.
+	err = queue.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	if err != nil {
+		t.Fatalf("PostAlerts: %v", err)
+	}
+
+	<-started
+
+	err = queue.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	if err != nil {
+		t.Fatalf("PostAlerts: %v", err)
+	}
+
+	err = queue.PostAlerts(context.Background(), []alertmanager.Alert{{}})
+	if !errors.Is(err, alertmanager.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(release)
+
+	err = queue.Close(context.Background())
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if failures.Load() != 2 {
+		t.Fatalf("expected 2 background failures reported, got %d", failures.Load())
+	}
+}
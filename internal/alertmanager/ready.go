@@ -26,24 +26,44 @@ package alertmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 )
 
+const defaultReadyPath = "/-/ready"
+
+// Ready reports nil when any configured peer is ready; otherwise it returns the joined
+// per-peer errors.
 func (client *Client) Ready(ctx context.Context) error {
-	if client == nil || client.httpClient == nil || client.baseURL == nil {
+	if client == nil || client.httpClient == nil || len(client.baseURLs) == 0 {
 		return ErrClientNil
 	}
 
-	endpoint := client.baseURL.ResolveReference(&url.URL{Path: "/-/ready"})
+	peerErrs := make([]error, 0, len(client.baseURLs))
+
+	for _, baseURL := range client.baseURLs {
+		err := client.readyPeer(ctx, baseURL)
+		if err == nil {
+			return nil
+		}
+
+		peerErrs = append(peerErrs, err)
+	}
+
+	return errors.Join(peerErrs...)
+}
+
+func (client *Client) readyPeer(ctx context.Context, baseURL *url.URL) error {
+	endpoint := endpointURL(baseURL, client.readyPath)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), http.NoBody)
 	if err != nil {
 		return fmt.Errorf("create ready request: %w", err)
 	}
 
-	client.applyAuth(req)
+	client.applyHeaders(req)
 
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
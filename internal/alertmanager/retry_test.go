@@ -212,3 +212,111 @@ func TestPostAlertsDoesNotRetryOn400(t *testing.T) {
 		t.Fatalf("expected 1 attempt, got %d", gotCount)
 	}
 }
+
+func TestPostAlertsMaxAttemptsOneDisablesRetries(t *testing.T) {
+	t.Parallel()
+
+	var requestCount atomic.Int32
+
+	upstream := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			requestCount.Add(1)
+
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:          upstream.URL,
+		Timeout:          2 * time.Second,
+		RetryMaxAttempts: 1,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	postErr := client.PostAlerts(ctx, []alertmanager.Alert{
+		{
+			Labels:   map[string]string{"alertname": "Test"},
+			StartsAt: time.Now().UTC(),
+			EndsAt:   time.Now().UTC().Add(1 * time.Minute),
+		},
+	})
+	if !errors.Is(postErr, alertmanager.ErrUpstreamNon2xx) {
+		t.Fatalf("expected ErrUpstreamNon2xx, got %v", postErr)
+	}
+
+	if gotCount := requestCount.Load(); gotCount != 1 {
+		t.Fatalf("expected 1 attempt, got %d", gotCount)
+	}
+}
+
+func TestPostAlertsRetryStatusOverrides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		opts         alertmanager.Options
+		wantRequests int32
+	}{
+		{
+			name:         "503 retried by default",
+			status:       http.StatusServiceUnavailable,
+			wantRequests: 3,
+		},
+		{
+			name:         "503 not retried when overridden",
+			status:       http.StatusServiceUnavailable,
+			opts:         alertmanager.Options{NoRetryStatusCodes: []int{http.StatusServiceUnavailable}},
+			wantRequests: 1,
+		},
+		{
+			name:         "409 retried when added",
+			status:       http.StatusConflict,
+			opts:         alertmanager.Options{RetryStatusCodes: []int{http.StatusConflict}},
+			wantRequests: 3,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requestCount atomic.Int32
+
+			upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				requestCount.Add(1)
+				writer.WriteHeader(testCase.status)
+			}))
+			defer upstream.Close()
+
+			opts := testCase.opts
+			opts.BaseURL = upstream.URL
+			opts.RetryInitialBackoff = time.Millisecond
+			opts.RetryMaxBackoff = time.Millisecond
+
+			client, err := alertmanager.New(&opts)
+			if err != nil {
+				t.Fatalf("alertmanager.New: %v", err)
+			}
+
+			postErr := client.PostAlerts(context.Background(), []alertmanager.Alert{{
+				Labels:   map[string]string{"alertname": "Test"},
+				StartsAt: time.Now().UTC(),
+				EndsAt:   time.Now().UTC().Add(time.Minute),
+			}})
+			if postErr == nil {
+				t.Fatalf("expected PostAlerts to fail with status %d", testCase.status)
+			}
+
+			if got := requestCount.Load(); got != testCase.wantRequests {
+				t.Fatalf("expected %d requests, got %d", testCase.wantRequests, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ResponseStatus records the HTTP status of the Alertmanager response that ended a post,
+// including successful ones. Attach it with WithResponseStatus; the zero value is ready to use.
+type ResponseStatus struct {
+	code atomic.Int64
+}
+
+// Code returns the recorded status, or 0 when no response was received.
+func (status *ResponseStatus) Code() int {
+	return int(status.code.Load())
+}
+
+func (status *ResponseStatus) set(code int) {
+	status.code.Store(int64(code))
+}
+
+type responseStatusKey struct{}
+
+// WithResponseStatus makes posts made with the returned context record their final
+// Alertmanager response status into status.
+func WithResponseStatus(ctx context.Context, status *ResponseStatus) context.Context {
+	return context.WithValue(ctx, responseStatusKey{}, status)
+}
+
+// responseStatusFromContext returns the ResponseStatus attached to ctx, or nil.
+func responseStatusFromContext(ctx context.Context) *ResponseStatus {
+	status, _ := ctx.Value(responseStatusKey{}).(*ResponseStatus)
+
+	return status
+}
+
+// recordResponseStatus stores code into the ResponseStatus attached to ctx, if any.
+func recordResponseStatus(ctx context.Context, code int) {
+	if status := responseStatusFromContext(ctx); status != nil {
+		status.set(code)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestResponseStatusRecordsSuccessThroughBatcher(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, _ *http.Request) {
+		responseWriter.WriteHeader(http.StatusAccepted)
+	}))
+	defer upstream.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{BaseURL: upstream.URL})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	var direct alertmanager.ResponseStatus
+
+	err = client.PostAlerts(alertmanager.WithResponseStatus(context.Background(), &direct), []alertmanager.Alert{{}})
+	if err != nil || direct.Code() != http.StatusAccepted {
+		t.Fatalf("expected status 202 recorded by the client, got %d (err %v)", direct.Code(), err)
+	}
+
+	batcher, err := alertmanager.NewBatcher(&alertmanager.BatcherOptions{
+		Window: 10 * time.Millisecond,
+		Post:   client.PostAlerts,
+	})
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+
+	var batched alertmanager.ResponseStatus
+
+	err = batcher.PostAlerts(alertmanager.WithResponseStatus(context.Background(), &batched), []alertmanager.Alert{{}})
+	if err != nil || batched.Code() != http.StatusAccepted {
+		t.Fatalf("expected status 202 handed back by the batcher, got %d (err %v)", batched.Code(), err)
+	}
+}
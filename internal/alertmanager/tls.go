@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func buildTLSConfig(opts *Options) (*tls.Config, error) {
+	tlsConfig := &tls.Config{} //nolint:gosec // user-configured option; explicitly supported for self-signed homelab setups.
+	tlsConfig.InsecureSkipVerify = opts.InsecureSkipVerify
+
+	certFile := strings.TrimSpace(opts.ClientCertFile)
+	keyFile := strings.TrimSpace(opts.ClientKeyFile)
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("%w: client cert and key must be set together", ErrTLSConfig)
+		}
+
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: load client cert %q / key %q: %w",
+				ErrTLSConfig,
+				certFile,
+				keyFile,
+				err,
+			)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	rootCAs, err := loadRootCAs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// When a CA is configured, verification stays enabled against that pool only.
+	tlsConfig.RootCAs = rootCAs
+
+	return tlsConfig, nil
+}
+
+// loadRootCAs builds a dedicated pool from CACertFile and/or the inline CABundle.
+// It returns nil (system roots) when neither is configured.
+func loadRootCAs(opts *Options) (*x509.CertPool, error) {
+	caFile := strings.TrimSpace(opts.CACertFile)
+	caBundle := strings.TrimSpace(opts.CABundle)
+
+	if caFile == "" && caBundle == "" {
+		return nil, nil //nolint:nilnil // nil pool means "use system roots".
+	}
+
+	pool := x509.NewCertPool()
+
+	if caFile != "" {
+		pemData, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read ca file %q: %w", ErrTLSConfig, caFile, err)
+		}
+
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("%w: no certificates found in ca file %q", ErrInvalidCABundle, caFile)
+		}
+	}
+
+	if caBundle != "" && !pool.AppendCertsFromPEM([]byte(caBundle)) {
+		return nil, fmt.Errorf("%w: no certificates found in inline ca bundle", ErrInvalidCABundle)
+	}
+
+	return pool, nil
+}
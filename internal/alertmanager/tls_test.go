@@ -0,0 +1,273 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func TestClientMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	authority := newTestCertificate(t, nil, true)
+	serverCert := newTestCertificate(t, authority, false)
+	clientCert := newTestCertificate(t, authority, false)
+
+	upstream := newMutualTLSServer(t, authority, serverCert)
+	defer upstream.Close()
+
+	dir := t.TempDir()
+	caFile := writeTestFile(t, dir, "ca.pem", authority.certPEM)
+	certFile := writeTestFile(t, dir, "client.pem", clientCert.certPEM)
+	keyFile := writeTestFile(t, dir, "client-key.pem", clientCert.keyPEM)
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:        upstream.URL,
+		Timeout:        2 * time.Second,
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		CACertFile:     caFile,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = client.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: expected no error with client cert, got %v", err)
+	}
+}
+
+func TestClientMismatchedCertAndKeyFails(t *testing.T) {
+	t.Parallel()
+
+	authority := newTestCertificate(t, nil, true)
+	first := newTestCertificate(t, authority, false)
+	second := newTestCertificate(t, authority, false)
+
+	dir := t.TempDir()
+
+	_, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:        "https://alertmanager.example.local",
+		ClientCertFile: writeTestFile(t, dir, "client.pem", first.certPEM),
+		ClientKeyFile:  writeTestFile(t, dir, "client-key.pem", second.keyPEM),
+	})
+	if !errors.Is(err, alertmanager.ErrTLSConfig) {
+		t.Fatalf("expected ErrTLSConfig, got %v", err)
+	}
+}
+
+func TestClientCABundleVerifiesSelfSignedUpstream(t *testing.T) {
+	t.Parallel()
+
+	authority := newTestCertificate(t, nil, true)
+	serverCert := newTestCertificate(t, authority, false)
+
+	keyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	if err != nil {
+		t.Fatalf("tls.X509KeyPair: %v", err)
+	}
+
+	upstream := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	upstream.TLS = &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{keyPair}}
+	upstream.StartTLS()
+
+	defer upstream.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	trusting, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:  upstream.URL,
+		Timeout:  2 * time.Second,
+		CABundle: string(authority.certPEM),
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = trusting.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: expected no error with matching CA bundle, got %v", err)
+	}
+
+	otherAuthority := newTestCertificate(t, nil, true)
+
+	distrusting, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:  upstream.URL,
+		Timeout:  2 * time.Second,
+		CABundle: string(otherAuthority.certPEM),
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = distrusting.Ready(ctx)
+	if err == nil {
+		t.Fatalf("Ready: expected verification failure with unrelated CA bundle")
+	}
+}
+
+func TestClientInvalidCABundle(t *testing.T) {
+	t.Parallel()
+
+	_, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:  "https://alertmanager.example.local",
+		CABundle: "not a pem",
+	})
+	if !errors.Is(err, alertmanager.ErrInvalidCABundle) {
+		t.Fatalf("expected ErrInvalidCABundle, got %v", err)
+	}
+}
+
+func newMutualTLSServer(t *testing.T, authority, serverCert *testCertificate) *httptest.Server {
+	t.Helper()
+
+	keyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	if err != nil {
+		t.Fatalf("tls.X509KeyPair: %v", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(authority.cert)
+
+	upstream := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	upstream.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	upstream.StartTLS()
+
+	return upstream
+}
+
+// newTestCertificate creates a CA (parent == nil) or a leaf valid for 127.0.0.1 signed by parent.
+func newTestCertificate(t *testing.T, parent *testCertificate, isCA bool) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("rand.Int: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "gotilert-test"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+
+	signerCert, signerKey := template, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey: %v", err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	err := os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+
+	return path
+}
@@ -0,0 +1,137 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package alertmanager_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+)
+
+func TestClientFailsOverToNextPeerOnConnectionFailure(t *testing.T) {
+	t.Parallel()
+
+	// A closed server gives a URL that refuses connections.
+	deadPeer := httptest.NewServer(http.NotFoundHandler())
+	deadPeer.Close()
+
+	var postCount atomic.Int32
+
+	livePeer := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == "/api/v2/alerts" {
+				postCount.Add(1)
+			}
+
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer livePeer.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURLs: []string{deadPeer.URL, livePeer.URL},
+		Timeout:  2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = client.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: expected no error with one live peer, got %v", err)
+	}
+
+	err = client.PostAlerts(ctx, []alertmanager.Alert{
+		{
+			Labels:   map[string]string{"alertname": "Test"},
+			StartsAt: time.Now().UTC(),
+			EndsAt:   time.Now().UTC().Add(1 * time.Minute),
+		},
+	})
+	if err != nil {
+		t.Fatalf("PostAlerts: expected failover success, got %v", err)
+	}
+
+	if gotCount := postCount.Load(); gotCount != 1 {
+		t.Fatalf("expected 1 post on the live peer, got %d", gotCount)
+	}
+}
+
+func TestClientRoutesRequestsThroughProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxiedHost atomic.Value
+
+	proxy := httptest.NewServer(
+		http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// A forward proxy receives the absolute target URL.
+			proxiedHost.Store(request.URL.Host)
+			writer.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer proxy.Close()
+
+	client, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:  "http://alertmanager.invalid:9093",
+		Timeout:  2 * time.Second,
+		ProxyURL: proxy.URL,
+	})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err = client.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: expected no error through proxy, got %v", err)
+	}
+
+	if got := proxiedHost.Load(); got != "alertmanager.invalid:9093" {
+		t.Fatalf("expected proxied host %q, got %v", "alertmanager.invalid:9093", got)
+	}
+}
+
+func TestClientRejectsUnsupportedProxyScheme(t *testing.T) {
+	t.Parallel()
+
+	_, err := alertmanager.New(&alertmanager.Options{
+		BaseURL:  "http://alertmanager.example.local",
+		ProxyURL: "ftp://proxy.example.local",
+	})
+	if !errors.Is(err, alertmanager.ErrInvalidProxyURL) {
+		t.Fatalf("expected ErrInvalidProxyURL, got %v", err)
+	}
+}
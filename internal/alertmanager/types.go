@@ -32,4 +32,7 @@ type Alert struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 	StartsAt    time.Time         `json:"startsAt"`
 	EndsAt      time.Time         `json:"endsAt"`
+
+	// GeneratorURL is the link Alertmanager shows for the alert; omitted when empty.
+	GeneratorURL string `json:"generatorURL,omitempty"`
 }
@@ -0,0 +1,220 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package audit writes an append-only JSON lines record of every forwarded alert,
+// separate from the access and application logs.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fileMode = 0o600
+	dirMode  = 0o750
+
+	// flushInterval bounds how long a record can sit in the buffer before reaching the file.
+	flushInterval = time.Second
+)
+
+var ErrOpen = errors.New("cannot open audit file")
+
+// Result values of a Record.
+const (
+	ResultForwarded = "forwarded"
+	ResultFailed    = "failed"
+)
+
+// Record is one audit line.
+type Record struct {
+	Time           time.Time `json:"time"`
+	RequestID      string    `json:"request_id,omitempty"`
+	App            string    `json:"app"`
+	Priority       int       `json:"priority"`
+	Severity       string    `json:"severity"`
+	GotilertID     string    `json:"gotilert_id"`
+	Result         string    `json:"result"`
+	UpstreamStatus int       `json:"upstream_status,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// Log buffers records in memory and flushes them every flushInterval and on Close, so a write
+// on the request path costs a JSON encode under a mutex rather than a disk write.
+// A nil *Log discards records.
+type Log struct {
+	mutex   sync.Mutex
+	writer  *bufio.Writer
+	closer  io.Closer
+	err     error
+	onError func(err error)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Open appends to the file at path, creating it (and its directory) when missing.
+// An empty path disables auditing and returns a nil *Log. onError is passed to New.
+func Open(path string, onError func(err error)) (*Log, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil //nolint:nilnil // a nil *Log is the documented "disabled" value.
+	}
+
+	path = filepath.Clean(path)
+
+	err := os.MkdirAll(filepath.Dir(path), dirMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOpen, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOpen, err)
+	}
+
+	return New(file, onError), nil
+}
+
+// New returns a Log writing to output. When output is an io.Closer, Close closes it.
+// onError, when set, is called for every failed write or background flush as it happens.
+func New(output io.Writer, onError func(err error)) *Log {
+	log := &Log{
+		writer:  bufio.NewWriter(output),
+		onError: onError,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if closer, ok := output.(io.Closer); ok {
+		log.closer = closer
+	}
+
+	go log.flushLoop()
+
+	return log
+}
+
+// Write appends record. Failures go to onError right away and the first one is also returned
+// by Close, so auditing never fails a forward.
+func (log *Log) Write(record *Record) {
+	if log == nil {
+		return
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.fail(fmt.Errorf("encode audit record: %w", err))
+
+		return
+	}
+
+	log.mutex.Lock()
+	_, err = log.writer.Write(append(line, '\n'))
+	log.mutex.Unlock()
+
+	if err != nil {
+		log.fail(fmt.Errorf("write audit record: %w", err))
+	}
+}
+
+// fail keeps the first error for Close and reports every one to onError.
+func (log *Log) fail(err error) {
+	log.mutex.Lock()
+	if log.err == nil {
+		log.err = err
+	}
+	log.mutex.Unlock()
+
+	if log.onError != nil {
+		log.onError(err)
+	}
+}
+
+func (log *Log) flushLoop() {
+	defer close(log.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-log.stop:
+			return
+		case <-ticker.C:
+			err := log.Flush()
+			if err != nil {
+				log.fail(err)
+			}
+		}
+	}
+}
+
+// Flush writes buffered records to the output.
+func (log *Log) Flush() error {
+	if log == nil {
+		return nil
+	}
+
+	log.mutex.Lock()
+	defer log.mutex.Unlock()
+
+	err := log.writer.Flush()
+	if err != nil {
+		return fmt.Errorf("flush audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops the background flush, writes pending records and closes the output. It returns
+// the first write or flush error seen since Open, if any.
+func (log *Log) Close() error {
+	if log == nil {
+		return nil
+	}
+
+	close(log.stop)
+	<-log.done
+
+	errs := []error{log.Flush()}
+
+	if log.closer != nil {
+		errs = append(errs, log.closer.Close())
+	}
+
+	log.mutex.Lock()
+	errs = append(errs, log.err)
+	log.mutex.Unlock()
+
+	return errors.Join(errs...)
+}
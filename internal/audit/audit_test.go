@@ -0,0 +1,135 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/audit"
+)
+
+func TestOpenAppendsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit", "gotilert.jsonl")
+
+	for _, gotilertID := range []string{"first", "second"} {
+		log, err := audit.Open(path, nil)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+
+		log.Write(&audit.Record{Time: time.Now().UTC(), App: "backup", GotilertID: gotilertID, Result: audit.ResultForwarded})
+
+		err = log.Close()
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open audit file: %v", err)
+	}
+	defer file.Close()
+
+	var ids []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record audit.Record
+
+		err = json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+
+		ids = append(ids, record.GotilertID)
+	}
+
+	if len(ids) != 2 || ids[0] != "first" || ids[1] != "second" {
+		t.Fatalf("expected records [first second] appended across opens, got %v", ids)
+	}
+}
+
+func TestOpenEmptyPathDisablesAudit(t *testing.T) {
+	t.Parallel()
+
+	log, err := audit.Open("  ", nil)
+	if err != nil || log != nil {
+		t.Fatalf("expected a nil log without error, got %v, %v", log, err)
+	}
+
+	// A nil log discards records.
+	log.Write(&audit.Record{App: "backup"})
+
+	err = log.Close()
+	if err != nil {
+		t.Fatalf("Close on nil log: %v", err)
+	}
+}
+
+var errDiskFull = errors.New("disk full")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errDiskFull
+}
+
+func TestBackgroundFlushErrorsAreReported(t *testing.T) {
+	t.Parallel()
+
+	reported := make(chan error, 1)
+
+	log := audit.New(failingWriter{}, func(err error) {
+		select {
+		case reported <- err:
+		default:
+		}
+	})
+
+	log.Write(&audit.Record{App: "backup", Result: audit.ResultForwarded})
+
+	select {
+	case err := <-reported:
+		if !errors.Is(err, errDiskFull) {
+			t.Fatalf("expected the flush error to be reported, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the background flush error to be reported before Close")
+	}
+
+	err := log.Close()
+	if !errors.Is(err, errDiskFull) {
+		t.Fatalf("expected Close to return the first error, got %v", err)
+	}
+}
@@ -26,23 +26,52 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/leinardi/gotilert/internal/mapping"
+	"github.com/leinardi/gotilert/internal/templating"
 )
 
 const (
 	DefaultAlertName = "GotilertNotification"
 
+	// CatchAllToken keys an optional apps entry used for tokens that match no other app.
+	CatchAllToken = "*"
+
+	// DefaultMaxPriority is the upper bound of defaults.priorityRange when unset.
+	DefaultMaxPriority = 10
+
+	// DefaultSummaryMaxLen is the defaults.summaryMaxLen used when unset.
+	DefaultSummaryMaxLen = 120
+
+	// DefaultValueMaxLen is the defaults.valueMaxLen used when unset.
+	DefaultValueMaxLen = 4096
+
+	// maxHTTPStatusCode bounds alertmanager.retryStatusCodes / noRetryStatusCodes.
+	maxHTTPStatusCode = 599
+
+	// server.defaultContentType values.
+	ContentTypeForm = "form"
+	ContentTypeJSON = "json"
+
 	// Logging formats.
-	logFormatPlain = "plain"
-	logFormatText  = "text"
-	logFormatJSON  = "json"
+	logFormatPlain  = "plain"
+	logFormatText   = "text"
+	logFormatLogfmt = "logfmt"
+	logFormatJSON   = "json"
 
 	// Logging levels.
 	logLevelDebug   = "debug"
@@ -53,6 +82,10 @@ const (
 	logLevelFatal   = "fatal"
 	logLevelPanic   = "panic"
 
+	// Alertmanager alerts API versions.
+	alertmanagerAPIVersionV1 = "v1"
+	alertmanagerAPIVersionV2 = "v2"
+
 	// Severity canonical values.
 	severityInfo     = "info"
 	severityWarning  = "warning"
@@ -66,13 +99,18 @@ const (
 var (
 	ErrConfigFilePathEmpty          = errors.New("config file path is empty")
 	ErrConfigNil                    = errors.New("config is nil")
+	ErrConfigDirEmpty               = errors.New("config directory contains no *.yaml files")
+	ErrConfigFragmentTokenConflict  = errors.New("app token is defined in more than one config fragment")
 	ErrDurationNilNode              = errors.New("duration yaml node is nil")
 	ErrDurationExpectedScalar       = errors.New("duration yaml node must be a scalar")
-	ErrAlertmanagerURLRequired      = errors.New("alertmanager.url is required")
+	ErrAlertmanagerURLRequired      = errors.New("alertmanager.url (or alertmanager.urls) is required")
 	ErrAlertmanagerURLParse         = errors.New("alertmanager.url parse failed")
 	ErrAlertmanagerURLInvalidScheme = errors.New("alertmanager.url must use http or https scheme")
 	ErrAlertmanagerURLMissingHost   = errors.New("alertmanager.url must include host")
-	ErrAlertmanagerBasicAuthUser    = errors.New(
+	ErrAlertmanagerURLExclusive     = errors.New(
+		"alertmanager.url and alertmanager.urls are mutually exclusive",
+	)
+	ErrAlertmanagerBasicAuthUser = errors.New(
 		"alertmanager.basicAuth.username is required when basicAuth is set",
 	)
 	ErrAlertmanagerBasicAuthPass = errors.New(
@@ -81,32 +119,121 @@ var (
 	ErrAlertmanagerAuthExclusive = errors.New(
 		"alertmanager.basicAuth and alertmanager.bearerToken are mutually exclusive",
 	)
+	ErrAlertmanagerAuthFileConflict = errors.New(
+		"alertmanager credential is set both inline and via *File",
+	)
+	ErrAlertmanagerAuthFileRead    = errors.New("alertmanager credential file read failed")
 	ErrAlertmanagerTimeoutNegative = errors.New("alertmanager.timeout must be >= 0")
+	ErrAlertmanagerRetryNegative   = errors.New("alertmanager.retry values must be >= 0")
+	ErrAlertmanagerRetryStatus     = errors.New(
+		"alertmanager.retryStatusCodes and noRetryStatusCodes must be distinct HTTP status codes (100-599)",
+	)
+	ErrAlertmanagerBatchNegative   = errors.New("alertmanager.batching values must be >= 0")
+	ErrAlertmanagerAsyncNegative   = errors.New("alertmanager.async values must be >= 0")
+	ErrAlertmanagerCircuitNegative = errors.New("alertmanager.circuitBreaker values must be >= 0")
+	ErrAlertmanagerPoolNegative    = errors.New("alertmanager.transport values must be >= 0")
+	ErrAlertmanagerWaitNegative    = errors.New("alertmanager.waitForReadyTimeout must be >= 0")
+	ErrAlertmanagerTLSCertKeyPair  = errors.New(
+		"alertmanager.tlsConfig.certFile and keyFile must be set together",
+	)
+	ErrAlertmanagerTLSInsecureWithCA = errors.New(
+		"alertmanager.tlsConfig.insecureSkipVerify cannot be combined with caFile/caBundle",
+	)
+	ErrAlertmanagerConcurrencyNegative = errors.New(
+		"alertmanager.maxConcurrency and concurrencyWait must be >= 0",
+	)
+	ErrAlertmanagerAPIVersion    = errors.New("alertmanager.apiVersion must be v1 or v2")
+	ErrAlertmanagerPathInvalid   = errors.New("alertmanager.alertsPath and readyPath must be plain paths")
+	ErrAlertmanagerHeaderInvalid = errors.New(
+		"alertmanager.headers must not be empty or set Authorization/Content-Type",
+	)
 
 	ErrDefaultsSeverityMapRequired = errors.New(
 		"defaults.severityFromPriority is required and must be non-empty",
 	)
-	ErrDefaultsTTLNonPositive = errors.New("defaults.ttl must be > 0")
-	ErrPriorityNegative       = errors.New("priority must be >= 0")
-	ErrInvalidSeverity        = errors.New(
+	ErrDefaultsTTLNonPositive   = errors.New("defaults.ttl must be > 0")
+	ErrDefaultsBackdateNegative = errors.New("defaults.startsAtBackdate must be >= 0")
+	ErrDefaultsSummaryNegative  = errors.New("defaults.summaryMaxLen must be >= 0")
+	ErrDefaultsValueMaxLen      = errors.New("defaults.valueMaxLen must be >= 0")
+	ErrDefaultsAutoResolve      = errors.New("defaults.autoResolveAfter must be >= 0")
+	ErrPriorityMatchInvalid     = errors.New("defaults.priorityMatch must be floor, ceil or nearest")
+	ErrTTLFromPriorityInvalid   = errors.New("ttlFromPriority requires priorities >= 0 and durations > 0")
+	ErrPriorityNegative         = errors.New("priority must be >= 0")
+	ErrPriorityRangeInvalid     = errors.New("defaults.priorityRange requires 0 <= min <= max")
+	ErrInvalidSeverity          = errors.New(
 		"invalid severity (allowed: info, warning, critical)",
 	)
 
-	ErrAppsEmptyTokenKey   = errors.New("apps contains an empty token key")
-	ErrAppsAppNameRequired = errors.New("apps appName is required")
+	ErrTemplateInvalid     = errors.New("invalid label/annotation template")
+	ErrExtrasPolicyInvalid = errors.New(
+		"extras.allow and extras.deny are mutually exclusive and must not contain empty paths",
+	)
+
+	ErrAppsEmptyTokenKey    = errors.New("apps contains an empty token key")
+	ErrAppsAppNameRequired  = errors.New("apps appName is required")
+	ErrAppsTokenDuplicate   = errors.New("apps token is used more than once")
+	ErrAppsTokenHashInvalid = errors.New("apps hashed token is malformed")
+	ErrAppsAppIDZero        = errors.New("apps appId must be > 0")
+	ErrAppsAppIDDuplicate   = errors.New("apps appId is used by more than one app")
 
-	ErrLoggingLevelInvalid  = errors.New("logging.level is invalid")
-	ErrLoggingFormatInvalid = errors.New("logging.format is invalid (allowed: plain, text, json)")
+	ErrLoggingLevelInvalid    = errors.New("logging.level is invalid")
+	ErrLoggingFormatInvalid   = errors.New("logging.format is invalid (allowed: plain, text, logfmt, json)")
+	ErrLoggingSamplingInvalid = errors.New("logging.sampling values must be >= 0")
+	ErrLoggingRotateNegative  = errors.New("logging maxSizeMB, maxBackups and maxAgeDays must be >= 0")
 
 	ErrServerTimeoutNegative = errors.New("server timeouts must be >= 0")
+	ErrServerListenAddrUnix  = errors.New("server.listenAddr unix: form requires a socket path")
+	ErrServerMaxBodyNegative = errors.New("server.maxBodyBytes must be >= 0")
+	ErrServerMaxBatchLines   = errors.New("server.maxBatchLines must be >= 0")
+	ErrServerContentType     = errors.New("server.defaultContentType is invalid (allowed: form, json)")
+	ErrRateLimitNegative     = errors.New("rateLimit.rps and rateLimit.burst must be >= 0")
+	ErrServerTLSCertKeyPair  = errors.New(
+		"server.tls.certFile and keyFile must be set together (clientCAFile requires both)",
+	)
+	ErrServerHealthNegative = errors.New(
+		"server.health.upstreamFailureThreshold and upstreamFailureWindow must be >= 0",
+	)
+	ErrServerMetricsAuthInvalid = errors.New(
+		"server.metrics.auth accepts either bearerToken or basicAuth (with username and password)",
+	)
+	ErrServerCORSInvalid = errors.New("server.cors is invalid")
+	ErrServerIdempotency = errors.New("server.idempotency.ttl and maxEntries must be >= 0")
+	ErrServerCIDRInvalid = errors.New(
+		"server.allowedCIDRs and trustedProxies entries must be IP addresses or CIDR ranges",
+	)
 )
 
 type Config struct {
 	Server       ServerConfig         `yaml:"server"`
+	Gotify       GotifyConfig         `yaml:"gotify"`
 	Logging      LoggingConfig        `yaml:"logging"`
+	Metrics      MetricsConfig        `yaml:"metrics"`
+	Tracing      TracingConfig        `yaml:"tracing"`
 	Alertmanager AlertmanagerConfig   `yaml:"alertmanager"`
 	Defaults     DefaultsConfig       `yaml:"defaults"`
 	Apps         map[string]AppConfig `yaml:"apps"`
+
+	// positions holds key locations when loaded from disk (nil otherwise).
+	positions positions
+}
+
+// GotifyConfig tunes conformance with the Gotify message API.
+type GotifyConfig struct {
+	// MaxPriority rejects /message priorities above it with 400 (Gotify defines 0-10, so 10 is
+	// the spec-conformant value); unset accepts any priority >= 0, while an explicit 0 only
+	// accepts 0. To clamp instead of rejecting, use defaults.priorityRange with clampHigh.
+	MaxPriority *int `yaml:"maxPriority"`
+}
+
+type MetricsConfig struct {
+	// DisableRuntimeMetrics omits the go_* and process_* collectors from /metrics.
+	DisableRuntimeMetrics bool `yaml:"disableRuntimeMetrics"`
+}
+
+// TracingConfig enables OpenTelemetry tracing. Exporter, sampler and resource settings come
+// from the standard OTEL_* environment variables.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
 }
 
 type ServerConfig struct {
@@ -115,43 +242,408 @@ type ServerConfig struct {
 	WriteTimeout    Duration `yaml:"writeTimeout"`
 	IdleTimeout     Duration `yaml:"idleTimeout"`
 	ShutdownTimeout Duration `yaml:"shutdownTimeout"`
+
+	// DrainDelay keeps serving after a shutdown signal while /readyz reports not ready,
+	// giving load balancers time to stop routing traffic; 0 disables the drain phase.
+	DrainDelay Duration `yaml:"drainDelay"`
+
+	// ReadyCacheTTL reuses the last Alertmanager readiness result for this long, so frequent
+	// /readyz probes do not each hit Alertmanager; 0 checks on every probe.
+	ReadyCacheTTL Duration `yaml:"readyCacheTTL"`
+	// ReadyInterval checks Alertmanager in the background at this interval and serves the last
+	// result on /readyz (readyCacheTTL is then unused); 0 checks on demand. ReadyTimeout bounds
+	// each check (default 2s).
+	ReadyInterval Duration `yaml:"readyInterval"`
+	ReadyTimeout  Duration `yaml:"readyTimeout"`
+
+	// RoutePrefix serves every route under a base path (e.g. "/gotilert" -> "/gotilert/message").
+	RoutePrefix string `yaml:"routePrefix"`
+
+	// MaxBodyBytes caps /message request bodies; 0 means the built-in default (1 MiB).
+	MaxBodyBytes int64 `yaml:"maxBodyBytes"`
+
+	// MaxBatchLines caps the messages of an NDJSON /message batch; 0 means the built-in
+	// default (1000).
+	MaxBatchLines int `yaml:"maxBatchLines"`
+
+	// DefaultContentType is how /message bodies without a Content-Type header are parsed:
+	// "form" (default, like Gotify) or "json".
+	DefaultContentType string `yaml:"defaultContentType"`
+
+	// AdminToken enables POST /-/reload for callers presenting it; empty disables admin access.
+	AdminToken string `yaml:"adminToken"`
+
+	// RateLimit is the default per-app /message rate limit; apps may override it.
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+
+	// TLS serves the HTTP API over HTTPS when certFile and keyFile are set.
+	TLS ServerTLSConfig `yaml:"tls"`
+
+	// Pprof exposes net/http/pprof under /debug/pprof/ (also enabled by --pprof).
+	Pprof bool `yaml:"pprof"`
+
+	// TestEndpoint serves POST /-/test, which forwards a synthetic alert (labeled
+	// gotilert_test="true") for the calling app token and returns the upstream result.
+	TestEndpoint bool `yaml:"testEndpoint"`
+
+	// GotifyCompat serves GET /application and GET /current/user for Gotify clients that
+	// probe them before sending messages.
+	GotifyCompat bool `yaml:"gotifyCompat"`
+
+	// Metrics configures the /metrics endpoint.
+	Metrics ServerMetricsConfig `yaml:"metrics"`
+
+	// Health enables opt-in /healthz checks; by default /healthz is always healthy.
+	Health HealthConfig `yaml:"health"`
+
+	// AllowedCIDRs restricts /message to clients whose IP is within one of these ranges
+	// (bare IPs are accepted as single-host ranges); empty allows every client.
+	AllowedCIDRs []string `yaml:"allowedCIDRs"`
+
+	// TrustProxy takes the client IP from the last X-Forwarded-For entry instead of the
+	// connection peer. Enable it only behind a reverse proxy that sets the header.
+	TrustProxy bool `yaml:"trustProxy"`
+
+	// TrustedProxies lists reverse proxies (IPs or CIDRs) whose X-Forwarded-For is honored:
+	// the client IP is the rightmost entry that is not itself a trusted proxy.
+	TrustedProxies []string `yaml:"trustedProxies"`
+
+	// CORS enables cross-origin /message requests from browser dashboards (off by default).
+	CORS CORSConfig `yaml:"cors"`
+
+	// Idempotency replays responses to /message retries carrying the same Idempotency-Key.
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+}
+
+// IdempotencyConfig keeps successful /message responses per (token, Idempotency-Key) for TTL,
+// so client retries are not forwarded twice. Disabled when TTL is 0.
+type IdempotencyConfig struct {
+	TTL Duration `yaml:"ttl"`
+	// MaxEntries bounds the cache, evicting the oldest keys first (default 10000).
+	MaxEntries int `yaml:"maxEntries"`
+}
+
+type HealthConfig struct {
+	// UpstreamFailureThreshold reports unhealthy after this many consecutive Alertmanager
+	// failures within UpstreamFailureWindow (default 5m). 0 disables the check.
+	UpstreamFailureThreshold int      `yaml:"upstreamFailureThreshold"`
+	UpstreamFailureWindow    Duration `yaml:"upstreamFailureWindow"`
+
+	// CheckConfigFile reports unhealthy while the config file cannot be read.
+	CheckConfigFile bool `yaml:"checkConfigFile"`
+}
+
+type ServerMetricsConfig struct {
+	Auth MetricsAuthConfig `yaml:"auth"`
+}
+
+// MetricsAuthConfig protects /metrics with either a bearer token or basic auth.
+// When both are empty, /metrics is unauthenticated.
+type MetricsAuthConfig struct {
+	BearerToken string            `yaml:"bearerToken"`
+	BasicAuth   *MetricsBasicAuth `yaml:"basicAuth"`
+}
+
+type MetricsBasicAuth struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type ServerTLSConfig struct {
+	CertFile     string `yaml:"certFile"`
+	KeyFile      string `yaml:"keyFile"`
+	ClientCAFile string `yaml:"clientCAFile"`
+}
+
+// Enabled reports whether HTTPS is configured.
+func (tlsConfig *ServerTLSConfig) Enabled() bool {
+	return tlsConfig.CertFile != "" || tlsConfig.KeyFile != ""
+}
+
+// RateLimitConfig is a token-bucket limit. RPS 0 disables limiting; Burst 0 means ceil(RPS).
+type RateLimitConfig struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
 }
 
 type LoggingConfig struct {
 	Format      string `yaml:"format"`
 	Level       string `yaml:"level"`
 	IncludeTime bool   `yaml:"includeTime"`
+
+	// LogProbes keeps the /healthz and /readyz access logs at info; by default they are debug.
+	LogProbes bool `yaml:"logProbes"`
+
+	// Output is "stdout" (default), "stderr" or a file path. Files are rotated by size
+	// (MaxSizeMB, 0 -> 100) and old ones pruned by count (MaxBackups) and age (MaxAgeDays).
+	Output     string `yaml:"output"`
+	MaxSizeMB  int    `yaml:"maxSizeMB"`
+	MaxBackups int    `yaml:"maxBackups"`
+	MaxAgeDays int    `yaml:"maxAgeDays"`
+
+	Sampling LogSamplingConfig `yaml:"sampling"`
+
+	// AuditFile appends a JSON line per forwarded alert (app, priority, severity, gotilert_id,
+	// upstream result) to this file; empty disables the audit log.
+	AuditFile string `yaml:"auditFile"`
+}
+
+// LogSamplingConfig caps repeated info/debug records: per message, the first Initial records
+// each second are logged, then every Thereafter-th. Sampling is off when Initial is 0.
+type LogSamplingConfig struct {
+	Initial    int `yaml:"initial"`
+	Thereafter int `yaml:"thereafter"`
 }
 
 type AlertmanagerConfig struct {
-	URL       string     `yaml:"url"`
-	BasicAuth *BasicAuth `yaml:"basicAuth"`
-	Bearer    string     `yaml:"bearerToken"`
-	TLSConfig TLSConfig  `yaml:"tlsConfig"`
-	Timeout   Duration   `yaml:"timeout"`
+	URL        string            `yaml:"url"`
+	URLs       []string          `yaml:"urls"`
+	BasicAuth  *BasicAuth        `yaml:"basicAuth"`
+	Bearer     string            `yaml:"bearerToken"`
+	BearerFile string            `yaml:"bearerTokenFile"`
+	TLSConfig  TLSConfig         `yaml:"tlsConfig"`
+	Timeout    Duration          `yaml:"timeout"`
+	Retry      RetryConfig       `yaml:"retry"`
+	Headers    map[string]string `yaml:"headers"`
+	ProxyURL   string            `yaml:"proxyUrl"`
+	Batching   BatchingConfig    `yaml:"batching"`
+	Async      AsyncConfig       `yaml:"async"`
+
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker"`
+	Transport      TransportConfig      `yaml:"transport"`
+
+	// MaxConcurrency caps concurrent posts to Alertmanager; 0 means unlimited.
+	MaxConcurrency int `yaml:"maxConcurrency"`
+	// ConcurrencyWait bounds how long a post waits for a free slot; 0 waits up to the
+	// forward timeout.
+	ConcurrencyWait Duration `yaml:"concurrencyWait"`
+
+	// APIVersion selects the alerts API ("v1" or "v2"); empty means v2.
+	APIVersion string `yaml:"apiVersion"`
+
+	// AlertsPath and ReadyPath override the alerts (/api/<apiVersion>/alerts) and readiness
+	// (/-/ready) endpoints; they are joined to the path of each Alertmanager URL.
+	AlertsPath string `yaml:"alertsPath"`
+	ReadyPath  string `yaml:"readyPath"`
+
+	// CompressRequests gzips larger request bodies (Content-Encoding: gzip). Alertmanager
+	// does not decode them, so it needs a decompressing proxy in front.
+	CompressRequests bool `yaml:"compressRequests"`
+
+	// RetryStatusCodes adds upstream statuses to the retried set (429 + 5xx by default);
+	// NoRetryStatusCodes removes them, e.g. a 503 from an auth proxy refreshing tokens.
+	RetryStatusCodes   []int `yaml:"retryStatusCodes"`
+	NoRetryStatusCodes []int `yaml:"noRetryStatusCodes"`
+
+	// WaitForReadyOnStartup holds /readyz and /message (503) until Alertmanager is ready, for
+	// up to WaitForReadyTimeout (default 60s); /healthz is served meanwhile. On timeout
+	// gotilert starts anyway unless WaitForReadyFailOnTimeout is set.
+	WaitForReadyOnStartup     bool     `yaml:"waitForReadyOnStartup"`
+	WaitForReadyTimeout       Duration `yaml:"waitForReadyTimeout"`
+	WaitForReadyFailOnTimeout bool     `yaml:"waitForReadyFailOnTimeout"`
+}
+
+// BatchingConfig enables coalescing alerts into fewer Alertmanager POSTs.
+// Batching is disabled when Window is 0.
+type BatchingConfig struct {
+	Window  Duration `yaml:"window"`
+	MaxSize int      `yaml:"maxSize"`
+}
+
+// AsyncConfig makes /message answer 202 right after queueing the alert; a worker pool
+// posts it in the background. Forwarding is synchronous when Enabled is false.
+type AsyncConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// QueueSize bounds pending forwards; a full queue answers 429 (default 1024).
+	QueueSize int `yaml:"queueSize"`
+	// Workers is the number of background senders (default 4).
+	Workers int `yaml:"workers"`
+}
+
+// CircuitBreakerConfig makes /message fail fast while Alertmanager is down.
+// The breaker is disabled when FailureThreshold is 0.
+type CircuitBreakerConfig struct {
+	// FailureThreshold opens the circuit after this many consecutive failed posts.
+	FailureThreshold int `yaml:"failureThreshold"`
+	// Cooldown is how long the circuit stays open before a probe is let through (default 30s).
+	Cooldown Duration `yaml:"cooldown"`
+}
+
+// TransportConfig tunes the HTTP connection pool used to reach Alertmanager.
+// Zero values mean "use built-in defaults"; maxConnsPerHost=0 means unlimited.
+type TransportConfig struct {
+	MaxIdleConns        int      `yaml:"maxIdleConns"`
+	MaxIdleConnsPerHost int      `yaml:"maxIdleConnsPerHost"`
+	MaxConnsPerHost     int      `yaml:"maxConnsPerHost"`
+	IdleConnTimeout     Duration `yaml:"idleConnTimeout"`
+
+	// DisableKeepAlives dials (and re-resolves DNS) for every request. ConnMaxAge, when set,
+	// stops reusing pooled connections older than this; 0 keeps them until idle timeout.
+	DisableKeepAlives bool     `yaml:"disableKeepAlives"`
+	ConnMaxAge        Duration `yaml:"connMaxAge"`
+}
+
+// RetryConfig tunes PostAlerts retries. Zero values mean "use built-in defaults".
+type RetryConfig struct {
+	MaxAttempts    int      `yaml:"maxAttempts"`
+	InitialBackoff Duration `yaml:"initialBackoff"`
+	MaxBackoff     Duration `yaml:"maxBackoff"`
 }
 
 type TLSConfig struct {
-	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+	ClientCertFile     string `yaml:"certFile"`
+	ClientKeyFile      string `yaml:"keyFile"`
+	CACertFile         string `yaml:"caFile"`
+	CABundle           string `yaml:"caBundle"`
 }
 
 type BasicAuth struct {
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"passwordFile"`
 }
 
 type DefaultsConfig struct {
-	AlertName            string            `yaml:"alertname"`
-	TTL                  Duration          `yaml:"ttl"`
-	SeverityFromPriority map[int]string    `yaml:"severityFromPriority"`
+	AlertName string   `yaml:"alertname"`
+	TTL       Duration `yaml:"ttl"`
+
+	// TTLFromPriority overrides ttl for priorities at or above a key (closest lower key wins).
+	TTLFromPriority map[int]Duration `yaml:"ttlFromPriority"`
+
+	// StartsAtBackdate moves startsAt into the past to absorb clock skew with Alertmanager;
+	// endsAt stays now + ttl.
+	StartsAtBackdate     Duration          `yaml:"startsAtBackdate"`
+	SeverityFromPriority SeverityMap       `yaml:"severityFromPriority"`
 	Labels               map[string]string `yaml:"labels"`
+	Annotations          map[string]string `yaml:"annotations"`
+	GeneratorURL         string            `yaml:"generatorURL"`
+	PriorityRange        PriorityRange     `yaml:"priorityRange"`
+
+	// MinForwardPriority drops messages whose (clamped) priority is below it: they are
+	// answered as usual but never posted to Alertmanager. 0 forwards everything.
+	MinForwardPriority int `yaml:"minForwardPriority"`
+
+	// Rules match messages by content and drop them or adjust their labels and severity.
+	Rules []RuleConfig `yaml:"rules"`
+
+	// SigningSecret, when set, requires every /message body to be signed with it
+	// (X-Gotilert-Signature: sha256=<hex HMAC-SHA256>).
+	SigningSecret string `yaml:"signingSecret"`
+
+	// SummaryMaxLen caps, in characters, the summary annotation derived from a title-less
+	// message; longer messages are cut and end with "…". 0 means DefaultSummaryMaxLen.
+	SummaryMaxLen int `yaml:"summaryMaxLen"`
+
+	// ValueMaxLen caps, in characters, every label and annotation value (e.g. a huge
+	// description); longer values are cut and end with "…truncated". The alertname and
+	// gotilert_id labels identify the alert and are never cut. 0 means DefaultValueMaxLen.
+	ValueMaxLen int `yaml:"valueMaxLen"`
+
+	// AutoResolveAfter, when set, re-posts every forwarded alert as resolved once this much
+	// time has passed, even if its endsAt was later. A re-fired alert restarts the wait. 0 disables.
+	AutoResolveAfter Duration `yaml:"autoResolveAfter"`
+
+	// PriorityMatch selects how priorities without an exact severityFromPriority key
+	// resolve: floor (default), ceil or nearest.
+	PriorityMatch mapping.Match `yaml:"priorityMatch"`
+
+	// ForcedLabels are applied after app and computed labels, so apps cannot override them.
+	ForcedLabels map[string]string `yaml:"forcedLabels"`
+
+	// GotilertIDAsAnnotation moves gotilert_id from the labels to the annotations, so
+	// messages with otherwise equal labels group (and deduplicate) in Alertmanager.
+	GotilertIDAsAnnotation bool         `yaml:"gotilertIdAsAnnotation"`
+	Extras                 ExtrasConfig `yaml:"extras"`
+}
+
+// ExtrasConfig controls which message extras become annotations. Paths are dotted
+// (e.g. "client::notification.click.url"); allow and deny are mutually exclusive.
+// When empty, only the well-known extras are mapped.
+type ExtrasConfig struct {
+	Allow       []string `yaml:"allow"`
+	Deny        []string `yaml:"deny"`
+	Passthrough bool     `yaml:"passthrough"`
+}
+
+// PriorityRange clamps incoming priorities before severity lookup. Values below Min are
+// raised to Min; values above Max are lowered to Max only when ClampHigh is set.
+// Max defaults to DefaultMaxPriority (Gotify's conventional 0-10 scale) when unset; an
+// explicit 0 is kept.
+type PriorityRange struct {
+	Min       int  `yaml:"min"`
+	Max       *int `yaml:"max"`
+	ClampHigh bool `yaml:"clampHigh"`
+}
+
+// MaxPriority returns Max, or DefaultMaxPriority when it is unset.
+func (priorityRange PriorityRange) MaxPriority() int {
+	if priorityRange.Max == nil {
+		return DefaultMaxPriority
+	}
+
+	return *priorityRange.Max
+}
+
+// Clamp applies the range to priority.
+func (priorityRange PriorityRange) Clamp(priority int) int {
+	if priority < priorityRange.Min {
+		return priorityRange.Min
+	}
+
+	if maxPriority := priorityRange.MaxPriority(); priorityRange.ClampHigh && priority > maxPriority {
+		return maxPriority
+	}
+
+	return priority
 }
 
 type AppConfig struct {
+	// Tokens are additional tokens resolving to this app besides its key in apps,
+	// so an old and a new token both work during a rotation.
+	Tokens []string `yaml:"tokens"`
+
+	// AppID, when set, is reported as the Gotify app id instead of a hash of appName.
+	AppID *uint32 `yaml:"appId"`
+
 	AppName              string            `yaml:"appName"`
 	AlertName            string            `yaml:"alertname"`
 	Labels               map[string]string `yaml:"labels"`
-	SeverityFromPriority map[int]string    `yaml:"severityFromPriority"`
+	Annotations          map[string]string `yaml:"annotations"`
+	SeverityFromPriority SeverityMap       `yaml:"severityFromPriority"`
+	Resolve              ResolveConfig     `yaml:"resolve"`
+
+	// MinForwardPriority, when set, replaces defaults.minForwardPriority for this app.
+	MinForwardPriority *int `yaml:"minForwardPriority"`
+
+	// AlertnameFromTitle uses the message title (sanitized) as alertname, so different titles
+	// group separately; messages without a title keep alertname.
+	AlertnameFromTitle bool `yaml:"alertnameFromTitle"`
+
+	// TTLFromPriority, when set, replaces defaults.ttlFromPriority for this app.
+	TTLFromPriority map[int]Duration `yaml:"ttlFromPriority"`
+
+	// GeneratorURL, when set, replaces defaults.generatorURL for this app.
+	GeneratorURL string `yaml:"generatorURL"`
+
+	// GotilertIDAsAnnotation, when set, replaces defaults.gotilertIdAsAnnotation for this app.
+	GotilertIDAsAnnotation *bool `yaml:"gotilertIdAsAnnotation"`
+
+	// Extras, when set, replaces defaults.extras for this app.
+	Extras *ExtrasConfig `yaml:"extras"`
+
+	// RateLimit, when set, replaces server.rateLimit for this app.
+	RateLimit *RateLimitConfig `yaml:"rateLimit"`
+
+	// SigningSecret, when set, replaces defaults.signingSecret for this app.
+	SigningSecret string `yaml:"signingSecret"`
+}
+
+// ResolveConfig selects which messages resolve an app's firing alerts instead of firing new ones.
+// Both triggers are optional; resolution is disabled when neither is set.
+type ResolveConfig struct {
+	Priority  *int   `yaml:"priority"`
+	ExtrasKey string `yaml:"extrasKey"`
 }
 
 type Duration struct {
@@ -177,24 +669,26 @@ func (duration *Duration) UnmarshalYAML(node *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML writes durations in the same "5m0s" form UnmarshalYAML accepts.
+func (duration Duration) MarshalYAML() (any, error) {
+	return duration.String(), nil
+}
+
 // LoadFile loads, validates, and returns configuration from a YAML file.
 func LoadFile(path string) (*Config, error) {
 	if strings.TrimSpace(path) == "" {
 		return nil, ErrConfigFilePathEmpty
 	}
 
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read config file %q: %w", path, err)
-	}
-
 	var cfg Config
 
-	err = yaml.Unmarshal(data, &cfg)
+	lines, err := decodeFile(path, &cfg)
 	if err != nil {
-		return nil, fmt.Errorf("parse config file %q: %w", path, err)
+		return nil, err
 	}
 
+	cfg.positions = lines
+
 	err = cfg.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("validate config file %q: %w", path, err)
@@ -203,6 +697,34 @@ func LoadFile(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// decodeFile strictly decodes the YAML file at path into out and returns its key positions.
+func decodeFile(path string, out any) (positions, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // the operator-provided config path.
+	if err != nil {
+		return nil, fmt.Errorf("read config file %q: %w", path, err)
+	}
+
+	// Reject unknown fields so a typo (e.g. "severtiyFromPriority") fails with its line
+	// number instead of silently leaving the setting empty. Map keys (app tokens, labels)
+	// are data, not fields, and are unaffected.
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(out)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %q: %w", path, err)
+	}
+
+	var root yaml.Node
+
+	err = yaml.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %q: %w", path, err)
+	}
+
+	return newPositions(&root), nil
+}
+
 func (cfg *Config) Validate() error {
 	if cfg == nil {
 		return ErrConfigNil
@@ -213,6 +735,15 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if cfg.Gotify.MaxPriority != nil && *cfg.Gotify.MaxPriority < 0 {
+		return fmt.Errorf(
+			"gotify.maxPriority%s: %w: %d",
+			cfg.positions.at("gotify", "maxPriority"),
+			ErrPriorityNegative,
+			*cfg.Gotify.MaxPriority,
+		)
+	}
+
 	err = cfg.validateLogging()
 	if err != nil {
 		return err
@@ -237,6 +768,10 @@ func (cfg *Config) Validate() error {
 }
 
 func (cfg *Config) validateServer() error {
+	if socketPath, ok := strings.CutPrefix(cfg.Server.ListenAddr, "unix:"); ok && strings.TrimSpace(socketPath) == "" {
+		return ErrServerListenAddrUnix
+	}
+
 	if cfg.Server.ReadTimeout.Duration < 0 {
 		return ErrServerTimeoutNegative
 	}
@@ -253,6 +788,93 @@ func (cfg *Config) validateServer() error {
 		return ErrServerTimeoutNegative
 	}
 
+	if cfg.Server.DrainDelay.Duration < 0 {
+		return ErrServerTimeoutNegative
+	}
+
+	if cfg.Server.ReadyCacheTTL.Duration < 0 || cfg.Server.ReadyInterval.Duration < 0 || cfg.Server.ReadyTimeout.Duration < 0 {
+		return ErrServerTimeoutNegative
+	}
+
+	if cfg.Server.MaxBodyBytes < 0 {
+		return ErrServerMaxBodyNegative
+	}
+
+	if cfg.Server.MaxBatchLines < 0 {
+		return ErrServerMaxBatchLines
+	}
+
+	cfg.Server.DefaultContentType = strings.ToLower(strings.TrimSpace(cfg.Server.DefaultContentType))
+	if cfg.Server.DefaultContentType == "" {
+		cfg.Server.DefaultContentType = ContentTypeForm
+	}
+
+	if cfg.Server.DefaultContentType != ContentTypeForm && cfg.Server.DefaultContentType != ContentTypeJSON {
+		return fmt.Errorf("%w%s", ErrServerContentType, cfg.positions.at("server", "defaultContentType"))
+	}
+
+	if !cfg.Server.RateLimit.valid() {
+		return fmt.Errorf("server: %w", ErrRateLimitNegative)
+	}
+
+	serverTLS := &cfg.Server.TLS
+	serverTLS.CertFile = strings.TrimSpace(serverTLS.CertFile)
+	serverTLS.KeyFile = strings.TrimSpace(serverTLS.KeyFile)
+	serverTLS.ClientCAFile = strings.TrimSpace(serverTLS.ClientCAFile)
+
+	if (serverTLS.CertFile == "") != (serverTLS.KeyFile == "") ||
+		(serverTLS.ClientCAFile != "" && !serverTLS.Enabled()) {
+		return ErrServerTLSCertKeyPair
+	}
+
+	if cfg.Server.Health.UpstreamFailureThreshold < 0 || cfg.Server.Health.UpstreamFailureWindow.Duration < 0 {
+		return ErrServerHealthNegative
+	}
+
+	if err := cfg.validatePrefixes(cfg.Server.AllowedCIDRs, "allowedCIDRs"); err != nil {
+		return err
+	}
+
+	if err := cfg.validatePrefixes(cfg.Server.TrustedProxies, "trustedProxies"); err != nil {
+		return err
+	}
+
+	if err := cfg.Server.CORS.validate(); err != nil {
+		return fmt.Errorf("%w%s", err, cfg.positions.at("server", "cors"))
+	}
+
+	if cfg.Server.Idempotency.TTL.Duration < 0 || cfg.Server.Idempotency.MaxEntries < 0 {
+		return fmt.Errorf("%w%s", ErrServerIdempotency, cfg.positions.at("server", "idempotency"))
+	}
+
+	return cfg.Server.Metrics.Auth.validate()
+}
+
+// validatePrefixes trims the server.<key> entries in place and checks they parse.
+func (cfg *Config) validatePrefixes(values []string, key string) error {
+	for index, value := range values {
+		values[index] = strings.TrimSpace(value)
+
+		if _, err := parsePrefix(values[index]); err != nil {
+			return fmt.Errorf("%w%s", err, cfg.positions.at("server", key, strconv.Itoa(index)))
+		}
+	}
+
+	return nil
+}
+
+func (auth *MetricsAuthConfig) validate() error {
+	auth.BearerToken = strings.TrimSpace(auth.BearerToken)
+
+	if auth.BasicAuth == nil {
+		return nil
+	}
+
+	if auth.BearerToken != "" ||
+		strings.TrimSpace(auth.BasicAuth.Username) == "" || auth.BasicAuth.Password == "" {
+		return ErrServerMetricsAuthInvalid
+	}
+
 	return nil
 }
 
@@ -261,7 +883,7 @@ func (cfg *Config) validateLogging() error {
 	format := strings.TrimSpace(cfg.Logging.Format)
 	if format != "" {
 		switch strings.ToLower(format) {
-		case logFormatPlain, logFormatText, logFormatJSON:
+		case logFormatPlain, logFormatText, logFormatLogfmt, logFormatJSON:
 			// ok
 		default:
 			return fmt.Errorf("%w: %q", ErrLoggingFormatInvalid, cfg.Logging.Format)
@@ -284,25 +906,42 @@ func (cfg *Config) validateLogging() error {
 		}
 	}
 
+	sampling := cfg.Logging.Sampling
+	if sampling.Initial < 0 || sampling.Thereafter < 0 {
+		return fmt.Errorf(
+			"%w: initial=%d thereafter=%d%s",
+			ErrLoggingSamplingInvalid,
+			sampling.Initial,
+			sampling.Thereafter,
+			cfg.positions.at("logging", "sampling"),
+		)
+	}
+
+	cfg.Logging.Output = strings.TrimSpace(cfg.Logging.Output)
+
+	if cfg.Logging.MaxSizeMB < 0 || cfg.Logging.MaxBackups < 0 || cfg.Logging.MaxAgeDays < 0 {
+		return fmt.Errorf(
+			"%w: maxSizeMB=%d maxBackups=%d maxAgeDays=%d%s",
+			ErrLoggingRotateNegative,
+			cfg.Logging.MaxSizeMB,
+			cfg.Logging.MaxBackups,
+			cfg.Logging.MaxAgeDays,
+			cfg.positions.at("logging"),
+		)
+	}
+
 	return nil
 }
 
 func (cfg *Config) validateAlertmanager() error {
-	if strings.TrimSpace(cfg.Alertmanager.URL) == "" {
-		return ErrAlertmanagerURLRequired
-	}
-
-	parsed, err := url.Parse(cfg.Alertmanager.URL)
+	err := cfg.validateAlertmanagerURLs()
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrAlertmanagerURLParse, err)
-	}
-
-	if parsed.Scheme != "http" && parsed.Scheme != "https" {
-		return fmt.Errorf("%w: %q", ErrAlertmanagerURLInvalidScheme, parsed.Scheme)
+		return err
 	}
 
-	if strings.TrimSpace(parsed.Host) == "" {
-		return ErrAlertmanagerURLMissingHost
+	err = cfg.resolveAlertmanagerCredentialFiles()
+	if err != nil {
+		return err
 	}
 
 	// Auth is optional (may be absent entirely).
@@ -324,50 +963,403 @@ func (cfg *Config) validateAlertmanager() error {
 		return ErrAlertmanagerTimeoutNegative
 	}
 
-	return nil
-}
+	err = cfg.validateAlertmanagerAPIVersion()
+	if err != nil {
+		return err
+	}
 
-func (cfg *Config) validateDefaults() error {
-	if len(cfg.Defaults.SeverityFromPriority) == 0 {
-		return ErrDefaultsSeverityMapRequired
+	for _, endpoint := range []*string{&cfg.Alertmanager.AlertsPath, &cfg.Alertmanager.ReadyPath} {
+		*endpoint = strings.TrimSpace(*endpoint)
+		if strings.ContainsAny(*endpoint, "?# ") {
+			return fmt.Errorf("%w: %q", ErrAlertmanagerPathInvalid, *endpoint)
+		}
 	}
 
-	if strings.TrimSpace(cfg.Defaults.AlertName) == "" {
-		cfg.Defaults.AlertName = DefaultAlertName
+	err = cfg.validateAlertmanagerHeaders()
+	if err != nil {
+		return err
 	}
 
-	for priority, severity := range cfg.Defaults.SeverityFromPriority {
-		if priority < 0 {
-			return fmt.Errorf(
-				"defaults.severityFromPriority: %w: %d",
-				ErrPriorityNegative,
-				priority,
-			)
-		}
+	err = cfg.validateAlertmanagerTLS()
+	if err != nil {
+		return err
+	}
 
-		err := validateSeverity(severity)
-		if err != nil {
-			return fmt.Errorf("defaults.severityFromPriority[%d]: %w", priority, err)
-		}
+	err = cfg.validateAlertmanagerRetry()
+	if err != nil {
+		return err
+	}
 
-		cfg.Defaults.SeverityFromPriority[priority] = canonicalSeverity(severity)
+	if cfg.Alertmanager.WaitForReadyTimeout.Duration < 0 {
+		return ErrAlertmanagerWaitNegative
 	}
 
-	if cfg.Defaults.TTL.Duration <= 0 {
-		return ErrDefaultsTTLNonPositive
+	transport := cfg.Alertmanager.Transport
+	if transport.MaxIdleConns < 0 || transport.MaxIdleConnsPerHost < 0 || transport.MaxConnsPerHost < 0 ||
+		transport.IdleConnTimeout.Duration < 0 || transport.ConnMaxAge.Duration < 0 {
+		return ErrAlertmanagerPoolNegative
+	}
+
+	batching := cfg.Alertmanager.Batching
+	if batching.Window.Duration < 0 || batching.MaxSize < 0 {
+		return ErrAlertmanagerBatchNegative
+	}
+
+	async := cfg.Alertmanager.Async
+	if async.QueueSize < 0 || async.Workers < 0 {
+		return fmt.Errorf("%w: queueSize=%d workers=%d", ErrAlertmanagerAsyncNegative, async.QueueSize, async.Workers)
+	}
+
+	if cfg.Alertmanager.MaxConcurrency < 0 || cfg.Alertmanager.ConcurrencyWait.Duration < 0 {
+		return fmt.Errorf(
+			"%w: maxConcurrency=%d concurrencyWait=%s",
+			ErrAlertmanagerConcurrencyNegative,
+			cfg.Alertmanager.MaxConcurrency,
+			cfg.Alertmanager.ConcurrencyWait,
+		)
+	}
+
+	breaker := cfg.Alertmanager.CircuitBreaker
+	if breaker.FailureThreshold < 0 || breaker.Cooldown.Duration < 0 {
+		return fmt.Errorf(
+			"%w: failureThreshold=%d cooldown=%s",
+			ErrAlertmanagerCircuitNegative,
+			breaker.FailureThreshold,
+			breaker.Cooldown,
+		)
 	}
 
 	return nil
 }
 
-func (cfg *Config) validateApps() error {
-	for token, app := range cfg.Apps {
-		if strings.TrimSpace(token) == "" {
+func (cfg *Config) validateAlertmanagerTLS() error {
+	tlsConfig := cfg.Alertmanager.TLSConfig
+
+	if (strings.TrimSpace(tlsConfig.ClientCertFile) == "") != (strings.TrimSpace(tlsConfig.ClientKeyFile) == "") {
+		return ErrAlertmanagerTLSCertKeyPair
+	}
+
+	hasCA := strings.TrimSpace(tlsConfig.CACertFile) != "" || strings.TrimSpace(tlsConfig.CABundle) != ""
+	if hasCA && tlsConfig.InsecureSkipVerify {
+		return ErrAlertmanagerTLSInsecureWithCA
+	}
+
+	return nil
+}
+
+// validateAlertmanagerAPIVersion normalizes apiVersion, defaulting to v2.
+func (cfg *Config) validateAlertmanagerAPIVersion() error {
+	amConfig := &cfg.Alertmanager
+	amConfig.APIVersion = strings.ToLower(strings.TrimSpace(amConfig.APIVersion))
+
+	switch amConfig.APIVersion {
+	case "":
+		amConfig.APIVersion = alertmanagerAPIVersionV2
+	case alertmanagerAPIVersionV1, alertmanagerAPIVersionV2:
+		// ok
+	default:
+		return fmt.Errorf("%w: %q", ErrAlertmanagerAPIVersion, amConfig.APIVersion)
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateAlertmanagerHeaders() error {
+	for name := range cfg.Alertmanager.Headers {
+		trimmed := strings.TrimSpace(name)
+
+		switch strings.ToLower(trimmed) {
+		case "", "authorization", "content-type":
+			return fmt.Errorf("%w: %q", ErrAlertmanagerHeaderInvalid, name)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) validateAlertmanagerURLs() error {
+	hasURL := strings.TrimSpace(cfg.Alertmanager.URL) != ""
+
+	if hasURL && len(cfg.Alertmanager.URLs) > 0 {
+		return ErrAlertmanagerURLExclusive
+	}
+
+	if !hasURL && len(cfg.Alertmanager.URLs) == 0 {
+		return ErrAlertmanagerURLRequired
+	}
+
+	for index, rawURL := range cfg.Alertmanager.PeerURLs() {
+		err := validateAlertmanagerURL(rawURL)
+		if err != nil {
+			if hasURL {
+				return err
+			}
+
+			return fmt.Errorf("alertmanager.urls[%d]: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+func validateAlertmanagerURL(rawURL string) error {
+	if strings.TrimSpace(rawURL) == "" {
+		return ErrAlertmanagerURLRequired
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAlertmanagerURLParse, err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrAlertmanagerURLInvalidScheme, parsed.Scheme)
+	}
+
+	if strings.TrimSpace(parsed.Host) == "" {
+		return ErrAlertmanagerURLMissingHost
+	}
+
+	return nil
+}
+
+// PeerURLs returns the configured Alertmanager peers: the single `url` form or the `urls` list.
+func (amConfig *AlertmanagerConfig) PeerURLs() []string {
+	if strings.TrimSpace(amConfig.URL) != "" {
+		return []string{amConfig.URL}
+	}
+
+	return amConfig.URLs
+}
+
+func (cfg *Config) validateAlertmanagerRetry() error {
+	retry := cfg.Alertmanager.Retry
+
+	if retry.MaxAttempts < 0 {
+		return fmt.Errorf("%w: maxAttempts=%d", ErrAlertmanagerRetryNegative, retry.MaxAttempts)
+	}
+
+	if retry.InitialBackoff.Duration < 0 {
+		return fmt.Errorf("%w: initialBackoff=%s", ErrAlertmanagerRetryNegative, retry.InitialBackoff)
+	}
+
+	if retry.MaxBackoff.Duration < 0 {
+		return fmt.Errorf("%w: maxBackoff=%s", ErrAlertmanagerRetryNegative, retry.MaxBackoff)
+	}
+
+	return cfg.validateAlertmanagerRetryStatus()
+}
+
+func (cfg *Config) validateAlertmanagerRetryStatus() error {
+	retryable := make(map[int]bool, len(cfg.Alertmanager.RetryStatusCodes))
+
+	for _, code := range cfg.Alertmanager.RetryStatusCodes {
+		if !isHTTPStatusCode(code) {
+			return fmt.Errorf("%w: retryStatusCodes contains %d", ErrAlertmanagerRetryStatus, code)
+		}
+
+		retryable[code] = true
+	}
+
+	for _, code := range cfg.Alertmanager.NoRetryStatusCodes {
+		if !isHTTPStatusCode(code) || retryable[code] {
+			return fmt.Errorf("%w: noRetryStatusCodes contains %d", ErrAlertmanagerRetryStatus, code)
+		}
+	}
+
+	return nil
+}
+
+func isHTTPStatusCode(code int) bool {
+	return code >= http.StatusContinue && code <= maxHTTPStatusCode
+}
+
+// resolveAlertmanagerCredentialFiles reads *File credentials and stores them inline,
+// so the client only ever sees resolved values.
+func (cfg *Config) resolveAlertmanagerCredentialFiles() error {
+	if cfg.Alertmanager.BasicAuth != nil {
+		basicAuth := cfg.Alertmanager.BasicAuth
+
+		password, err := resolveCredential(
+			basicAuth.Password,
+			basicAuth.PasswordFile,
+			"alertmanager.basicAuth.passwordFile",
+		)
+		if err != nil {
+			return err
+		}
+
+		basicAuth.Password = password
+	}
+
+	bearer, err := resolveCredential(
+		cfg.Alertmanager.Bearer,
+		cfg.Alertmanager.BearerFile,
+		"alertmanager.bearerTokenFile",
+	)
+	if err != nil {
+		return err
+	}
+
+	cfg.Alertmanager.Bearer = bearer
+
+	return nil
+}
+
+func resolveCredential(inline, path, field string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return inline, nil
+	}
+
+	if strings.TrimSpace(inline) != "" {
+		return "", fmt.Errorf("%w: %s", ErrAlertmanagerAuthFileConflict, field)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrAlertmanagerAuthFileRead, field, err)
+	}
+
+	// Secret files commonly end with a newline (echo, kubectl create secret --from-file).
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func (cfg *Config) validateDefaults() error {
+	if len(cfg.Defaults.SeverityFromPriority) == 0 {
+		return ErrDefaultsSeverityMapRequired
+	}
+
+	if strings.TrimSpace(cfg.Defaults.AlertName) == "" {
+		cfg.Defaults.AlertName = DefaultAlertName
+	}
+
+	for priority, severity := range cfg.Defaults.SeverityFromPriority {
+		line := cfg.positions.at("defaults", "severityFromPriority", strconv.Itoa(priority))
+
+		if priority < 0 {
+			return fmt.Errorf(
+				"defaults.severityFromPriority%s: %w: %d",
+				line,
+				ErrPriorityNegative,
+				priority,
+			)
+		}
+
+		err := validateSeverity(severity)
+		if err != nil {
+			return fmt.Errorf("defaults.severityFromPriority[%d]%s: %w", priority, line, err)
+		}
+
+		cfg.Defaults.SeverityFromPriority[priority] = canonicalSeverity(severity)
+	}
+
+	if cfg.Defaults.TTL.Duration <= 0 {
+		return ErrDefaultsTTLNonPositive
+	}
+
+	if cfg.Defaults.StartsAtBackdate.Duration < 0 {
+		return fmt.Errorf("%w: %s", ErrDefaultsBackdateNegative, cfg.Defaults.StartsAtBackdate)
+	}
+
+	switch {
+	case cfg.Defaults.SummaryMaxLen < 0:
+		return fmt.Errorf(
+			"%w: %d%s",
+			ErrDefaultsSummaryNegative,
+			cfg.Defaults.SummaryMaxLen,
+			cfg.positions.at("defaults", "summaryMaxLen"),
+		)
+	case cfg.Defaults.SummaryMaxLen == 0:
+		cfg.Defaults.SummaryMaxLen = DefaultSummaryMaxLen
+	}
+
+	switch {
+	case cfg.Defaults.ValueMaxLen < 0:
+		return fmt.Errorf(
+			"%w: %d%s",
+			ErrDefaultsValueMaxLen,
+			cfg.Defaults.ValueMaxLen,
+			cfg.positions.at("defaults", "valueMaxLen"),
+		)
+	case cfg.Defaults.ValueMaxLen == 0:
+		cfg.Defaults.ValueMaxLen = DefaultValueMaxLen
+	}
+
+	if cfg.Defaults.AutoResolveAfter.Duration < 0 {
+		return fmt.Errorf(
+			"%w: %s%s",
+			ErrDefaultsAutoResolve,
+			cfg.Defaults.AutoResolveAfter,
+			cfg.positions.at("defaults", "autoResolveAfter"),
+		)
+	}
+
+	err := validateTTLMap(cfg.Defaults.TTLFromPriority)
+	if err != nil {
+		return fmt.Errorf("defaults.ttlFromPriority%s: %w", cfg.positions.at("defaults", "ttlFromPriority"), err)
+	}
+
+	err = cfg.validatePriorityRange()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Defaults.MinForwardPriority < 0 {
+		return fmt.Errorf(
+			"defaults.minForwardPriority%s: %w: %d",
+			cfg.positions.at("defaults", "minForwardPriority"),
+			ErrPriorityNegative,
+			cfg.Defaults.MinForwardPriority,
+		)
+	}
+
+	err = cfg.validatePriorityMatch()
+	if err != nil {
+		return err
+	}
+
+	err = validateExtras(&cfg.Defaults.Extras)
+	if err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	err = cfg.validateRules()
+	if err != nil {
+		return err
+	}
+
+	err = validateTemplateMap("defaults.forcedLabels", cfg.Defaults.ForcedLabels, cfg.lineOf("defaults", "forcedLabels"))
+	if err != nil {
+		return err
+	}
+
+	return cfg.validateTemplates(
+		"defaults",
+		[]string{"defaults"},
+		cfg.Defaults.Labels,
+		cfg.Defaults.Annotations,
+		cfg.Defaults.GeneratorURL,
+	)
+}
+
+func (cfg *Config) validateApps() error {
+	err := cfg.validateAppTokens()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.validateAppIDs()
+	if err != nil {
+		return err
+	}
+
+	for token, app := range cfg.Apps {
+		if strings.TrimSpace(token) == "" {
 			return ErrAppsEmptyTokenKey
 		}
 
 		if strings.TrimSpace(app.AppName) == "" {
-			return fmt.Errorf("%w: %s", ErrAppsAppNameRequired, tokenKeyForError(token))
+			return fmt.Errorf("%w: %s%s", ErrAppsAppNameRequired, RedactToken(token), cfg.positions.at("apps", token))
 		}
 
 		if strings.TrimSpace(app.AlertName) == "" {
@@ -375,21 +1367,279 @@ func (cfg *Config) validateApps() error {
 			app.AlertName = ""
 		}
 
-		err := normalizeSeverityMap(app.SeverityFromPriority, "apps", tokenKeyForError(token))
+		err := normalizeSeverityMap(
+			app.SeverityFromPriority,
+			"apps",
+			RedactToken(token),
+			cfg.lineOf("apps", token, "severityFromPriority"),
+		)
+		if err != nil {
+			return err
+		}
+
+		err = validateTTLMap(app.TTLFromPriority)
+		if err != nil {
+			return fmt.Errorf(
+				"apps[%s].ttlFromPriority%s: %w",
+				RedactToken(token),
+				cfg.positions.at("apps", token, "ttlFromPriority"),
+				err,
+			)
+		}
+
+		err = cfg.validateTemplates(
+			"apps["+RedactToken(token)+"]",
+			[]string{"apps", token},
+			app.Labels,
+			app.Annotations,
+			app.GeneratorURL,
+		)
 		if err != nil {
 			return err
 		}
 
+		if app.RateLimit != nil && !app.RateLimit.valid() {
+			return fmt.Errorf("apps[%s]: %w", RedactToken(token), ErrRateLimitNegative)
+		}
+
+		if app.Extras != nil {
+			err = validateExtras(app.Extras)
+			if err != nil {
+				return fmt.Errorf("apps[%s]: %w", RedactToken(token), err)
+			}
+		}
+
+		if app.Resolve.Priority != nil && *app.Resolve.Priority < 0 {
+			return fmt.Errorf(
+				"apps[%s].resolve.priority: %w: %d",
+				RedactToken(token),
+				ErrPriorityNegative,
+				*app.Resolve.Priority,
+			)
+		}
+
+		if app.MinForwardPriority != nil && *app.MinForwardPriority < 0 {
+			return fmt.Errorf(
+				"apps[%s].minForwardPriority%s: %w: %d",
+				RedactToken(token),
+				cfg.positions.at("apps", token, "minForwardPriority"),
+				ErrPriorityNegative,
+				*app.MinForwardPriority,
+			)
+		}
+
 		cfg.Apps[token] = app
 	}
 
 	return nil
 }
 
+// validateAppIDs rejects explicit app ids that are zero or shared by two apps.
+func (cfg *Config) validateAppIDs() error {
+	owners := make(map[uint32]string, len(cfg.Apps))
+
+	for _, token := range slices.Sorted(maps.Keys(cfg.Apps)) {
+		appID := cfg.Apps[token].AppID
+		if appID == nil {
+			continue
+		}
+
+		line := cfg.positions.at("apps", token, "appId")
+
+		if *appID == 0 {
+			return fmt.Errorf("apps[%s]%s: %w", RedactToken(token), line, ErrAppsAppIDZero)
+		}
+
+		if owner, ok := owners[*appID]; ok {
+			return fmt.Errorf(
+				"apps[%s]%s: %w: %d (also apps[%s])",
+				RedactToken(token),
+				line,
+				ErrAppsAppIDDuplicate,
+				*appID,
+				RedactToken(owner),
+			)
+		}
+
+		owners[*appID] = token
+	}
+
+	return nil
+}
+
+// validateAppTokens trims apps[*].tokens and rejects empty or malformed tokens and tokens
+// that resolve to more than one app (or repeat within one). Plaintext and hashed forms of
+// the same token count as duplicates.
+func (cfg *Config) validateAppTokens() error {
+	seen := make(map[string]struct{}, len(cfg.Apps))
+	keys := slices.Sorted(maps.Keys(cfg.Apps))
+
+	for _, key := range keys {
+		if key == CatchAllToken {
+			continue
+		}
+
+		digest, err := TokenDigest(key)
+		if err != nil {
+			return fmt.Errorf("apps[%s]: %w", RedactToken(key), err)
+		}
+
+		if _, ok := seen[digest]; ok {
+			return fmt.Errorf("apps[%s]: %w", RedactToken(key), ErrAppsTokenDuplicate)
+		}
+
+		seen[digest] = struct{}{}
+	}
+
+	for _, key := range keys {
+		app := cfg.Apps[key]
+
+		for index, token := range app.Tokens {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				return fmt.Errorf("apps[%s].tokens: %w", RedactToken(key), ErrAppsEmptyTokenKey)
+			}
+
+			digest, err := TokenDigest(token)
+			if err != nil {
+				return fmt.Errorf("apps[%s].tokens: %w", RedactToken(key), err)
+			}
+
+			if _, ok := seen[digest]; ok {
+				return fmt.Errorf("apps[%s].tokens: %w: %s", RedactToken(key), ErrAppsTokenDuplicate, RedactToken(token))
+			}
+
+			seen[digest] = struct{}{}
+			app.Tokens[index] = token
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) validatePriorityRange() error {
+	priorityRange := &cfg.Defaults.PriorityRange
+
+	if priorityRange.Max == nil {
+		maxPriority := DefaultMaxPriority
+		priorityRange.Max = &maxPriority
+	}
+
+	if priorityRange.Min < 0 || priorityRange.Min > *priorityRange.Max {
+		return fmt.Errorf(
+			"%w: min=%d max=%d%s",
+			ErrPriorityRangeInvalid,
+			priorityRange.Min,
+			*priorityRange.Max,
+			cfg.positions.at("defaults", "priorityRange"),
+		)
+	}
+
+	return nil
+}
+
+func (cfg *Config) validatePriorityMatch() error {
+	match := mapping.Match(strings.ToLower(strings.TrimSpace(string(cfg.Defaults.PriorityMatch))))
+	if match == "" {
+		match = mapping.MatchFloor
+	}
+
+	if !slices.Contains(mapping.Matches(), match) {
+		return fmt.Errorf("%w: %q", ErrPriorityMatchInvalid, cfg.Defaults.PriorityMatch)
+	}
+
+	cfg.Defaults.PriorityMatch = match
+
+	return nil
+}
+
+func (rateLimit *RateLimitConfig) valid() bool {
+	return rateLimit.RPS >= 0 && rateLimit.Burst >= 0
+}
+
+func validateExtras(extras *ExtrasConfig) error {
+	if len(extras.Allow) > 0 && len(extras.Deny) > 0 {
+		return ErrExtrasPolicyInvalid
+	}
+
+	for _, paths := range [][]string{extras.Allow, extras.Deny} {
+		for index, path := range paths {
+			paths[index] = strings.TrimSpace(path)
+			if paths[index] == "" {
+				return ErrExtrasPolicyInvalid
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTemplates parses label/annotation/generatorURL values so template syntax errors
+// fail at load time. path locates the section in the file for error line numbers.
+func (cfg *Config) validateTemplates(
+	section string,
+	path []string,
+	labels, annotations map[string]string,
+	generatorURL string,
+) error {
+	err := validateTemplateMap(section+".labels", labels, cfg.lineOf(append(slices.Clone(path), "labels")...))
+	if err != nil {
+		return err
+	}
+
+	err = validateTemplateMap(section+".annotations", annotations, cfg.lineOf(append(slices.Clone(path), "annotations")...))
+	if err != nil {
+		return err
+	}
+
+	_, err = templating.Compile(map[string]string{"generatorURL": generatorURL})
+	if err != nil {
+		return fmt.Errorf(
+			"%s%s: %w: %w",
+			section,
+			cfg.positions.at(append(slices.Clone(path), "generatorURL")...),
+			ErrTemplateInvalid,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// validateTemplateMap compiles each value on its own so the error names the offending key.
+func validateTemplateMap(section string, values map[string]string, line func(key string) string) error {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		_, err := templating.Compile(map[string]string{key: values[key]})
+		if err != nil {
+			return fmt.Errorf("%s[%s]%s: %w: %w", section, key, line(key), ErrTemplateInvalid, err)
+		}
+	}
+
+	return nil
+}
+
+// lineOf returns a func giving the " line=N" suffix of a key below path.
+func (cfg *Config) lineOf(path ...string) func(key string) string {
+	return func(key string) string {
+		return cfg.positions.at(append(slices.Clone(path), key)...)
+	}
+}
+
+func validateTTLMap(mapping map[int]Duration) error {
+	for priority, ttl := range mapping {
+		if priority < 0 || ttl.Duration <= 0 {
+			return fmt.Errorf("%w: %d: %s", ErrTTLFromPriorityInvalid, priority, ttl)
+		}
+	}
+
+	return nil
+}
+
 func normalizeSeverityMap(
 	mapping map[int]string,
 	section string,
 	tokenRedaction string,
+	line func(key string) string,
 ) error {
 	if len(mapping) == 0 {
 		return nil
@@ -398,9 +1648,10 @@ func normalizeSeverityMap(
 	for prio, sev := range mapping {
 		if prio < 0 {
 			return fmt.Errorf(
-				"%s[%s].severityFromPriority: %w: %d",
+				"%s[%s].severityFromPriority%s: %w: %d",
 				section,
 				tokenRedaction,
+				line(strconv.Itoa(prio)),
 				ErrPriorityNegative,
 				prio,
 			)
@@ -409,10 +1660,11 @@ func normalizeSeverityMap(
 		err := validateSeverity(sev)
 		if err != nil {
 			return fmt.Errorf(
-				"%s[%s].severityFromPriority[%d]: %w",
+				"%s[%s].severityFromPriority[%d]%s: %w",
 				section,
 				tokenRedaction,
 				prio,
+				line(strconv.Itoa(prio)),
 				err,
 			)
 		}
@@ -444,8 +1696,3 @@ func validateSeverity(input string) error {
 		return fmt.Errorf("%w: %q", ErrInvalidSeverity, input)
 	}
 }
-
-func tokenKeyForError(token string) string {
-	// Don’t echo secrets (tokens) in errors. Use a stable redaction.
-	return fmt.Sprintf("token(len=%d)", len(token))
-}
@@ -26,10 +26,15 @@ package config_test
 
 import (
 	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/leinardi/gotilert/internal/config"
+	"github.com/leinardi/gotilert/internal/mapping"
 )
 
 func TestValidateDefaultsSeverityMapRequired(t *testing.T) {
@@ -125,6 +130,740 @@ func TestValidateDefaultsTTLMustBePositive(t *testing.T) {
 	}
 }
 
+func TestValidateAlertmanagerBearerTokenFile(t *testing.T) {
+	t.Parallel()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+
+	err := os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0o600)
+	if err != nil {
+		t.Fatalf("write token file: %v", err)
+	}
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.BearerFile = tokenFile
+
+	err = cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if cfg.Alertmanager.Bearer != "s3cr3t" {
+		t.Fatalf("expected bearer %q, got %q", "s3cr3t", cfg.Alertmanager.Bearer)
+	}
+}
+
+func TestValidateAlertmanagerPasswordFileConflict(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.BasicAuth = &config.BasicAuth{
+		Username:     "user",
+		Password:     "inline",
+		PasswordFile: "/does/not/matter",
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerAuthFileConflict) {
+		t.Fatalf("expected ErrAlertmanagerAuthFileConflict, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerPasswordFileUnreadable(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.BasicAuth = &config.BasicAuth{
+		Username:     "user",
+		PasswordFile: filepath.Join(t.TempDir(), "missing"),
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerAuthFileRead) {
+		t.Fatalf("expected ErrAlertmanagerAuthFileRead, got: %v", err)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected wrapped os.ErrNotExist, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerRetryNegative(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.Retry.MaxAttempts = -1
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerRetryNegative) {
+		t.Fatalf("expected ErrAlertmanagerRetryNegative, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerRetryStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	for _, codes := range [][2][]int{
+		{{99}, nil},
+		{nil, {600}},
+		{{503}, {503}},
+	} {
+		cfg := minimalValidConfig()
+		cfg.Alertmanager.RetryStatusCodes = codes[0]
+		cfg.Alertmanager.NoRetryStatusCodes = codes[1]
+
+		err := cfg.Validate()
+		if !errors.Is(err, config.ErrAlertmanagerRetryStatus) {
+			t.Fatalf("retry=%v noRetry=%v: expected ErrAlertmanagerRetryStatus, got: %v", codes[0], codes[1], err)
+		}
+	}
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.RetryStatusCodes = []int{409}
+	cfg.Alertmanager.NoRetryStatusCodes = []int{503}
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected valid retry status codes, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerCircuitBreakerNegative(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.CircuitBreaker.FailureThreshold = -1
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerCircuitNegative) {
+		t.Fatalf("expected ErrAlertmanagerCircuitNegative, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerTransportNegative(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.Transport.IdleConnTimeout = config.Duration{Duration: -time.Second}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerPoolNegative) {
+		t.Fatalf("expected ErrAlertmanagerPoolNegative, got: %v", err)
+	}
+}
+
+func TestValidateAppsRejectsDuplicateTokens(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Apps["token-a"] = config.AppConfig{AppName: "a", Tokens: []string{"token-a2"}}
+	cfg.Apps["token-b"] = config.AppConfig{AppName: "b", Tokens: []string{" token-a "}}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAppsTokenDuplicate) {
+		t.Fatalf("expected ErrAppsTokenDuplicate, got: %v", err)
+	}
+
+	if strings.Contains(err.Error(), "token-a") {
+		t.Fatalf("expected token to be redacted, got: %v", err)
+	}
+}
+
+func TestValidateAppsHashedTokens(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Apps["sha256:not-hex"] = config.AppConfig{AppName: "a"}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAppsTokenHashInvalid) {
+		t.Fatalf("expected ErrAppsTokenHashInvalid, got: %v", err)
+	}
+
+	// The plaintext and hashed forms of one token must not map to two apps.
+	cfg = minimalValidConfig()
+	cfg.Apps["token-a"] = config.AppConfig{AppName: "a"}
+	cfg.Apps[config.HashedTokenPrefix+strings.ToUpper(config.HashToken("token-a"))] = config.AppConfig{AppName: "b"}
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrAppsTokenDuplicate) {
+		t.Fatalf("expected ErrAppsTokenDuplicate, got: %v", err)
+	}
+}
+
+func TestValidateAppsAppID(t *testing.T) {
+	t.Parallel()
+
+	zero := uint32(0)
+	cfg := minimalValidConfig()
+	cfg.Apps["token-a"] = config.AppConfig{AppName: "a", AppID: &zero}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAppsAppIDZero) {
+		t.Fatalf("expected ErrAppsAppIDZero, got: %v", err)
+	}
+
+	shared := uint32(42)
+	cfg = minimalValidConfig()
+	cfg.Apps["token-a"] = config.AppConfig{AppName: "a", AppID: &shared}
+	cfg.Apps["token-b"] = config.AppConfig{AppName: "b", AppID: &shared}
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrAppsAppIDDuplicate) {
+		t.Fatalf("expected ErrAppsAppIDDuplicate, got: %v", err)
+	}
+}
+
+func TestValidateTTLFromPriorityRequiresPositiveDurations(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Apps["token"] = config.AppConfig{
+		AppName:         "a",
+		TTLFromPriority: map[int]config.Duration{5: {Duration: 0}},
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrTTLFromPriorityInvalid) {
+		t.Fatalf("expected ErrTTLFromPriorityInvalid, got: %v", err)
+	}
+}
+
+func TestValidatePriorityMatch(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Defaults.PriorityMatch != mapping.MatchFloor {
+		t.Fatalf("expected default priorityMatch %q, got %q", mapping.MatchFloor, cfg.Defaults.PriorityMatch)
+	}
+
+	cfg = minimalValidConfig()
+	cfg.Defaults.PriorityMatch = "round"
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrPriorityMatchInvalid) {
+		t.Fatalf("expected ErrPriorityMatchInvalid, got: %v", err)
+	}
+}
+
+func TestLoadFileRejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "gotilert.yaml")
+
+	err := os.WriteFile(path, []byte(`alertmanager:
+  url: "http://alertmanager:9093"
+defaults:
+  ttl: "5m"
+  severtiyFromPriority:
+    0: info
+apps:
+  "ANY-TOKEN":
+    appName: "backup"
+`), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err = config.LoadFile(path)
+	if err == nil {
+		t.Fatalf("expected unknown field error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "severtiyFromPriority") || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("expected error naming the key and its line, got: %v", err)
+	}
+
+	err = os.WriteFile(path, []byte(`alertmanager:
+  url: "http://alertmanager:9093"
+defaults:
+  ttl: "5m"
+  severityFromPriority:
+    0: info
+apps:
+  "ANY-TOKEN":
+    appName: "backup"
+`), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	if cfg.Defaults.TTL.Duration != 5*time.Minute || cfg.Apps["ANY-TOKEN"].AppName != "backup" {
+		t.Fatalf("expected ttl and app token to load, got ttl=%s apps=%v", cfg.Defaults.TTL, cfg.Apps)
+	}
+}
+
+func TestLoadFileValidationErrorsIncludeLine(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "gotilert.yaml")
+
+	err := os.WriteFile(path, []byte(`alertmanager:
+  url: "http://alertmanager:9093"
+defaults:
+  ttl: "5m"
+  severityFromPriority:
+    0: info
+apps:
+  "SECRET-TOKEN":
+    appName: "backup"
+    severityFromPriority:
+      0: info
+      5: urgent
+`), 0o600)
+	if err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	_, err = config.LoadFile(path)
+	if !errors.Is(err, config.ErrInvalidSeverity) {
+		t.Fatalf("expected ErrInvalidSeverity, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "apps[token(len=12)].severityFromPriority[5] line=12") {
+		t.Fatalf("expected redacted token and line number, got: %v", err)
+	}
+
+	if strings.Contains(err.Error(), "SECRET-TOKEN") {
+		t.Fatalf("expected token to be redacted, got: %v", err)
+	}
+}
+
+func TestLoadFileSeverityRanges(t *testing.T) {
+	t.Parallel()
+
+	const header = `alertmanager:
+  url: "http://alertmanager:9093"
+defaults:
+  ttl: "5m"
+  severityFromPriority:
+`
+
+	cases := []struct {
+		name    string
+		mapping string
+		wantErr error
+	}{
+		{name: "ranges and single keys", mapping: "    0-3: info\n    4 - 7: Warning\n    8: critical\n"},
+		{name: "overlap", mapping: "    0-5: info\n    5-10: critical\n", wantErr: config.ErrSeverityRangeOverlap},
+		{name: "duplicate via range", mapping: "    3: info\n    0-4: warning\n", wantErr: config.ErrSeverityRangeOverlap},
+		{name: "reversed", mapping: "    7-3: info\n", wantErr: config.ErrSeverityRangeInvalid},
+		{name: "negative low", mapping: "    -1-3: info\n", wantErr: config.ErrSeverityRangeInvalid},
+		{name: "not a number", mapping: "    low: info\n", wantErr: config.ErrSeverityRangeInvalid},
+	}
+
+	for _, testCase := range cases {
+		path := filepath.Join(t.TempDir(), "gotilert.yaml")
+
+		err := os.WriteFile(path, []byte(header+testCase.mapping), 0o600)
+		if err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		cfg, err := config.LoadFile(path)
+		if testCase.wantErr != nil {
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("%s: expected %v, got: %v", testCase.name, testCase.wantErr, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", testCase.name, err)
+		}
+
+		want := config.SeverityMap{
+			0: "info", 1: "info", 2: "info", 3: "info",
+			4: "warning", 5: "warning", 6: "warning", 7: "warning",
+			8: "critical",
+		}
+		if !maps.Equal(cfg.Defaults.SeverityFromPriority, want) {
+			t.Fatalf("%s: expected %v, got %v", testCase.name, want, cfg.Defaults.SeverityFromPriority)
+		}
+	}
+}
+
+func TestValidateAlertmanagerURLAndURLsExclusive(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.URLs = []string{"http://alertmanager-1.example.local"}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerURLExclusive) {
+		t.Fatalf("expected ErrAlertmanagerURLExclusive, got: %v", err)
+	}
+}
+
+func TestValidateAlertmanagerURLsInvalidPeer(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Alertmanager.URL = ""
+	cfg.Alertmanager.URLs = []string{
+		"http://alertmanager-1.example.local",
+		"ftp://alertmanager-2.example.local",
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrAlertmanagerURLInvalidScheme) {
+		t.Fatalf("expected ErrAlertmanagerURLInvalidScheme, got: %v", err)
+	}
+}
+
+func TestValidateRejectsInvalidLabelTemplate(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Apps = map[string]config.AppConfig{
+		"TOKEN": {
+			AppName: "truenas",
+			Labels:  map[string]string{"instance": "{{ .Title "},
+		},
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrTemplateInvalid) {
+		t.Fatalf("expected ErrTemplateInvalid, got: %v", err)
+	}
+}
+
+func TestPriorityRangeClamp(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Defaults.PriorityRange = config.PriorityRange{Min: 1}
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	priorityRange := cfg.Defaults.PriorityRange
+
+	if got := priorityRange.Clamp(0); got != 1 {
+		t.Fatalf("expected low priority clamped to 1, got %d", got)
+	}
+
+	if got := priorityRange.Clamp(999); got != 999 {
+		t.Fatalf("expected high priority untouched without clampHigh, got %d", got)
+	}
+
+	priorityRange.ClampHigh = true
+
+	if got := priorityRange.Clamp(999); got != config.DefaultMaxPriority {
+		t.Fatalf("expected high priority clamped to %d, got %d", config.DefaultMaxPriority, got)
+	}
+}
+
+func TestValidatePriorityRangeInvalid(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	maxPriority := 5
+	cfg.Defaults.PriorityRange = config.PriorityRange{Min: 8, Max: &maxPriority}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrPriorityRangeInvalid) {
+		t.Fatalf("expected ErrPriorityRangeInvalid, got: %v", err)
+	}
+}
+
+func TestValidatePriorityRangeKeepsExplicitZeroMax(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	maxPriority := 0
+	cfg.Defaults.PriorityRange = config.PriorityRange{Max: &maxPriority, ClampHigh: true}
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if got := cfg.Defaults.PriorityRange.Clamp(7); got != 0 {
+		t.Fatalf("expected priorities clamped to 0, got %d", got)
+	}
+
+	cfg = minimalValidConfig()
+	cfg.Defaults.PriorityRange = config.PriorityRange{Min: 1, Max: &maxPriority}
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrPriorityRangeInvalid) {
+		t.Fatalf("expected ErrPriorityRangeInvalid for max < min, got: %v", err)
+	}
+}
+
+func TestValidateSummaryMaxLen(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if cfg.Defaults.SummaryMaxLen != config.DefaultSummaryMaxLen {
+		t.Fatalf("expected summaryMaxLen defaulted to %d, got %d", config.DefaultSummaryMaxLen, cfg.Defaults.SummaryMaxLen)
+	}
+
+	cfg = minimalValidConfig()
+	cfg.Defaults.SummaryMaxLen = -1
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrDefaultsSummaryNegative) {
+		t.Fatalf("expected ErrDefaultsSummaryNegative, got: %v", err)
+	}
+}
+
+func TestValidateValueMaxLen(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if cfg.Defaults.ValueMaxLen != config.DefaultValueMaxLen {
+		t.Fatalf("expected valueMaxLen defaulted to %d, got %d", config.DefaultValueMaxLen, cfg.Defaults.ValueMaxLen)
+	}
+
+	cfg = minimalValidConfig()
+	cfg.Defaults.ValueMaxLen = -1
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrDefaultsValueMaxLen) {
+		t.Fatalf("expected ErrDefaultsValueMaxLen, got: %v", err)
+	}
+}
+
+func TestValidateAutoResolveAfterNegative(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Defaults.AutoResolveAfter = config.Duration{Duration: -time.Minute}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrDefaultsAutoResolve) {
+		t.Fatalf("expected ErrDefaultsAutoResolve, got: %v", err)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	t.Parallel()
+
+	low, high := 7, 3
+
+	cases := []struct {
+		name    string
+		rules   []config.RuleConfig
+		wantErr error
+	}{
+		{
+			name:    "bad regex",
+			rules:   []config.RuleConfig{{Match: config.RuleMatch{Title: "("}, Drop: true}},
+			wantErr: config.ErrRuleRegexInvalid,
+		},
+		{
+			name:    "empty match",
+			rules:   []config.RuleConfig{{Drop: true}},
+			wantErr: config.ErrRuleMatchEmpty,
+		},
+		{
+			name:    "drop with labels",
+			rules:   []config.RuleConfig{{Match: config.RuleMatch{Title: "x"}, Drop: true, SetSeverity: "info"}},
+			wantErr: config.ErrRuleActionInvalid,
+		},
+		{
+			name:    "no action",
+			rules:   []config.RuleConfig{{Match: config.RuleMatch{Title: "x"}}},
+			wantErr: config.ErrRuleActionInvalid,
+		},
+		{
+			name:    "reversed priorities",
+			rules:   []config.RuleConfig{{Match: config.RuleMatch{MinPriority: &low, MaxPriority: &high}, Drop: true}},
+			wantErr: config.ErrRulePriorityRange,
+		},
+		{
+			name:    "bad severity",
+			rules:   []config.RuleConfig{{Match: config.RuleMatch{Title: "x"}, SetSeverity: "urgent"}},
+			wantErr: config.ErrInvalidSeverity,
+		},
+		{
+			name: "duplicate default name",
+			rules: []config.RuleConfig{
+				{Match: config.RuleMatch{Title: "x"}, Drop: true},
+				{Name: "rule-1", Match: config.RuleMatch{Title: "y"}, Drop: true},
+			},
+			wantErr: config.ErrRuleNameDuplicate,
+		},
+	}
+
+	for _, testCase := range cases {
+		cfg := minimalValidConfig()
+		cfg.Defaults.Rules = testCase.rules
+
+		err := cfg.Validate()
+		if !errors.Is(err, testCase.wantErr) {
+			t.Fatalf("%s: expected %v, got: %v", testCase.name, testCase.wantErr, err)
+		}
+	}
+
+	cfg := minimalValidConfig()
+	cfg.Defaults.Rules = []config.RuleConfig{{Match: config.RuleMatch{Title: "x"}, SetSeverity: "Crit"}}
+
+	err := cfg.Validate()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if rule := cfg.Defaults.Rules[0]; rule.Name != "rule-1" || rule.SetSeverity != "critical" {
+		t.Fatalf("expected default name and canonical severity, got %+v", rule)
+	}
+}
+
+func TestValidateExtrasAllowDenyExclusive(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Defaults.Extras = config.ExtrasConfig{
+		Allow: []string{"client::notification.click.url"},
+		Deny:  []string{"client::notification.bigImageUrl"},
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrExtrasPolicyInvalid) {
+		t.Fatalf("expected ErrExtrasPolicyInvalid, got: %v", err)
+	}
+}
+
+func TestValidateMetricsAuthExclusive(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Server.Metrics.Auth = config.MetricsAuthConfig{
+		BearerToken: "token",
+		BasicAuth:   &config.MetricsBasicAuth{Username: "prometheus", Password: "secret"},
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrServerMetricsAuthInvalid) {
+		t.Fatalf("expected ErrServerMetricsAuthInvalid, got: %v", err)
+	}
+}
+
+func TestValidateAllowedCIDRs(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Server.AllowedCIDRs = []string{" 10.0.0.0/8 ", "192.0.2.7", "2001:db8::/32"}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if cfg.Server.AllowedCIDRs[0] != "10.0.0.0/8" {
+		t.Fatalf("expected trimmed CIDR, got %q", cfg.Server.AllowedCIDRs[0])
+	}
+
+	prefixes, err := config.ParsePrefixes(cfg.Server.AllowedCIDRs)
+	if err != nil {
+		t.Fatalf("ParsePrefixes: %v", err)
+	}
+
+	if got := prefixes[1].String(); got != "192.0.2.7/32" {
+		t.Fatalf("expected bare IP as /32, got %q", got)
+	}
+
+	cfg.Server.AllowedCIDRs = []string{"10.0.0.0/33"}
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrServerCIDRInvalid) {
+		t.Fatalf("expected ErrServerCIDRInvalid, got: %v", err)
+	}
+
+	cfg.Server.AllowedCIDRs = nil
+	cfg.Server.TrustedProxies = []string{"proxy.local"}
+
+	err = cfg.Validate()
+	if !errors.Is(err, config.ErrServerCIDRInvalid) {
+		t.Fatalf("expected ErrServerCIDRInvalid for trustedProxies, got: %v", err)
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+	cfg.Server.CORS = config.CORSConfig{
+		AllowedOrigins: []string{" HTTPS://Dashboard.example.com:8443 ", "*"},
+		AllowedMethods: []string{"post"},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if got := cfg.Server.CORS.AllowedOrigins[0]; got != "https://dashboard.example.com:8443" {
+		t.Fatalf("expected normalized origin, got %q", got)
+	}
+
+	if got := cfg.Server.CORS.AllowedMethods[0]; got != "POST" {
+		t.Fatalf("expected upper-case method, got %q", got)
+	}
+
+	for _, origin := range []string{"dashboard.example.com", "https://dashboard.example.com/app", "ftp://host", "https://"} {
+		cfg.Server.CORS = config.CORSConfig{AllowedOrigins: []string{origin}}
+
+		err := cfg.Validate()
+		if !errors.Is(err, config.ErrServerCORSInvalid) {
+			t.Fatalf("origin %q: expected ErrServerCORSInvalid, got: %v", origin, err)
+		}
+	}
+}
+
+func TestValidateDefaultContentType(t *testing.T) {
+	t.Parallel()
+
+	cfg := minimalValidConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if cfg.Server.DefaultContentType != config.ContentTypeForm {
+		t.Fatalf("expected default %q, got %q", config.ContentTypeForm, cfg.Server.DefaultContentType)
+	}
+
+	cfg.Server.DefaultContentType = " JSON "
+
+	if err := cfg.Validate(); err != nil || cfg.Server.DefaultContentType != config.ContentTypeJSON {
+		t.Fatalf("expected %q, got %q (err=%v)", config.ContentTypeJSON, cfg.Server.DefaultContentType, err)
+	}
+
+	cfg.Server.DefaultContentType = "xml"
+
+	err := cfg.Validate()
+	if !errors.Is(err, config.ErrServerContentType) {
+		t.Fatalf("expected ErrServerContentType, got: %v", err)
+	}
+}
+
 func minimalValidConfig() *config.Config {
 	return &config.Config{
 		Server: config.ServerConfig{
@@ -0,0 +1,120 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const corsAnyOrigin = "*"
+
+// CORSConfig enables CORS on /message for browser clients; it is off while AllowedOrigins is empty.
+type CORSConfig struct {
+	// AllowedOrigins lists origins ("https://dashboard.example.com") allowed to call /message,
+	// or "*" for any origin. Only a matching request Origin is reflected in the response.
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+
+	// AllowedMethods and AllowedHeaders are announced in preflight responses; empty uses
+	// the server defaults (POST; Content-Type and the token/signature headers).
+	AllowedMethods []string `yaml:"allowedMethods"`
+	AllowedHeaders []string `yaml:"allowedHeaders"`
+
+	// MaxAge lets browsers cache preflight results; 0 omits Access-Control-Max-Age.
+	MaxAge Duration `yaml:"maxAge"`
+}
+
+// Enabled reports whether CORS is configured.
+func (cors *CORSConfig) Enabled() bool {
+	return len(cors.AllowedOrigins) > 0
+}
+
+// validate normalizes origins to scheme://host[:port] (lowercase), methods to upper case,
+// and rejects anything that is not a plain origin, method or header name.
+func (cors *CORSConfig) validate() error {
+	for index, origin := range cors.AllowedOrigins {
+		normalized, ok := normalizeOrigin(origin)
+		if !ok {
+			return fmt.Errorf("%w: origin %q", ErrServerCORSInvalid, origin)
+		}
+
+		cors.AllowedOrigins[index] = normalized
+	}
+
+	for index, method := range cors.AllowedMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if !isHTTPToken(method) {
+			return fmt.Errorf("%w: method %q", ErrServerCORSInvalid, cors.AllowedMethods[index])
+		}
+
+		cors.AllowedMethods[index] = method
+	}
+
+	for index, header := range cors.AllowedHeaders {
+		header = strings.TrimSpace(header)
+		if !isHTTPToken(header) {
+			return fmt.Errorf("%w: header %q", ErrServerCORSInvalid, cors.AllowedHeaders[index])
+		}
+
+		cors.AllowedHeaders[index] = header
+	}
+
+	if cors.MaxAge.Duration < 0 {
+		return fmt.Errorf("%w: maxAge must be >= 0", ErrServerCORSInvalid)
+	}
+
+	return nil
+}
+
+func normalizeOrigin(origin string) (string, bool) {
+	origin = strings.TrimSpace(origin)
+	if origin == corsAnyOrigin {
+		return origin, true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+		parsed.User != nil || parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", false
+	}
+
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), true
+}
+
+// isHTTPToken reports whether value is a non-empty RFC 9110 token (method or header name).
+func isHTTPToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, char := range value {
+		if char > 0x7e || char <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, char) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,137 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// appsFragment is what every fragment after the base file may contain.
+type appsFragment struct {
+	Apps map[string]AppConfig `yaml:"apps"`
+}
+
+// Load loads configuration from path: a single YAML file (LoadFile) or a directory of
+// fragments (LoadDir).
+func Load(path string) (*Config, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, ErrConfigFilePathEmpty
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file %q: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return LoadDir(path)
+	}
+
+	return LoadFile(path)
+}
+
+// LoadDir loads every *.yaml file in dir in lexical order and validates the merged result.
+// The first file is the base and provides every section; the others may only define apps,
+// which are unioned. A token defined in two fragments is an error.
+func LoadDir(dir string) (*Config, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("list config directory %q: %w", dir, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrConfigDirEmpty, dir)
+	}
+
+	slices.Sort(files)
+
+	var cfg Config
+
+	baseLines, err := decodeFile(files[0], &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.positions = baseLines.withFile(filepath.Base(files[0]))
+
+	// origin remembers which fragment defined each token, for conflict errors.
+	origin := make(map[string]string, len(cfg.Apps))
+	for token := range cfg.Apps {
+		origin[token] = filepath.Base(files[0])
+	}
+
+	for _, file := range files[1:] {
+		err = cfg.mergeFragment(file, origin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validate config directory %q: %w", dir, err)
+	}
+
+	return &cfg, nil
+}
+
+func (cfg *Config) mergeFragment(file string, origin map[string]string) error {
+	var fragment appsFragment
+
+	lines, err := decodeFile(file, &fragment)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(file)
+
+	if cfg.Apps == nil {
+		cfg.Apps = make(map[string]AppConfig, len(fragment.Apps))
+	}
+
+	for _, token := range slices.Sorted(maps.Keys(fragment.Apps)) {
+		if previous, ok := origin[token]; ok {
+			return fmt.Errorf(
+				"%w: %s in %s and %s",
+				ErrConfigFragmentTokenConflict,
+				RedactToken(token),
+				previous,
+				name,
+			)
+		}
+
+		origin[token] = name
+		cfg.Apps[token] = fragment.Apps[token]
+	}
+
+	maps.Copy(cfg.positions, lines.withFile(name))
+
+	return nil
+}
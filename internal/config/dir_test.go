@@ -0,0 +1,122 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/config"
+)
+
+const baseFragment = `alertmanager:
+  url: "http://alertmanager:9093"
+defaults:
+  ttl: "5m"
+  severityFromPriority:
+    0: info
+apps:
+  "TOKEN-A":
+    appName: "a"
+`
+
+func writeFragments(t *testing.T, fragments map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range fragments {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	return dir
+}
+
+func TestLoadDirMergesAppsFromFragments(t *testing.T) {
+	t.Parallel()
+
+	dir := writeFragments(t, map[string]string{
+		"00-base.yaml": baseFragment,
+		"10-team.yaml": "apps:\n  \"TOKEN-B\":\n    appName: \"b\"\n",
+		"notes.txt":    "ignored",
+	})
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	if cfg.Apps["TOKEN-A"].AppName != "a" || cfg.Apps["TOKEN-B"].AppName != "b" {
+		t.Fatalf("expected apps from both fragments, got %v", cfg.Apps)
+	}
+}
+
+func TestLoadDirRejectsConflictsAndNonAppSections(t *testing.T) {
+	t.Parallel()
+
+	dir := writeFragments(t, map[string]string{
+		"00-base.yaml": baseFragment,
+		"10-team.yaml": "apps:\n  \"TOKEN-A\":\n    appName: \"other\"\n",
+	})
+
+	_, err := config.Load(dir)
+	if !errors.Is(err, config.ErrConfigFragmentTokenConflict) {
+		t.Fatalf("expected ErrConfigFragmentTokenConflict, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "00-base.yaml and 10-team.yaml") || strings.Contains(err.Error(), "TOKEN-A") {
+		t.Fatalf("expected both file names and a redacted token, got: %v", err)
+	}
+
+	dir = writeFragments(t, map[string]string{
+		"00-base.yaml": baseFragment,
+		"10-team.yaml": "defaults:\n  ttl: \"1m\"\n",
+	})
+
+	_, err = config.Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "10-team.yaml") {
+		t.Fatalf("expected an error for a non-apps section in a fragment, got: %v", err)
+	}
+}
+
+func TestLoadDirValidationErrorsNameTheFragment(t *testing.T) {
+	t.Parallel()
+
+	dir := writeFragments(t, map[string]string{
+		"00-base.yaml": baseFragment,
+		"10-team.yaml": "apps:\n  \"TOKEN-B\":\n    appName: \"b\"\n    severityFromPriority:\n      0: urgent\n",
+	})
+
+	_, err := config.Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "file=10-team.yaml line=5") {
+		t.Fatalf("expected the fragment name and line in the error, got: %v", err)
+	}
+}
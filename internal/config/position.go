@@ -0,0 +1,112 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// positions maps a dotted key path (e.g. "apps.<token>.severityFromPriority.5") to where that
+// key is in the loaded file(s), so validation errors can point at it.
+type positions map[string]position
+
+type position struct {
+	// file is set only when the config was merged from several fragments.
+	file string
+	line int
+}
+
+func newPositions(root *yaml.Node) positions {
+	lines := positions{}
+
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		lines.walk("", root.Content[0])
+	}
+
+	return lines
+}
+
+func (lines positions) walk(prefix string, node *yaml.Node) {
+	if node.Kind == yaml.SequenceNode {
+		// Sequence items are addressed by index, e.g. "defaults.rules.0".
+		for index, item := range node.Content {
+			path := prefix + "." + strconv.Itoa(index)
+
+			lines[path] = position{line: item.Line}
+			lines.walk(path, item)
+		}
+
+		return
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		key, value := node.Content[index], node.Content[index+1]
+
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+
+		lines[path] = position{line: key.Line}
+		lines.walk(path, value)
+	}
+}
+
+// at returns " line=N" (" file=F line=N" for fragments) for the deepest known prefix of path,
+// or "" when the config was not loaded from disk (or the path is unknown).
+func (lines positions) at(path ...string) string {
+	for length := len(path); length > 0; length-- {
+		found, ok := lines[strings.Join(path[:length], ".")]
+		if !ok {
+			continue
+		}
+
+		if found.file != "" {
+			return " file=" + found.file + " line=" + strconv.Itoa(found.line)
+		}
+
+		return " line=" + strconv.Itoa(found.line)
+	}
+
+	return ""
+}
+
+// withFile returns lines with every entry attributed to file.
+func (lines positions) withFile(file string) positions {
+	out := make(positions, len(lines))
+	for path, found := range lines {
+		found.file = file
+		out[path] = found
+	}
+
+	return out
+}
@@ -0,0 +1,142 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/leinardi/gotilert/internal/logger"
+)
+
+// RedactedValue replaces secrets in Redacted.
+const RedactedValue = "<redacted>"
+
+// RedactToken returns a stable, non-secret stand-in for an app token (used in errors and output).
+func RedactToken(token string) string {
+	return fmt.Sprintf("token(len=%d)", len(token))
+}
+
+// RedactTokens applies RedactToken to every token (nil stays nil).
+func RedactTokens(tokens []string) []string {
+	if tokens == nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		out = append(out, RedactToken(token))
+	}
+
+	return out
+}
+
+// RedactedAppKeys maps every app token to a unique RedactToken key. Tokens of equal length
+// get a "#n" suffix, assigned in token order so the keys are stable across runs.
+func (cfg *Config) RedactedAppKeys() map[string]string {
+	tokens := slices.Sorted(maps.Keys(cfg.Apps))
+	keys := make(map[string]string, len(tokens))
+	seen := make(map[string]int, len(tokens))
+
+	for _, token := range tokens {
+		// The catch-all key is not a secret; keep it recognizable.
+		if token == CatchAllToken {
+			keys[token] = CatchAllToken
+
+			continue
+		}
+
+		key := RedactToken(token)
+
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s#%d", key, seen[key])
+		}
+
+		keys[token] = key
+	}
+
+	return keys
+}
+
+// Redacted returns a copy of cfg that is safe to print: credentials and header values are
+// masked, URL userinfo is replaced by logger.RedactURL and apps are keyed by RedactedAppKeys.
+func (cfg *Config) Redacted() *Config {
+	out := *cfg
+
+	out.Server.AdminToken = redactSecret(cfg.Server.AdminToken)
+	out.Server.Metrics.Auth.BearerToken = redactSecret(cfg.Server.Metrics.Auth.BearerToken)
+
+	if cfg.Server.Metrics.Auth.BasicAuth != nil {
+		metricsBasicAuth := *cfg.Server.Metrics.Auth.BasicAuth
+		metricsBasicAuth.Password = redactSecret(metricsBasicAuth.Password)
+		out.Server.Metrics.Auth.BasicAuth = &metricsBasicAuth
+	}
+
+	amConfig := &out.Alertmanager
+	amConfig.URL = logger.RedactURL(cfg.Alertmanager.URL)
+	amConfig.URLs = make([]string, 0, len(cfg.Alertmanager.URLs))
+
+	for _, rawURL := range cfg.Alertmanager.URLs {
+		amConfig.URLs = append(amConfig.URLs, logger.RedactURL(rawURL))
+	}
+
+	amConfig.ProxyURL = logger.RedactURL(cfg.Alertmanager.ProxyURL)
+	amConfig.Bearer = redactSecret(cfg.Alertmanager.Bearer)
+
+	if cfg.Alertmanager.BasicAuth != nil {
+		basicAuth := *cfg.Alertmanager.BasicAuth
+		basicAuth.Password = redactSecret(basicAuth.Password)
+		amConfig.BasicAuth = &basicAuth
+	}
+
+	// Header values routinely carry credentials (Authorization, X-API-Key, ...).
+	amConfig.Headers = make(map[string]string, len(cfg.Alertmanager.Headers))
+	for name, value := range cfg.Alertmanager.Headers {
+		amConfig.Headers[name] = redactSecret(value)
+	}
+
+	out.Defaults.SigningSecret = redactSecret(cfg.Defaults.SigningSecret)
+
+	keys := cfg.RedactedAppKeys()
+
+	out.Apps = make(map[string]AppConfig, len(cfg.Apps))
+	for token, app := range cfg.Apps {
+		app.Tokens = RedactTokens(app.Tokens)
+		app.SigningSecret = redactSecret(app.SigningSecret)
+		out.Apps[keys[token]] = app
+	}
+
+	return &out
+}
+
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return RedactedValue
+}
@@ -0,0 +1,153 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	ErrRuleMatchEmpty    = errors.New("defaults.rules match needs title, message, minPriority or maxPriority")
+	ErrRuleRegexInvalid  = errors.New("defaults.rules match regex is invalid")
+	ErrRulePriorityRange = errors.New("defaults.rules priorities require 0 <= minPriority <= maxPriority")
+	ErrRuleActionInvalid = errors.New("defaults.rules needs drop, or setLabels and/or setSeverity")
+	ErrRuleNameDuplicate = errors.New("defaults.rules name is used more than once")
+)
+
+// RuleConfig is a defaults.rules entry. A message matching every set matcher gets the
+// rule's actions; rules are evaluated in order and all matching rules apply, except that
+// drop stops evaluation.
+type RuleConfig struct {
+	// Name labels gotilert_rule_matches_total; it defaults to "rule-<position>" (1-based).
+	Name  string    `yaml:"name"`
+	Match RuleMatch `yaml:"match"`
+
+	// Drop discards the message (the client still gets a success response). It cannot be
+	// combined with the other actions.
+	Drop bool `yaml:"drop"`
+
+	// SetLabels are templated like labels and applied over the computed labels (alertname,
+	// app, severity, ...); defaults.forcedLabels still win.
+	SetLabels map[string]string `yaml:"setLabels"`
+
+	// SetSeverity replaces the severity derived from severityFromPriority.
+	SetSeverity string `yaml:"setSeverity"`
+}
+
+// RuleMatch holds a rule's matchers. Title and Message are regular expressions (RE2,
+// unanchored); priorities are inclusive bounds on the clamped priority.
+type RuleMatch struct {
+	Title       string `yaml:"title"`
+	Message     string `yaml:"message"`
+	MinPriority *int   `yaml:"minPriority"`
+	MaxPriority *int   `yaml:"maxPriority"`
+}
+
+// Empty reports whether the match has no matcher at all.
+func (match *RuleMatch) Empty() bool {
+	return match.Title == "" && match.Message == "" && match.MinPriority == nil && match.MaxPriority == nil
+}
+
+func (cfg *Config) validateRules() error {
+	names := make(map[string]bool, len(cfg.Defaults.Rules))
+
+	for index := range cfg.Defaults.Rules {
+		rule := &cfg.Defaults.Rules[index]
+		path := []string{"defaults", "rules", strconv.Itoa(index)}
+
+		if rule.Name == "" {
+			rule.Name = "rule-" + strconv.Itoa(index+1)
+		}
+
+		if names[rule.Name] {
+			return fmt.Errorf("%w: %q%s", ErrRuleNameDuplicate, rule.Name, cfg.positions.at(path...))
+		}
+
+		names[rule.Name] = true
+
+		err := validateRuleMatch(&rule.Match)
+		if err != nil {
+			return fmt.Errorf("defaults.rules[%s]%s: %w", rule.Name, cfg.positions.at(append(path, "match")...), err)
+		}
+
+		if rule.Drop == (len(rule.SetLabels) > 0 || rule.SetSeverity != "") {
+			return fmt.Errorf("%w: %q%s", ErrRuleActionInvalid, rule.Name, cfg.positions.at(path...))
+		}
+
+		if rule.SetSeverity != "" {
+			err = validateSeverity(rule.SetSeverity)
+			if err != nil {
+				return fmt.Errorf(
+					"defaults.rules[%s].setSeverity%s: %w",
+					rule.Name,
+					cfg.positions.at(append(path, "setSeverity")...),
+					err,
+				)
+			}
+
+			rule.SetSeverity = canonicalSeverity(rule.SetSeverity)
+		}
+
+		err = validateTemplateMap(
+			"defaults.rules["+rule.Name+"].setLabels",
+			rule.SetLabels,
+			cfg.lineOf(append(path, "setLabels")...),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRuleMatch(match *RuleMatch) error {
+	if match.Empty() {
+		return ErrRuleMatchEmpty
+	}
+
+	for _, pattern := range []string{match.Title, match.Message} {
+		if pattern == "" {
+			continue
+		}
+
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrRuleRegexInvalid, err)
+		}
+	}
+
+	minPriority, maxPriority := match.MinPriority, match.MaxPriority
+
+	if (minPriority != nil && *minPriority < 0) || (maxPriority != nil && *maxPriority < 0) ||
+		(minPriority != nil && maxPriority != nil && *maxPriority < *minPriority) {
+		return ErrRulePriorityRange
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSeverityRangeSpan bounds how many priorities a single range key may cover, since
+// ranges are expanded to one entry per priority.
+const maxSeverityRangeSpan = 1000
+
+var (
+	ErrSeverityMapExpectedMapping = errors.New("severityFromPriority must be a mapping")
+	ErrSeverityRangeInvalid       = errors.New(
+		"severityFromPriority key must be a priority or a range low-high (0 <= low <= high, at most 1000 apart)",
+	)
+	ErrSeverityRangeOverlap = errors.New("severityFromPriority keys overlap")
+)
+
+// SeverityMap maps Gotify priorities to severities. In YAML a key is either one priority
+// ("5") or an inclusive range ("0-3"). Ranges are expanded to one entry per priority, so
+// lookups (floor, ceil, nearest) work the same for both forms.
+type SeverityMap map[int]string
+
+func (severityMap *SeverityMap) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("%w: kind=%d", ErrSeverityMapExpectedMapping, node.Kind)
+	}
+
+	out := make(SeverityMap, len(node.Content)/2)
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode, valueNode := node.Content[index], node.Content[index+1]
+
+		low, high, err := parsePriorityKey(keyNode.Value)
+		if err != nil {
+			return fmt.Errorf("%w: %q line=%d", err, keyNode.Value, keyNode.Line)
+		}
+
+		var severity string
+
+		err = valueNode.Decode(&severity)
+		if err != nil {
+			return fmt.Errorf("severityFromPriority %q line=%d: %w", keyNode.Value, valueNode.Line, err)
+		}
+
+		for priority := low; priority <= high; priority++ {
+			if _, taken := out[priority]; taken {
+				return fmt.Errorf(
+					"%w: %q covers priority %d again line=%d",
+					ErrSeverityRangeOverlap,
+					keyNode.Value,
+					priority,
+					keyNode.Line,
+				)
+			}
+
+			out[priority] = severity
+		}
+	}
+
+	*severityMap = out
+
+	return nil
+}
+
+// parsePriorityKey parses "5" (low == high) or "0-3". A single negative priority parses so
+// Validate can report it like before; ranges must be non-negative and ordered.
+func parsePriorityKey(key string) (int, int, error) {
+	key = strings.TrimSpace(key)
+
+	priority, err := strconv.Atoi(key)
+	if err == nil {
+		return priority, priority, nil
+	}
+
+	lowText, highText, found := strings.Cut(key, "-")
+	if !found {
+		return 0, 0, ErrSeverityRangeInvalid
+	}
+
+	low, lowErr := strconv.Atoi(strings.TrimSpace(lowText))
+	high, highErr := strconv.Atoi(strings.TrimSpace(highText))
+
+	if lowErr != nil || highErr != nil || low < 0 || high < low || high-low > maxSeverityRangeSpan {
+		return 0, 0, ErrSeverityRangeInvalid
+	}
+
+	return low, high, nil
+}
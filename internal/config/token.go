@@ -0,0 +1,62 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// HashedTokenPrefix marks an apps key (or apps[*].tokens entry) holding the hex-encoded
+// SHA-256 digest of a token instead of the token itself.
+const HashedTokenPrefix = "sha256:"
+
+// HashToken returns the lowercase hex SHA-256 digest of token, the form expected after
+// HashedTokenPrefix (same as `printf %s "$TOKEN" | sha256sum`).
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenDigest returns the digest a configured token is looked up by: the normalized digest
+// of a "sha256:<hex>" entry, or HashToken of a plaintext one.
+func TokenDigest(configured string) (string, error) {
+	if len(configured) < len(HashedTokenPrefix) ||
+		!strings.EqualFold(configured[:len(HashedTokenPrefix)], HashedTokenPrefix) {
+		return HashToken(configured), nil
+	}
+
+	digest := strings.ToLower(configured[len(HashedTokenPrefix):])
+
+	decoded, err := hex.DecodeString(digest)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("%w: expected %s followed by %d hex characters", ErrAppsTokenHashInvalid, HashedTokenPrefix, 2*sha256.Size)
+	}
+
+	return digest, nil
+}
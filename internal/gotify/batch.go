@@ -0,0 +1,96 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package gotify
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BatchLine is one message of a batch request: the parsed message, or the error that made
+// its line invalid. Line is 1-based and counts every physical line, including blank ones.
+type BatchLine struct {
+	Line    int
+	Message MessageRequest
+	Err     error
+}
+
+// IsBatch reports whether request carries an NDJSON batch (Content-Type: application/x-ndjson).
+func IsBatch(request *http.Request) bool {
+	mediaType, err := requestMediaType(request, ParseOptions{})
+
+	return err == nil && mediaType == mediaTypeNDJSON
+}
+
+// ParseMessageRequests parses a request carrying one or more messages. An NDJSON body
+// (application/x-ndjson) yields one BatchLine per non-blank line, each holding a JSON message
+// or its own error, so one bad line does not reject the others. Any other content type is
+// parsed by ParseMessageRequest as a single line. The returned error is reserved for failures
+// of the request as a whole (unreadable or oversized body, more than opts.MaxBatchLines
+// messages, no messages at all).
+func ParseMessageRequests(request *http.Request, opts ParseOptions) ([]BatchLine, error) {
+	if !IsBatch(request) {
+		msg, err := ParseMessageRequest(request, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return []BatchLine{{Line: 1, Message: msg}}, nil
+	}
+
+	var lines []BatchLine
+
+	reader := bufio.NewReader(request.Body)
+
+	for number := 1; ; number++ {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("read ndjson body: %w", err)
+		}
+
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 {
+			if opts.MaxBatchLines > 0 && len(lines) == opts.MaxBatchLines {
+				return nil, fmt.Errorf("%w: limit is %d", ErrBatchTooLarge, opts.MaxBatchLines)
+			}
+
+			msg, parseErr := decodeJSONMessage(bytes.NewReader(trimmed), opts)
+			lines = append(lines, BatchLine{Line: number, Message: msg, Err: parseErr})
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrMessageRequired
+	}
+
+	return lines, nil
+}
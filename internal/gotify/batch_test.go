@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package gotify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseMessageRequestsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	body := `{"message":"one","priority":2}` + "\n\n" +
+		`{"title":"no message"}` + "\n" +
+		`not json` + "\n" +
+		`{"message":"four"}`
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	if !IsBatch(req) {
+		t.Fatalf("expected an NDJSON request to be a batch")
+	}
+
+	lines, err := ParseMessageRequests(req, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+
+	if lines[0].Line != 1 || lines[0].Err != nil || lines[0].Message.Message != "one" || lines[0].Message.Priority != 2 {
+		t.Fatalf("unexpected first line: %+v", lines[0])
+	}
+
+	if lines[1].Line != 3 || !errors.Is(lines[1].Err, ErrMessageRequired) {
+		t.Fatalf("expected line 3 to fail with ErrMessageRequired, got %+v", lines[1])
+	}
+
+	if lines[2].Line != 4 || lines[2].Err == nil {
+		t.Fatalf("expected line 4 to fail to decode, got %+v", lines[2])
+	}
+
+	if lines[3].Line != 5 || lines[3].Err != nil || lines[3].Message.Priority != DefaultPriority {
+		t.Fatalf("unexpected last line: %+v", lines[3])
+	}
+}
+
+func TestParseMessageRequestsSingleAndEmpty(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hi"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	lines, err := ParseMessageRequests(req, ParseOptions{})
+	if err != nil || len(lines) != 1 || lines[0].Message.Message != "hi" {
+		t.Fatalf("expected a single parsed line, got %+v (err=%v)", lines, err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader("\n \n"))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	_, err = ParseMessageRequests(req, ParseOptions{})
+	if !errors.Is(err, ErrMessageRequired) {
+		t.Fatalf("expected ErrMessageRequired for an empty batch, got: %v", err)
+	}
+}
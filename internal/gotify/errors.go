@@ -30,4 +30,6 @@ var (
 	ErrMessageRequired        = errors.New("message is required")
 	ErrInvalidPriority        = errors.New("invalid priority")
 	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrInvalidExtras          = errors.New("extras must be a JSON object")
+	ErrBatchTooLarge          = errors.New("too many messages in batch")
 )
@@ -25,6 +25,8 @@
 package gotify
 
 import (
+	"mime"
+	"sort"
 	"strings"
 )
 
@@ -34,73 +36,178 @@ const (
 	AnnotationGotifyClickURL           = "gotify_click_url"
 	AnnotationGotifyBigImageURL        = "gotify_big_image_url"
 	AnnotationGotifyOnReceiveIntentURL = "gotify_on_receive_intent_url"
+
+	// ExtraAnnotationPrefix prefixes annotations derived from extras outside the well-known set.
+	ExtraAnnotationPrefix = "gotify_extra_"
 )
 
-// ExtrasAnnotations extracts a small set of well-known Gotify extras and converts them into
-// string annotations suitable for Alertmanager.
-// Unknown extras are ignored. Non-string values are ignored.
-func ExtrasAnnotations(extras map[string]any) map[string]string {
-	if len(extras) == 0 {
-		return map[string]string{}
-	}
+// Bounds for walking extras so pathological payloads cannot explode the annotation set.
+const (
+	maxExtrasDepth   = 4
+	maxExtrasEntries = 64
+)
+
+const contentTypePath = "client::display.contentType"
+
+// wellKnownExtras maps dotted extras paths to their dedicated annotation names.
+var wellKnownExtras = map[string]string{
+	contentTypePath:                       AnnotationGotifyContentType,
+	"client::notification.click.url":      AnnotationGotifyClickURL,
+	"client::notification.bigImageUrl":    AnnotationGotifyBigImageURL,
+	"android::action.onReceive.intentUrl": AnnotationGotifyOnReceiveIntentURL,
+}
 
+// ExtrasPolicy controls which extras become annotations. Paths are dotted
+// (e.g. "client::notification.click.url") and also match everything below them.
+// The zero value emits only the well-known extras.
+type ExtrasPolicy struct {
+	// Allow, when non-empty, emits only extras under these paths. Allowed paths outside the
+	// well-known set are emitted as gotify_extra_<namespace>_<key>.
+	Allow []string
+	// Deny drops extras under these paths.
+	Deny []string
+	// Passthrough emits every string leaf, not only the well-known ones.
+	Passthrough bool
+}
+
+// ExtrasAnnotations converts extras into string annotations suitable for Alertmanager.
+// Well-known extras keep their dedicated gotify_* names; other string leaves are emitted as
+// gotify_extra_<namespace>_<key> (nested keys joined with "_", names sanitized) when the
+// policy allows them. Non-string values are ignored; nesting deeper than maxExtrasDepth levels
+// and entries beyond maxExtrasEntries (in sorted path order) are dropped.
+func ExtrasAnnotations(extras map[string]any, policy ExtrasPolicy) map[string]string {
 	annotations := make(map[string]string)
 
-	// client::display.contentType
-	if contentType, ok := extrasStringAtPath(extras, "client::display", "contentType"); ok {
-		annotations[AnnotationGotifyContentType] = contentType
-	}
+	for _, leaf := range extrasStringLeaves(extras) {
+		if len(annotations) >= maxExtrasEntries {
+			break
+		}
 
-	// client::notification.click.url
-	if clickURL, ok := extrasStringAtPath(extras, "client::notification", "click", "url"); ok {
-		annotations[AnnotationGotifyClickURL] = clickURL
-	}
+		name, ok := policy.annotationName(leaf.path)
+		if !ok {
+			continue
+		}
 
-	// client::notification.bigImageUrl
-	if bigImageURL, ok := extrasStringAtPath(extras, "client::notification", "bigImageUrl"); ok {
-		annotations[AnnotationGotifyBigImageURL] = bigImageURL
-	}
+		value := leaf.value
+		if leaf.path == contentTypePath {
+			value = normalizeContentType(value)
+		}
 
-	// android::action.onReceive.intentUrl
-	if intentURL, ok := extrasStringAtPath(extras, "android::action", "onReceive", "intentUrl"); ok {
-		annotations[AnnotationGotifyOnReceiveIntentURL] = intentURL
+		annotations[name] = value
 	}
 
 	return annotations
 }
 
-func extrasStringAtPath(extras map[string]any, path ...string) (string, bool) {
-	if len(extras) == 0 || len(path) == 0 {
+func (policy ExtrasPolicy) annotationName(path string) (string, bool) {
+	if matchesAnyPath(policy.Deny, path) {
 		return "", false
 	}
 
-	var current any = extras
+	allowed := matchesAnyPath(policy.Allow, path)
+	if len(policy.Allow) > 0 && !allowed {
+		return "", false
+	}
 
-	for index := range path {
-		key := path[index]
+	if name, ok := wellKnownExtras[path]; ok {
+		return name, true
+	}
 
-		currentMap, ok := current.(map[string]any)
-		if !ok {
-			return "", false
-		}
+	if !policy.Passthrough && !allowed {
+		return "", false
+	}
+
+	name := flattenedAnnotationName(path)
+
+	return name, name != ExtraAnnotationPrefix
+}
 
-		next, exists := currentMap[key]
-		if !exists {
-			return "", false
+// matchesAnyPath reports whether path equals, or is nested below, one of the patterns.
+func matchesAnyPath(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if path == pattern || strings.HasPrefix(path, pattern+".") {
+			return true
 		}
+	}
+
+	return false
+}
+
+type extrasLeaf struct {
+	path  string
+	value string
+}
+
+// extrasStringLeaves returns every non-empty string leaf of extras, sorted by dotted path.
+func extrasStringLeaves(extras map[string]any) []extrasLeaf {
+	var leaves []extrasLeaf
+
+	collectStringLeaves(&leaves, extras, "", 1)
 
-		current = next
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].path < leaves[j].path })
+
+	return leaves
+}
+
+func collectStringLeaves(leaves *[]extrasLeaf, node map[string]any, prefix string, depth int) {
+	for key, raw := range node {
+		path := prefix + key
+
+		switch value := raw.(type) {
+		case string:
+			trimmed := strings.TrimSpace(value)
+			if trimmed != "" {
+				*leaves = append(*leaves, extrasLeaf{path: path, value: trimmed})
+			}
+		case map[string]any:
+			if depth < maxExtrasDepth {
+				collectStringLeaves(leaves, value, path+".", depth+1)
+			}
+		}
 	}
+}
 
-	stringValue, ok := current.(string)
-	if !ok {
-		return "", false
+// normalizeContentType lowercases the media type and drops parameters, so
+// "Text/Markdown; charset=utf-8" and "text/markdown" map to the same annotation value.
+func normalizeContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(contentType)
 	}
 
-	stringValue = strings.TrimSpace(stringValue)
-	if stringValue == "" {
-		return "", false
+	return mediaType
+}
+
+// flattenedAnnotationName turns "client::notification.click.url" into
+// "gotify_extra_client_notification_click_url".
+func flattenedAnnotationName(path string) string {
+	return ExtraAnnotationPrefix + sanitizeAnnotationName(path)
+}
+
+// sanitizeAnnotationName replaces runs of characters outside [a-zA-Z0-9] with a single "_"
+// and trims leading/trailing underscores.
+func sanitizeAnnotationName(raw string) string {
+	var builder strings.Builder
+
+	pendingUnderscore := false
+
+	for _, char := range raw {
+		alphanumeric := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9')
+		if !alphanumeric {
+			pendingUnderscore = builder.Len() > 0
+
+			continue
+		}
+
+		if pendingUnderscore {
+			builder.WriteByte('_')
+
+			pendingUnderscore = false
+		}
+
+		builder.WriteRune(char)
 	}
 
-	return stringValue, true
+	return builder.String()
 }
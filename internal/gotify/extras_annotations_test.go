@@ -50,7 +50,7 @@ func TestExtrasAnnotationsExtractsWellKnownKeys(t *testing.T) {
 		},
 	}
 
-	annotations := gotify.ExtrasAnnotations(extras)
+	annotations := gotify.ExtrasAnnotations(extras, gotify.ExtrasPolicy{})
 
 	if got := annotations[gotify.AnnotationGotifyContentType]; got != "text/markdown" {
 		t.Fatalf("expected %q, got %q", "text/markdown", got)
@@ -89,7 +89,7 @@ func TestExtrasAnnotationsIgnoresNonStringValues(t *testing.T) {
 		},
 	}
 
-	annotations := gotify.ExtrasAnnotations(extras)
+	annotations := gotify.ExtrasAnnotations(extras, gotify.ExtrasPolicy{})
 	if len(annotations) != 0 {
 		t.Fatalf("expected no annotations, got %v", annotations)
 	}
@@ -98,8 +98,85 @@ func TestExtrasAnnotationsIgnoresNonStringValues(t *testing.T) {
 func TestExtrasAnnotationsEmptyExtras(t *testing.T) {
 	t.Parallel()
 
-	annotations := gotify.ExtrasAnnotations(nil)
+	annotations := gotify.ExtrasAnnotations(nil, gotify.ExtrasPolicy{})
 	if len(annotations) != 0 {
 		t.Fatalf("expected no annotations, got %v", annotations)
 	}
 }
+
+func TestExtrasAnnotationsPassthroughSanitizesNamesAndBoundsDepth(t *testing.T) {
+	t.Parallel()
+
+	extras := map[string]any{
+		"client::notification": map[string]any{
+			"click": map[string]any{"url": "https://example.local/details"},
+		},
+		"my-app::meta": map[string]any{
+			"ticket": "OPS-42",
+			"count":  3,
+			"a":      map[string]any{"b": map[string]any{"c": map[string]any{"d": "too deep"}}},
+		},
+	}
+
+	annotations := gotify.ExtrasAnnotations(extras, gotify.ExtrasPolicy{Passthrough: true})
+
+	assertAnnotations(t, annotations, map[string]string{
+		gotify.AnnotationGotifyClickURL:   "https://example.local/details",
+		"gotify_extra_my_app_meta_ticket": "OPS-42",
+	})
+}
+
+func TestExtrasAnnotationsAllowList(t *testing.T) {
+	t.Parallel()
+
+	annotations := gotify.ExtrasAnnotations(policyTestExtras(), gotify.ExtrasPolicy{
+		Allow: []string{"client::notification.click", "acme::ticket"},
+	})
+
+	assertAnnotations(t, annotations, map[string]string{
+		gotify.AnnotationGotifyClickURL:  "https://example.local/details",
+		"gotify_extra_acme_ticket_id":    "OPS-42",
+		"gotify_extra_acme_ticket_owner": "ops",
+	})
+}
+
+func TestExtrasAnnotationsDenyList(t *testing.T) {
+	t.Parallel()
+
+	annotations := gotify.ExtrasAnnotations(policyTestExtras(), gotify.ExtrasPolicy{
+		Deny:        []string{"client::notification.bigImageUrl", "acme::ticket.owner"},
+		Passthrough: true,
+	})
+
+	assertAnnotations(t, annotations, map[string]string{
+		gotify.AnnotationGotifyClickURL: "https://example.local/details",
+		"gotify_extra_acme_ticket_id":   "OPS-42",
+	})
+}
+
+func policyTestExtras() map[string]any {
+	return map[string]any{
+		"client::notification": map[string]any{
+			"click":       map[string]any{"url": "https://example.local/details"},
+			"bigImageUrl": "https://example.local/image.png",
+		},
+		"acme::ticket": map[string]any{
+			"id":    "OPS-42",
+			"owner": "ops",
+		},
+	}
+}
+
+func assertAnnotations(t *testing.T, got, want map[string]string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("expected %s=%q, got %q", key, value, got[key])
+		}
+	}
+}
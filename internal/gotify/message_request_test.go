@@ -25,7 +25,9 @@
 package gotify
 
 import (
+	"bytes"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -40,7 +42,7 @@ func TestParseMessageRequestJSONDefaultsPriority(t *testing.T) {
 	)
 	req.Header.Set("Content-Type", "application/json")
 
-	msg, err := ParseMessageRequest(req)
+	msg, err := ParseMessageRequest(req, ParseOptions{})
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -66,7 +68,7 @@ func TestParseMessageRequestJSONMissingMessage(t *testing.T) {
 	)
 	req.Header.Set("Content-Type", "application/json")
 
-	_, err := ParseMessageRequest(req)
+	_, err := ParseMessageRequest(req, ParseOptions{})
 	if !errors.Is(err, ErrMessageRequired) {
 		t.Fatalf("expected ErrMessageRequired, got: %v", err)
 	}
@@ -80,7 +82,7 @@ func TestParseMessageRequestForm(t *testing.T) {
 	)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	msg, err := ParseMessageRequest(req)
+	msg, err := ParseMessageRequest(req, ParseOptions{})
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -106,7 +108,7 @@ func TestParseMessageRequestUnsupportedContentType(t *testing.T) {
 	)
 	req.Header.Set("Content-Type", "text/plain")
 
-	_, err := ParseMessageRequest(req)
+	_, err := ParseMessageRequest(req, ParseOptions{})
 	if !errors.Is(err, ErrUnsupportedContentType) {
 		t.Fatalf("expected ErrUnsupportedContentType, got: %v", err)
 	}
@@ -122,7 +124,7 @@ func TestParseMessageRequestJSONNegativePriority(t *testing.T) {
 	)
 	request.Header.Set("Content-Type", "application/json")
 
-	_, err := ParseMessageRequest(request)
+	_, err := ParseMessageRequest(request, ParseOptions{})
 	if !errors.Is(err, ErrInvalidPriority) {
 		t.Fatalf("expected ErrInvalidPriority, got: %v", err)
 	}
@@ -138,8 +140,141 @@ func TestParseMessageRequestFormNegativePriority(t *testing.T) {
 	)
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	_, err := ParseMessageRequest(request)
+	_, err := ParseMessageRequest(request, ParseOptions{})
 	if !errors.Is(err, ErrInvalidPriority) {
 		t.Fatalf("expected ErrInvalidPriority, got: %v", err)
 	}
 }
+
+func TestParseMessageRequestMultipartIgnoresFileParts(t *testing.T) {
+	t.Parallel()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	for field, value := range map[string]string{"message": "hello", "title": "test", "priority": "7"} {
+		err := writer.WriteField(field, value)
+		if err != nil {
+			t.Fatalf("write field %q: %v", field, err)
+		}
+	}
+
+	filePart, err := writer.CreateFormFile("image", "screenshot.png")
+	if err != nil {
+		t.Fatalf("create file part: %v", err)
+	}
+
+	_, err = filePart.Write([]byte("\x89PNG fake image bytes"))
+	if err != nil {
+		t.Fatalf("write file part: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("close multipart writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	msg, err := ParseMessageRequest(req, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if msg.Message != "hello" || msg.Title != "test" || msg.Priority != 7 {
+		t.Fatalf("expected hello/test/7, got %q/%q/%d", msg.Message, msg.Title, msg.Priority)
+	}
+}
+
+func TestParseMessageRequestFormExtras(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message",
+		strings.NewReader(`message=hello&extras=%7B%22client%3A%3Adisplay%22%3A%7B%22contentType%22%3A%22text%2Fmarkdown%22%7D%7D`),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	msg, err := ParseMessageRequest(req, ParseOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	display, ok := msg.Extras["client::display"].(map[string]any)
+	if !ok || display["contentType"] != "text/markdown" {
+		t.Fatalf("expected form extras to be parsed, got %v", msg.Extras)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "http://example.local/message",
+		strings.NewReader(`message=hello&extras=not-json`),
+	)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	_, err = ParseMessageRequest(req, ParseOptions{})
+	if !errors.Is(err, ErrInvalidExtras) {
+		t.Fatalf("expected ErrInvalidExtras, got: %v", err)
+	}
+}
+
+func TestParseMessageRequestWithoutContentType(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "http://example.local/message",
+			strings.NewReader(`{"message":"hello","priority":7}`),
+		)
+	}
+
+	msg, err := ParseMessageRequest(newRequest(), ParseOptions{DefaultJSON: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if msg.Message != "hello" || msg.Priority != 7 {
+		t.Fatalf("expected message %q with priority 7, got %q with priority %d", "hello", msg.Message, msg.Priority)
+	}
+
+	_, err = ParseMessageRequest(newRequest(), ParseOptions{})
+	if !errors.Is(err, ErrMessageRequired) {
+		t.Fatalf("expected form fallback to reject a JSON body with ErrMessageRequired, got: %v", err)
+	}
+}
+
+func TestParseMessageRequestMaxPriority(t *testing.T) {
+	t.Parallel()
+
+	ten, zero := 10, 0
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		maxPriority *int
+		wantErr     bool
+	}{
+		{name: "json at max", contentType: "application/json", body: `{"message":"m","priority":10}`, maxPriority: &ten},
+		{name: "json above max", contentType: "application/json", body: `{"message":"m","priority":11}`, maxPriority: &ten, wantErr: true},
+		{name: "form at max", contentType: "application/x-www-form-urlencoded", body: "message=m&priority=10", maxPriority: &ten},
+		{
+			name: "form above max", contentType: "application/x-www-form-urlencoded",
+			body: "message=m&priority=11", maxPriority: &ten, wantErr: true,
+		},
+		{name: "explicit zero", contentType: "application/json", body: `{"message":"m","priority":1}`, maxPriority: &zero, wantErr: true},
+		{name: "unlimited", contentType: "application/json", body: `{"message":"m","priority":11}`},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", testCase.contentType)
+
+		_, err := ParseMessageRequest(req, ParseOptions{MaxPriority: testCase.maxPriority})
+		if testCase.wantErr != errors.Is(err, ErrInvalidPriority) {
+			t.Fatalf("%s: expected ErrInvalidPriority=%v, got: %v", testCase.name, testCase.wantErr, err)
+		}
+
+		if !testCase.wantErr && err != nil {
+			t.Fatalf("%s: expected no error, got: %v", testCase.name, err)
+		}
+	}
+}
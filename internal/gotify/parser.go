@@ -27,6 +27,7 @@ package gotify
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"mime"
 	"net/http"
 	"strconv"
@@ -35,6 +36,31 @@ import (
 
 const DefaultPriority = 5
 
+// multipartMaxMemory bounds how much of a multipart body is buffered in memory;
+// larger file parts spill to temporary files and are removed after parsing.
+const multipartMaxMemory = 1 << 20
+
+const (
+	mediaTypeJSON   = "application/json"
+	mediaTypeForm   = "application/x-www-form-urlencoded"
+	mediaTypeNDJSON = "application/x-ndjson"
+)
+
+// ParseOptions tunes ParseMessageRequest; the zero value keeps Gotify's behavior.
+type ParseOptions struct {
+	// DefaultJSON parses requests without a Content-Type header as JSON instead of as a
+	// URL-encoded form.
+	DefaultJSON bool
+
+	// MaxPriority rejects priorities above it with ErrInvalidPriority (Gotify defines 0-10);
+	// nil accepts any non-negative priority.
+	MaxPriority *int
+
+	// MaxBatchLines rejects NDJSON batches with more messages with ErrBatchTooLarge;
+	// 0 means no limit.
+	MaxBatchLines int
+}
+
 type jsonMessagePayload struct {
 	Message  string         `json:"message"`
 	Title    string         `json:"title"`
@@ -42,48 +68,68 @@ type jsonMessagePayload struct {
 	Extras   map[string]any `json:"extras,omitempty"`
 }
 
-// ParseMessageRequest parses a Gotify-like message request. It supports JSON and URL-encoded forms.
-func ParseMessageRequest(request *http.Request) (MessageRequest, error) {
+// ParseMessageRequest parses a Gotify-like message request.
+// It supports JSON, URL-encoded forms and multipart forms (file parts are ignored).
+func ParseMessageRequest(request *http.Request, opts ParseOptions) (MessageRequest, error) {
 	if request == nil {
 		return MessageRequest{}, fmt.Errorf("parse request: %w", ErrUnsupportedContentType)
 	}
 
-	contentType := request.Header.Get("Content-Type")
-	mediaType := ""
-
-	if contentType != "" {
-		parsedType, _, err := mime.ParseMediaType(contentType)
-		if err != nil {
-			return MessageRequest{}, fmt.Errorf(
-				"parse content-type %q: %w",
-				contentType,
-				ErrUnsupportedContentType,
-			)
-		}
-
-		mediaType = strings.ToLower(strings.TrimSpace(parsedType))
+	mediaType, err := requestMediaType(request, opts)
+	if err != nil {
+		return MessageRequest{}, err
 	}
 
-	// Default when header is absent:
-	// many clients send x-www-form-urlencoded without explicit content-type,
-	// but we keep it strict: if no content-type, try form parsing first.
 	switch mediaType {
-	case "application/json":
-		return parseJSON(request)
+	case mediaTypeJSON:
+		return parseJSON(request, opts)
+
+	case mediaTypeForm:
+		return parseForm(request, opts)
 
-	case "application/x-www-form-urlencoded", "":
-		return parseForm(request)
+	case "multipart/form-data":
+		return parseMultipartForm(request, opts)
 
 	default:
 		return MessageRequest{}, fmt.Errorf("%w: %q", ErrUnsupportedContentType, mediaType)
 	}
 }
 
-func parseJSON(request *http.Request) (MessageRequest, error) {
+// requestMediaType returns the lower-cased media type of request, falling back to form
+// (or JSON with opts.DefaultJSON) when the Content-Type header is absent.
+func requestMediaType(request *http.Request, opts ParseOptions) (string, error) {
+	contentType := request.Header.Get("Content-Type")
+	if contentType == "" {
+		// Most clients (curl -d, Gotify's own CLI) send a form body without the header;
+		// opts.DefaultJSON covers clients that post JSON without it.
+		if opts.DefaultJSON {
+			return mediaTypeJSON, nil
+		}
+
+		return mediaTypeForm, nil
+	}
+
+	parsedType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("parse content-type %q: %w", contentType, ErrUnsupportedContentType)
+	}
+
+	return strings.ToLower(strings.TrimSpace(parsedType)), nil
+}
+
+func parseJSON(request *http.Request, opts ParseOptions) (MessageRequest, error) {
+	return decodeJSONMessage(request.Body, opts)
+}
+
+func decodeJSONMessage(reader io.Reader, opts ParseOptions) (MessageRequest, error) {
 	var payload jsonMessagePayload
 
-	decoder := json.NewDecoder(request.Body)
-	// Compatibility: do NOT DisallowUnknownFields (Gotify clients may send extras, etc.)
+	decoder := json.NewDecoder(reader)
+	// Compatibility: do NOT DisallowUnknownFields (Gotify clients may send extras, etc.).
+	// Unknown top-level fields are dropped, as Gotify does.
+	// Numbers stay json.Number so extras are echoed back verbatim (no float64 rounding).
+	decoder.UseNumber()
+
 	err := decoder.Decode(&payload)
 	if err != nil {
 		return MessageRequest{}, fmt.Errorf("decode json: %w", err)
@@ -101,15 +147,34 @@ func parseJSON(request *http.Request) (MessageRequest, error) {
 		Extras:   payload.Extras,
 	}
 
-	return validate(msg)
+	return validate(msg, opts)
 }
 
-func parseForm(request *http.Request) (MessageRequest, error) {
+func parseForm(request *http.Request, opts ParseOptions) (MessageRequest, error) {
 	err := request.ParseForm()
 	if err != nil {
 		return MessageRequest{}, fmt.Errorf("parse form: %w", err)
 	}
 
+	return messageFromForm(request, opts)
+}
+
+func parseMultipartForm(request *http.Request, opts ParseOptions) (MessageRequest, error) {
+	err := request.ParseMultipartForm(multipartMaxMemory)
+	if err != nil {
+		return MessageRequest{}, fmt.Errorf("parse multipart form: %w", err)
+	}
+
+	defer func() {
+		// Best-effort cleanup of file parts spilled to disk.
+		_ = request.MultipartForm.RemoveAll()
+	}()
+
+	return messageFromForm(request, opts)
+}
+
+// messageFromForm reads message fields from an already parsed form.
+func messageFromForm(request *http.Request, opts ParseOptions) (MessageRequest, error) {
 	message := strings.TrimSpace(request.FormValue("message"))
 	title := strings.TrimSpace(request.FormValue("title"))
 	priority := DefaultPriority
@@ -124,17 +189,42 @@ func parseForm(request *http.Request) (MessageRequest, error) {
 		priority = parsed
 	}
 
+	extras, err := parseFormExtras(request.FormValue("extras"))
+	if err != nil {
+		return MessageRequest{}, err
+	}
+
 	msg := MessageRequest{
 		Message:  message,
 		Title:    title,
 		Priority: priority,
-		Extras:   nil,
+		Extras:   extras,
 	}
 
-	return validate(msg)
+	return validate(msg, opts)
 }
 
-func validate(msg MessageRequest) (MessageRequest, error) {
+// parseFormExtras decodes the optional "extras" form field, a JSON object (Gotify only
+// accepts extras in JSON bodies; this lets form clients send them too).
+func parseFormExtras(raw string) (map[string]any, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil //nolint:nilnil // no extras sent.
+	}
+
+	var extras map[string]any
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&extras)
+	if err != nil || extras == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExtras, raw)
+	}
+
+	return extras, nil
+}
+
+func validate(msg MessageRequest, opts ParseOptions) (MessageRequest, error) {
 	if strings.TrimSpace(msg.Message) == "" {
 		return MessageRequest{}, ErrMessageRequired
 	}
@@ -143,5 +233,9 @@ func validate(msg MessageRequest) (MessageRequest, error) {
 		return MessageRequest{}, fmt.Errorf("%w: %d", ErrInvalidPriority, msg.Priority)
 	}
 
+	if opts.MaxPriority != nil && msg.Priority > *opts.MaxPriority {
+		return MessageRequest{}, fmt.Errorf("%w: %d is above the maximum %d", ErrInvalidPriority, msg.Priority, *opts.MaxPriority)
+	}
+
 	return msg, nil
 }
@@ -44,3 +44,22 @@ type MessageResponse struct {
 	Date     time.Time      `json:"date"`
 	Extras   map[string]any `json:"extras,omitempty"`
 }
+
+// Application is a Gotify-ish entry of GET /application. Token is always empty:
+// tokens are never echoed back.
+type Application struct {
+	ID              uint32 `json:"id"`
+	Token           string `json:"token"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Internal        bool   `json:"internal"`
+	Image           string `json:"image"`
+	DefaultPriority int    `json:"defaultPriority"`
+}
+
+// User is a Gotify-ish GET /current/user response.
+type User struct {
+	ID    uint32 `json:"id"`
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
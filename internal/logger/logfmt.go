@@ -0,0 +1,60 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// newLogfmtHandler returns a handler writing strict logfmt: time= (optional), level=, msg=
+// and one key=value pair per attribute, with group keys joined by dots. Values containing
+// spaces, quotes, '=' or control characters are quoted with Go escaping; keys are reduced to
+// characters that never need quoting.
+func newLogfmtHandler(output io.Writer, level slog.Leveler, includeTime bool) slog.Handler {
+	base := replaceAttr(includeTime)
+
+	return slog.NewTextHandler(output, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			attr = base(groups, attr)
+			attr.Key = logfmtKey(attr.Key)
+
+			return attr
+		},
+	})
+}
+
+// logfmtKey replaces the characters logfmt does not allow in bare keys with '_'.
+func logfmtKey(key string) string {
+	return strings.Map(func(char rune) rune {
+		if char <= ' ' || char == '=' || char == '"' || char == 0x7f {
+			return '_'
+		}
+
+		return char
+	}, key)
+}
@@ -25,6 +25,7 @@
 package logger
 
 import (
+	"io"
 	"log/slog"
 	"os"
 	"strings"
@@ -44,7 +45,8 @@ func L() *slog.Logger {
 	initOnce.Do(func() {
 		if globalLogger == nil {
 			handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-				Level: slog.LevelInfo,
+				Level:       slog.LevelInfo,
+				ReplaceAttr: replaceAttr(true),
 			})
 			globalLogger = slog.New(handler)
 		}
@@ -58,27 +60,33 @@ func Set(newLogger *slog.Logger) {
 	globalLogger = newLogger
 }
 
-// Configure builds and installs a slog.Logger based on CLI flags.
-// format: "json" or "text" (unknown -> text)
+// Configure builds and installs a slog.Logger writing to output (os.Stdout when nil).
+// format: "json", "plain", "logfmt" or "text" (unknown -> text)
 // level:  "debug", "info", "warn", "error", "fatal", "panic" (fatal/panic -> error)
 // includeTime: if false, the time attribute is removed from log records.
-func Configure(format, level string, includeTime bool) *slog.Logger {
+func Configure(output io.Writer, format, level string, includeTime bool) *slog.Logger {
 	logLevel := parseLevel(level)
 
+	if output == nil {
+		output = os.Stdout
+	}
+
 	var handler slog.Handler
 
 	switch strings.ToLower(format) {
 	case "json":
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		handler = slog.NewJSONHandler(output, &slog.HandlerOptions{
 			Level:       logLevel,
-			ReplaceAttr: timeStripper(includeTime),
+			ReplaceAttr: replaceAttr(includeTime),
 		})
 	case "plain":
-		handler = newPlainTextHandler(os.Stdout, logLevel, includeTime)
+		handler = newPlainTextHandler(output, logLevel, includeTime)
+	case "logfmt":
+		handler = newLogfmtHandler(output, logLevel, includeTime)
 	default: // "text"
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		handler = slog.NewTextHandler(output, &slog.HandlerOptions{
 			Level:       logLevel,
-			ReplaceAttr: timeStripper(includeTime),
+			ReplaceAttr: replaceAttr(includeTime),
 		})
 	}
 
@@ -88,19 +96,15 @@ func Configure(format, level string, includeTime bool) *slog.Logger {
 	return configured
 }
 
-// timeStripper returns a ReplaceAttr function that removes the time attribute
-// when includeTime is false. When includeTime is true, it returns nil (no-op).
-func timeStripper(includeTime bool) func([]string, slog.Attr) slog.Attr {
-	if includeTime {
-		return nil
-	}
-
+// replaceAttr returns a ReplaceAttr function that redacts secrets and, when includeTime
+// is false, removes the time attribute.
+func replaceAttr(includeTime bool) func([]string, slog.Attr) slog.Attr {
 	return func(_ []string, attr slog.Attr) slog.Attr {
-		if attr.Key == slog.TimeKey {
+		if !includeTime && attr.Key == slog.TimeKey {
 			return slog.Attr{} // drop time
 		}
 
-		return attr
+		return redactAttr(attr)
 	}
 }
 
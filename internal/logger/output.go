@@ -0,0 +1,107 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+
+	logFileMode = 0o600
+	logDirMode  = 0o750
+)
+
+var ErrOutputOpen = errors.New("cannot open log output")
+
+// Output selects where log records are written. Path is "stdout" (default), "stderr" or a
+// file path; files are rotated once they reach MaxSizeMB (lumberjack's 100 MB when 0),
+// keeping at most MaxBackups old files (0 keeps all) for MaxAgeDays (0 keeps them forever).
+type Output struct {
+	Path       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
+
+// rotatingFile is the file opened by the last OpenOutput, closed when a new one replaces it.
+var (
+	rotatingFileMu sync.Mutex
+	rotatingFile   *lumberjack.Logger
+)
+
+// OpenOutput returns the writer for output. For a file, it checks up front that the file can
+// be created so a bad path fails at startup instead of silently dropping every record.
+func OpenOutput(output Output) (io.Writer, error) {
+	switch strings.ToLower(strings.TrimSpace(output.Path)) {
+	case "", OutputStdout:
+		return os.Stdout, nil
+	case OutputStderr:
+		return os.Stderr, nil
+	}
+
+	path := filepath.Clean(strings.TrimSpace(output.Path))
+
+	err := os.MkdirAll(filepath.Dir(path), logDirMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOutputOpen, err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, logFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOutputOpen, err)
+	}
+
+	_ = file.Close()
+
+	rotating := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    output.MaxSizeMB,
+		MaxBackups: output.MaxBackups,
+		MaxAge:     output.MaxAgeDays,
+	}
+
+	rotatingFileMu.Lock()
+	previous := rotatingFile
+	rotatingFile = rotating
+	rotatingFileMu.Unlock()
+
+	if previous != nil {
+		// A late write through the old logger simply reopens the file.
+		_ = previous.Close()
+	}
+
+	return rotating, nil
+}
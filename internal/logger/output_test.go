@@ -0,0 +1,93 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenOutputStandardStreams(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]*os.File{
+		"":         os.Stdout,
+		"stdout":   os.Stdout,
+		" STDERR ": os.Stderr,
+	}
+
+	for path, want := range cases {
+		writer, err := OpenOutput(Output{Path: path})
+		if err != nil {
+			t.Fatalf("expected no error for %q, got %v", path, err)
+		}
+
+		if writer != want {
+			t.Fatalf("expected %s for %q, got %v", want.Name(), path, writer)
+		}
+	}
+}
+
+func TestOpenOutputWritesToRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "gotilert.log")
+
+	writer, err := OpenOutput(Output{Path: path, MaxSizeMB: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	slog.New(newPlainTextHandler(writer, slog.LevelInfo, false)).Info("hello file")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "hello file") {
+		t.Fatalf("expected record in log file, got %q", content)
+	}
+}
+
+func TestOpenOutputRejectsUnwritablePath(t *testing.T) {
+	t.Parallel()
+
+	parent := filepath.Join(t.TempDir(), "not-a-dir")
+
+	err := os.WriteFile(parent, nil, 0o600)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	_, err = OpenOutput(Output{Path: filepath.Join(parent, "gotilert.log")})
+	if !errors.Is(err, ErrOutputOpen) {
+		t.Fatalf("expected ErrOutputOpen, got %v", err)
+	}
+}
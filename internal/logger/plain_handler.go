@@ -104,14 +104,14 @@ func (handler *PlainTextHandler) Handle(_ context.Context, record slog.Record) e
 	// Pre-resolved prefix attrs (from With)
 	if len(handler.prefixAttributes) > 0 {
 		for index := range handler.prefixAttributes {
-			writeAttrKV(&buffer, qualify(handler.groups, handler.prefixAttributes[index]))
+			writeAttrKV(&buffer, redactAttr(qualify(handler.groups, handler.prefixAttributes[index])))
 		}
 	}
 
 	// Record attrs
 	if record.NumAttrs() > 0 {
 		record.Attrs(func(attribute slog.Attr) bool {
-			writeAttrKV(&buffer, qualify(handler.groups, attribute))
+			writeAttrKV(&buffer, redactAttr(qualify(handler.groups, attribute)))
 
 			return true
 		})
@@ -0,0 +1,114 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedMask replaces secrets and URL userinfo in log output.
+const redactedMask = "***"
+
+// secretKeyFragments mark attribute keys whose values are never logged.
+var secretKeyFragments = []string{"password", "passwd", "secret", "token", "bearer", "authorization", "apikey"}
+
+// urlUserinfoPattern matches the userinfo part of URLs embedded in free text (e.g. error messages).
+var urlUserinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/?#\s@"']+@`)
+
+// RedactURL replaces the userinfo of rawURL, if any, with "***" (https://***@host/path).
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return RedactText(rawURL)
+	}
+
+	if parsed.User == nil {
+		return rawURL
+	}
+
+	parsed.User = nil
+
+	return strings.Replace(parsed.String(), "://", "://"+redactedMask+"@", 1)
+}
+
+// RedactURLs applies RedactURL to every element.
+func RedactURLs(rawURLs []string) []string {
+	redacted := make([]string, 0, len(rawURLs))
+	for _, rawURL := range rawURLs {
+		redacted = append(redacted, RedactURL(rawURL))
+	}
+
+	return redacted
+}
+
+// RedactText strips URL userinfo from free text such as wrapped error messages.
+func RedactText(text string) string {
+	return urlUserinfoPattern.ReplaceAllString(text, "${1}"+redactedMask+"@")
+}
+
+// redactAttr is applied by every handler: secret-looking keys are masked and URL userinfo is
+// stripped from string and error values, so a careless log call cannot leak credentials.
+func redactAttr(attr slog.Attr) slog.Attr {
+	if isSecretKey(attr.Key) {
+		if attr.Value.Kind() == slog.KindString && attr.Value.String() == "" {
+			return attr
+		}
+
+		return slog.String(attr.Key, redactedMask)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		text := attr.Value.String()
+		if redacted := RedactText(text); redacted != text {
+			return slog.String(attr.Key, redacted)
+		}
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			text := err.Error()
+			if redacted := RedactText(text); redacted != text {
+				return slog.String(attr.Key, redacted)
+			}
+		}
+	default:
+	}
+
+	return attr
+}
+
+func isSecretKey(key string) bool {
+	lowered := strings.ToLower(key)
+
+	for _, fragment := range secretKeyFragments {
+		if strings.Contains(lowered, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultSamplingTick is the window after which per-message counters reset.
+const defaultSamplingTick = time.Second
+
+// Sampling caps repeated log records: per message and level, the first Initial records in
+// each Tick are logged, then only every Thereafter-th one (0 drops the rest of the window).
+// Warnings and errors are never sampled.
+type Sampling struct {
+	Initial    int
+	Thereafter int
+	// Tick is the counting window; 0 means one second.
+	Tick time.Duration
+}
+
+// Enabled reports whether the sampling settings drop anything at all.
+func (sampling Sampling) Enabled() bool {
+	return sampling.Initial > 0
+}
+
+// samplingHandler wraps a slog.Handler and drops records exceeding the sampling budget.
+// Handlers derived through WithAttrs/WithGroup share the same counters.
+type samplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+type samplingState struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+	now        func() time.Time
+
+	mutex       sync.Mutex
+	windowStart time.Time
+	counts      map[samplingKey]int
+}
+
+type samplingKey struct {
+	level   slog.Level
+	message string
+}
+
+// NewSamplingHandler returns next wrapped in a sampler, or next itself when sampling is off.
+func NewSamplingHandler(next slog.Handler, sampling Sampling) slog.Handler {
+	if !sampling.Enabled() {
+		return next
+	}
+
+	tick := sampling.Tick
+	if tick <= 0 {
+		tick = defaultSamplingTick
+	}
+
+	return &samplingHandler{
+		next: next,
+		state: &samplingState{
+			initial:    sampling.Initial,
+			thereafter: sampling.Thereafter,
+			tick:       tick,
+			now:        time.Now,
+			counts:     make(map[samplingKey]int),
+		},
+	}
+}
+
+// EnableSampling wraps the current global logger's handler in a sampler.
+func EnableSampling(sampling Sampling) {
+	if !sampling.Enabled() {
+		return
+	}
+
+	Set(slog.New(NewSamplingHandler(L().Handler(), sampling)))
+}
+
+func (handler *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.next.Enabled(ctx, level)
+}
+
+func (handler *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelWarn && !handler.state.allow(record.Level, record.Message) {
+		return nil
+	}
+
+	return handler.next.Handle(ctx, record) //nolint:wrapcheck // transparent decorator.
+}
+
+func (handler *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: handler.next.WithAttrs(attrs), state: handler.state}
+}
+
+func (handler *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: handler.next.WithGroup(name), state: handler.state}
+}
+
+func (state *samplingState) allow(level slog.Level, message string) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	now := state.now()
+	if now.Sub(state.windowStart) >= state.tick {
+		state.windowStart = now
+		clear(state.counts)
+	}
+
+	key := samplingKey{level: level, message: message}
+	state.counts[key]++
+	count := state.counts[key]
+
+	if count <= state.initial {
+		return true
+	}
+
+	return state.thereafter > 0 && (count-state.initial)%state.thereafter == 0
+}
@@ -0,0 +1,88 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandlerKeepsInitialThenEveryNth(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	now := time.Unix(0, 0)
+	handler := NewSamplingHandler(slog.NewTextHandler(&buf, nil), Sampling{Initial: 2, Thereafter: 3})
+
+	sampler, ok := handler.(*samplingHandler)
+	if !ok {
+		t.Fatalf("expected a sampling handler, got %T", handler)
+	}
+
+	sampler.state.now = func() time.Time { return now }
+
+	// Derived loggers share the budget.
+	log := slog.New(handler).With("component", "test")
+
+	for range 10 {
+		log.Info("http request")
+	}
+
+	log.Info("other message")
+	log.Warn("http request")
+
+	if got := strings.Count(buf.String(), `msg="http request"`); got != 5 {
+		t.Fatalf("expected 2 initial + 2 sampled info + 1 warn records, got %d:\n%s", got, buf.String())
+	}
+
+	if !strings.Contains(buf.String(), `msg="other message"`) {
+		t.Fatalf("expected other messages to have their own budget, got:\n%s", buf.String())
+	}
+
+	// A new window resets the counters.
+	buf.Reset()
+
+	now = now.Add(time.Second)
+
+	log.Info("http request")
+
+	if !strings.Contains(buf.String(), `msg="http request"`) {
+		t.Fatalf("expected the counter to reset after a tick, got:\n%s", buf.String())
+	}
+}
+
+func TestNewSamplingHandlerDisabledReturnsNext(t *testing.T) {
+	t.Parallel()
+
+	next := slog.NewTextHandler(&bytes.Buffer{}, nil)
+
+	if NewSamplingHandler(next, Sampling{}) != slog.Handler(next) {
+		t.Fatalf("expected the handler to be returned unchanged when sampling is off")
+	}
+}
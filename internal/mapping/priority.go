@@ -0,0 +1,168 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package mapping resolves priority-keyed settings (severity, TTL, ...) for a Gotify priority.
+package mapping
+
+// DefaultSeverity is what Severity returns for an empty mapping.
+const DefaultSeverity = "info"
+
+// Match selects how a priority without an exact key resolves (defaults.priorityMatch).
+type Match string
+
+const (
+	// MatchFloor uses the closest lower key, else the smallest key (see ClosestKey).
+	MatchFloor Match = "floor"
+	// MatchCeil uses the closest higher key, else the largest key.
+	MatchCeil Match = "ceil"
+	// MatchNearest uses the key with the smallest distance; ties go to the lower key.
+	MatchNearest Match = "nearest"
+)
+
+// Matches lists the valid Match values.
+func Matches() []Match {
+	return []Match{MatchFloor, MatchCeil, MatchNearest}
+}
+
+// Key returns the key priority resolves to under match (an unknown match behaves like
+// MatchFloor). ok is false only for an empty mapping.
+func Key[V any](mapping map[int]V, priority int, match Match) (int, bool) {
+	switch match {
+	case MatchCeil:
+		return ceilKey(mapping, priority)
+	case MatchNearest:
+		return nearestKey(mapping, priority)
+	case MatchFloor:
+		return ClosestKey(mapping, priority)
+	default:
+		return ClosestKey(mapping, priority)
+	}
+}
+
+// ClosestKey returns the key of mapping that priority resolves to:
+//
+//   - exact match: priority itself, when it is a key;
+//   - closest lower: otherwise the largest key below priority;
+//   - below all: otherwise (every key is above priority) the smallest key.
+//
+// The result does not depend on map iteration order. ok is false only for an empty mapping.
+func ClosestKey[V any](mapping map[int]V, priority int) (int, bool) {
+	key, ok := FloorKey(mapping, priority)
+	if ok {
+		return key, true
+	}
+
+	smallest, found := 0, false
+
+	for key := range mapping {
+		if !found || key < smallest {
+			smallest, found = key, true
+		}
+	}
+
+	return smallest, found
+}
+
+// FloorKey returns the largest key <= priority (exact match or closest lower).
+// ok is false when every key is above priority.
+func FloorKey[V any](mapping map[int]V, priority int) (int, bool) {
+	if _, ok := mapping[priority]; ok {
+		return priority, true
+	}
+
+	best, found := 0, false
+
+	for key := range mapping {
+		if key < priority && (!found || key > best) {
+			best, found = key, true
+		}
+	}
+
+	return best, found
+}
+
+// ceilKey returns priority when mapped, else the smallest key above it, else the largest key.
+func ceilKey[V any](mapping map[int]V, priority int) (int, bool) {
+	if _, ok := mapping[priority]; ok {
+		return priority, true
+	}
+
+	best, found := 0, false
+	largest, hasLargest := 0, false
+
+	for key := range mapping {
+		if key > priority && (!found || key < best) {
+			best, found = key, true
+		}
+
+		if !hasLargest || key > largest {
+			largest, hasLargest = key, true
+		}
+	}
+
+	if found {
+		return best, true
+	}
+
+	return largest, hasLargest
+}
+
+// nearestKey returns the key closest to priority; on a tie the lower key wins.
+func nearestKey[V any](mapping map[int]V, priority int) (int, bool) {
+	best, found := 0, false
+
+	for key := range mapping {
+		if !found {
+			best, found = key, true
+
+			continue
+		}
+
+		distance, bestDistance := absDiff(key, priority), absDiff(best, priority)
+		if distance < bestDistance || (distance == bestDistance && key < best) {
+			best = key
+		}
+	}
+
+	return best, found
+}
+
+func absDiff(a, b int) int {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
+
+// Severity returns the severity priority maps to through Key, or DefaultSeverity when
+// mapping is empty. mapping is expected to be normalized by config validation.
+func Severity(mapping map[int]string, priority int, match Match) string {
+	key, ok := Key(mapping, priority, match)
+	if !ok {
+		return DefaultSeverity
+	}
+
+	return mapping[key]
+}
@@ -0,0 +1,158 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package mapping_test
+
+import (
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/mapping"
+)
+
+func TestSeverityExactMatch(t *testing.T) {
+	t.Parallel()
+
+	severities := map[int]string{
+		0: "info",
+		2: "warning",
+		5: "critical",
+	}
+
+	got := mapping.Severity(severities, 2, mapping.MatchFloor)
+	if got != "warning" {
+		t.Fatalf("expected %q, got %q", "warning", got)
+	}
+}
+
+func TestSeverityClosestLower(t *testing.T) {
+	t.Parallel()
+
+	severities := map[int]string{
+		0: "info",
+		2: "warning",
+		5: "critical",
+	}
+
+	got := mapping.Severity(severities, 3, mapping.MatchFloor)
+	if got != "warning" {
+		t.Fatalf("expected %q, got %q", "warning", got)
+	}
+}
+
+func TestSeverityBelowAllChoosesSmallestKey(t *testing.T) {
+	t.Parallel()
+
+	severities := map[int]string{
+		5:  "critical",
+		10: "warning",
+	}
+
+	got := mapping.Severity(severities, 1, mapping.MatchFloor)
+	if got != "critical" {
+		t.Fatalf("expected %q, got %q", "critical", got)
+	}
+}
+
+func TestSeverityEmptyMappingFallsBackToDefault(t *testing.T) {
+	t.Parallel()
+
+	got := mapping.Severity(nil, 3, mapping.MatchFloor)
+	if got != mapping.DefaultSeverity {
+		t.Fatalf("expected %q, got %q", mapping.DefaultSeverity, got)
+	}
+}
+
+func TestFloorKeyHasNoBelowAllFallback(t *testing.T) {
+	t.Parallel()
+
+	ttls := map[int]int{5: 1, 10: 2}
+
+	_, ok := mapping.FloorKey(ttls, 1)
+	if ok {
+		t.Fatalf("expected no floor key below every key")
+	}
+
+	key, ok := mapping.FloorKey(ttls, 7)
+	if !ok || key != 5 {
+		t.Fatalf("expected floor key 5, got %d (ok=%v)", key, ok)
+	}
+}
+
+func TestSeverityPriorityMatchModes(t *testing.T) {
+	t.Parallel()
+
+	severities := map[int]string{
+		0:  "info",
+		4:  "warning",
+		10: "critical",
+	}
+
+	cases := []struct {
+		match    mapping.Match
+		priority int
+		want     string
+	}{
+		{match: mapping.MatchFloor, priority: 4, want: "warning"},
+		{match: mapping.MatchFloor, priority: 9, want: "warning"},
+		{match: mapping.MatchCeil, priority: 4, want: "warning"},
+		{match: mapping.MatchCeil, priority: 5, want: "critical"},
+		{match: mapping.MatchCeil, priority: 11, want: "critical"},
+		{match: mapping.MatchNearest, priority: 3, want: "warning"},
+		{match: mapping.MatchNearest, priority: 2, want: "info"}, // tie: lower key wins
+		{match: mapping.MatchNearest, priority: 8, want: "critical"},
+		{match: mapping.MatchNearest, priority: 7, want: "warning"}, // tie: lower key wins
+	}
+
+	for _, testCase := range cases {
+		got := mapping.Severity(severities, testCase.priority, testCase.match)
+		if got != testCase.want {
+			t.Fatalf("%s(%d): expected %q, got %q", testCase.match, testCase.priority, testCase.want, got)
+		}
+	}
+}
+
+func TestSeverityBelowAllDependsOnMatch(t *testing.T) {
+	t.Parallel()
+
+	severities := map[int]string{
+		5:  "warning",
+		10: "critical",
+	}
+
+	for match, want := range map[mapping.Match]string{
+		mapping.MatchFloor:   "warning",
+		mapping.MatchCeil:    "warning",
+		mapping.MatchNearest: "warning",
+	} {
+		got := mapping.Severity(severities, 1, match)
+		if got != want {
+			t.Fatalf("%s: expected %q, got %q", match, want, got)
+		}
+	}
+
+	got := mapping.Severity(severities, 20, mapping.MatchFloor)
+	if got != "critical" {
+		t.Fatalf("expected %q above all keys, got %q", "critical", got)
+	}
+}
@@ -26,10 +26,12 @@ package metrics
 
 import (
 	"net/http"
+	"runtime"
 	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -41,8 +43,97 @@ type Metrics struct {
 
 	forwardedAlertsTotal  *prometheus.CounterVec
 	upstreamFailuresTotal *prometheus.CounterVec
+	forwardDuration       *prometheus.HistogramVec
+	upstreamRetriesTotal  *prometheus.CounterVec
+
+	alertmanagerPostsTotal *prometheus.CounterVec
+	batchSize              prometheus.Histogram
+
+	alertmanagerReady  prometheus.Gauge
+	readyCheckDuration prometheus.Histogram
+	circuitState       *prometheus.GaugeVec
+
+	forwardQueueDepth   prometheus.Gauge
+	forwardQueueDropped *prometheus.CounterVec
+	forwardInflight     prometheus.Gauge
+
+	buildInfo *prometheus.GaugeVec
+
+	sanitizedLabelsTotal *prometheus.CounterVec
+	truncatedValues      *prometheus.CounterVec
+	autoResolvesTotal    *prometheus.CounterVec
+	rateLimitedTotal     *prometheus.CounterVec
+	idempotentReplays    *prometheus.CounterVec
+	droppedTotal         *prometheus.CounterVec
+	ruleMatchesTotal     *prometheus.CounterVec
+	auditErrorsTotal     prometheus.Counter
+}
+
+// Trace identifies the distributed trace a measurement belongs to. The zero value means
+// "no trace": observations then carry no exemplar.
+type Trace struct {
+	TraceID string
+	SpanID  string
 }
 
+// exemplar returns the exemplar labels for trace, or nil when there is no trace ID.
+func (trace Trace) exemplar() prometheus.Labels {
+	if trace.TraceID == "" {
+		return nil
+	}
+
+	labels := prometheus.Labels{"trace_id": trace.TraceID}
+	if trace.SpanID != "" {
+		labels["span_id"] = trace.SpanID
+	}
+
+	return labels
+}
+
+// observe records value on observer, attaching trace as an OpenMetrics exemplar when set.
+func observe(observer prometheus.Observer, value float64, trace Trace) {
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+
+	labels := trace.exemplar()
+	if !ok || labels == nil {
+		observer.Observe(value)
+
+		return
+	}
+
+	exemplarObserver.ObserveWithExemplar(value, labels)
+}
+
+// Alertmanager POST modes.
+const (
+	PostModeImmediate = "immediate"
+	PostModeBatched   = "batched"
+)
+
+// Reasons for gotilert_forward_queue_dropped_total.
+const (
+	QueueDropFull   = "full"
+	QueueDropFailed = "failed"
+)
+
+// Outcomes for gotilert_auto_resolves_total.
+const (
+	AutoResolveScheduled = "scheduled"
+	AutoResolveSent      = "sent"
+	AutoResolveFailed    = "failed"
+	AutoResolveDropped   = "dropped"
+	AutoResolveCancelled = "cancelled"
+)
+
+// Reasons for gotilert_dropped_total.
+const (
+	DropBelowMinPriority = "below_min_priority"
+	DropRule             = "rule"
+)
+
+// circuitStates are the gotilert_circuit_state label values.
+var circuitStates = []string{"closed", "open", "half-open"}
+
 func New() *Metrics {
 	reg := prometheus.NewRegistry()
 
@@ -78,6 +169,136 @@ func New() *Metrics {
 			},
 			[]string{"app"},
 		),
+		forwardDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gotilert_forward_duration_seconds",
+				Help:    "Duration of forwarding a message to Alertmanager in seconds, including retries.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"app"},
+		),
+		upstreamRetriesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_upstream_retries_total",
+				Help: "Total number of retried Alertmanager POSTs (app is \"batched\" for batched posts).",
+			},
+			[]string{"app"},
+		),
+		alertmanagerPostsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_alertmanager_posts_total",
+				Help: "Total number of POST calls to Alertmanager, by mode (immediate or batched).",
+			},
+			[]string{"mode"},
+		),
+		batchSize: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "gotilert_alertmanager_batch_size",
+				Help:    "Number of alerts per batched POST to Alertmanager.",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 8),
+			},
+		),
+		alertmanagerReady: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "gotilert_alertmanager_ready",
+				Help: "Whether the last Alertmanager readiness check succeeded (1) or failed (0).",
+			},
+		),
+		readyCheckDuration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "gotilert_alertmanager_ready_check_duration_seconds",
+				Help:    "Duration of Alertmanager readiness checks in seconds.",
+				Buckets: prometheus.DefBuckets,
+			},
+		),
+		circuitState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotilert_circuit_state",
+				Help: "Alertmanager circuit breaker state (1 for the current state, 0 otherwise).",
+			},
+			[]string{"state"},
+		),
+		forwardQueueDepth: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "gotilert_forward_queue_depth",
+				Help: "Number of alerts waiting in the async forward queue.",
+			},
+		),
+		forwardQueueDropped: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_forward_queue_dropped_total",
+				Help: "Total number of async forwards dropped because the queue was full or the background post failed.",
+			},
+			[]string{"app", "reason"},
+		),
+		forwardInflight: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "gotilert_forward_inflight",
+				Help: "Number of Alertmanager posts currently running under alertmanager.maxConcurrency.",
+			},
+		),
+		buildInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotilert_build_info",
+				Help: "Build information about the running binary (always 1).",
+			},
+			[]string{"version", "commit", "date", "goversion"},
+		),
+		sanitizedLabelsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_sanitized_labels_total",
+				Help: "Total number of alert labels whose name or value was rewritten to be valid for Alertmanager.",
+			},
+			[]string{"app"},
+		),
+		truncatedValues: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_truncated_values_total",
+				Help: "Total number of alert label and annotation values cut to defaults.valueMaxLen.",
+			},
+			[]string{"app"},
+		),
+		autoResolvesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_auto_resolves_total",
+				Help: "Total number of alert resolutions scheduled, posted, dropped or cancelled by defaults.autoResolveAfter.",
+			},
+			[]string{"app", "outcome"},
+		),
+		rateLimitedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_rate_limited_total",
+				Help: "Total number of /message requests rejected by the per-app rate limit.",
+			},
+			[]string{"app"},
+		),
+		idempotentReplays: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_idempotent_replays_total",
+				Help: "Total number of /message requests answered from the Idempotency-Key cache instead of forwarding.",
+			},
+			[]string{"app"},
+		),
+		droppedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_dropped_total",
+				Help: "Total number of accepted messages intentionally not forwarded to Alertmanager.",
+			},
+			[]string{"app", "reason"},
+		),
+		ruleMatchesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotilert_rule_matches_total",
+				Help: "Total number of messages matched by each defaults.rules entry.",
+			},
+			[]string{"rule"},
+		),
+		auditErrorsTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: "gotilert_audit_errors_total",
+				Help: "Total number of failed audit log writes and flushes (logging.auditFile).",
+			},
+		),
 	}
 
 	// Keep registration explicit (no init()).
@@ -86,23 +307,65 @@ func New() *Metrics {
 		metrics.requestDuration,
 		metrics.forwardedAlertsTotal,
 		metrics.upstreamFailuresTotal,
+		metrics.forwardDuration,
+		metrics.upstreamRetriesTotal,
+		metrics.alertmanagerPostsTotal,
+		metrics.batchSize,
+		metrics.alertmanagerReady,
+		metrics.readyCheckDuration,
+		metrics.circuitState,
+		metrics.forwardQueueDepth,
+		metrics.forwardQueueDropped,
+		metrics.forwardInflight,
+		metrics.buildInfo,
+		metrics.sanitizedLabelsTotal,
+		metrics.truncatedValues,
+		metrics.autoResolvesTotal,
+		metrics.rateLimitedTotal,
+		metrics.idempotentReplays,
+		metrics.droppedTotal,
+		metrics.ruleMatchesTotal,
+		metrics.auditErrorsTotal,
 	)
 
 	return metrics
 }
 
+// RegisterRuntimeCollectors adds the standard go_* and process_* collectors.
+// They are opt-out (see metrics.disableRuntimeMetrics) for users who want the minimal set.
+func (m *Metrics) RegisterRuntimeCollectors() {
+	if m == nil {
+		return
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// SetBuildInfo publishes gotilert_build_info with the given build metadata.
+func (m *Metrics) SetBuildInfo(version, commit, date string) {
+	if m == nil {
+		return
+	}
+
+	m.buildInfo.WithLabelValues(version, commit, date, runtime.Version()).Set(1)
+}
+
+// Handler serves the registry; scrapers asking for OpenMetrics also get exemplars.
 func (m *Metrics) Handler() http.Handler {
-	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
 }
 
-func (m *Metrics) ObserveRequest(method, path string, status int, duration time.Duration) {
+func (m *Metrics) ObserveRequest(method, path string, status int, duration time.Duration, trace Trace) {
 	if m == nil {
 		return
 	}
 
 	statusStr := strconv.Itoa(status)
 	m.requestsTotal.WithLabelValues(method, path, statusStr).Inc()
-	m.requestDuration.WithLabelValues(method, path, statusStr).Observe(duration.Seconds())
+	observe(m.requestDuration.WithLabelValues(method, path, statusStr), duration.Seconds(), trace)
 }
 
 func (m *Metrics) IncForwarded(app string) {
@@ -120,3 +383,167 @@ func (m *Metrics) IncUpstreamFailure(app string) {
 
 	m.upstreamFailuresTotal.WithLabelValues(app).Inc()
 }
+
+func (m *Metrics) ObserveForward(app string, duration time.Duration, trace Trace) {
+	if m == nil {
+		return
+	}
+
+	observe(m.forwardDuration.WithLabelValues(app), duration.Seconds(), trace)
+}
+
+func (m *Metrics) IncUpstreamRetry(app string) {
+	if m == nil {
+		return
+	}
+
+	m.upstreamRetriesTotal.WithLabelValues(app).Inc()
+}
+
+func (m *Metrics) IncAlertmanagerPost(mode string) {
+	if m == nil {
+		return
+	}
+
+	m.alertmanagerPostsTotal.WithLabelValues(mode).Inc()
+}
+
+func (m *Metrics) ObserveBatchSize(size int) {
+	if m == nil {
+		return
+	}
+
+	m.batchSize.Observe(float64(size))
+}
+
+func (m *Metrics) SetAlertmanagerReady(ready bool) {
+	if m == nil {
+		return
+	}
+
+	value := 0.0
+	if ready {
+		value = 1
+	}
+
+	m.alertmanagerReady.Set(value)
+}
+
+func (m *Metrics) ObserveReadyCheck(duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.readyCheckDuration.Observe(duration.Seconds())
+}
+
+// SetCircuitState marks state (closed, open or half-open) as the current circuit breaker state.
+func (m *Metrics) SetCircuitState(state string) {
+	if m == nil {
+		return
+	}
+
+	for _, known := range circuitStates {
+		value := 0.0
+		if known == state {
+			value = 1
+		}
+
+		m.circuitState.WithLabelValues(known).Set(value)
+	}
+}
+
+func (m *Metrics) SetForwardQueueDepth(depth int) {
+	if m == nil {
+		return
+	}
+
+	m.forwardQueueDepth.Set(float64(depth))
+}
+
+func (m *Metrics) SetForwardInflight(inflight int) {
+	if m == nil {
+		return
+	}
+
+	m.forwardInflight.Set(float64(inflight))
+}
+
+// IncForwardQueueDropped counts an async forward lost for reason (QueueDropFull or QueueDropFailed).
+func (m *Metrics) IncForwardQueueDropped(app, reason string) {
+	if m == nil {
+		return
+	}
+
+	m.forwardQueueDropped.WithLabelValues(app, reason).Inc()
+}
+
+func (m *Metrics) AddSanitizedLabels(app string, count int) {
+	if m == nil {
+		return
+	}
+
+	m.sanitizedLabelsTotal.WithLabelValues(app).Add(float64(count))
+}
+
+// AddTruncatedValues counts label and annotation values cut to the configured maximum length.
+func (m *Metrics) AddTruncatedValues(app string, count int) {
+	if m == nil {
+		return
+	}
+
+	m.truncatedValues.WithLabelValues(app).Add(float64(count))
+}
+
+// IncAutoResolve counts a scheduled resolution by outcome (AutoResolveScheduled, Sent, Failed,
+// Dropped or Cancelled).
+func (m *Metrics) IncAutoResolve(app, outcome string) {
+	if m == nil {
+		return
+	}
+
+	m.autoResolvesTotal.WithLabelValues(app, outcome).Inc()
+}
+
+func (m *Metrics) IncRateLimited(app string) {
+	if m == nil {
+		return
+	}
+
+	m.rateLimitedTotal.WithLabelValues(app).Inc()
+}
+
+// IncIdempotentReplay counts a /message request answered from the Idempotency-Key cache.
+func (m *Metrics) IncIdempotentReplay(app string) {
+	if m == nil {
+		return
+	}
+
+	m.idempotentReplays.WithLabelValues(app).Inc()
+}
+
+// IncDropped counts a message accepted but not forwarded for reason (e.g. DropBelowMinPriority).
+func (m *Metrics) IncDropped(app, reason string) {
+	if m == nil {
+		return
+	}
+
+	m.droppedTotal.WithLabelValues(app, reason).Inc()
+}
+
+func (m *Metrics) IncRuleMatch(rule string) {
+	if m == nil {
+		return
+	}
+
+	m.ruleMatchesTotal.WithLabelValues(rule).Inc()
+}
+
+// IncAuditError counts a failed audit log write or flush.
+func (m *Metrics) IncAuditError() {
+	if m == nil {
+		return
+	}
+
+	m.auditErrorsTotal.Inc()
+}
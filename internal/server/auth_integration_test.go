@@ -147,7 +147,7 @@ func newTestServer(t *testing.T, tokenToApp map[string]server.App) *http.Server
 		return app, ok
 	}
 
-	forward := func(_ context.Context, _ server.App, _ gotify.MessageRequest, _ uint64) error {
+	forward := func(_ context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
 		return nil
 	}
 
@@ -182,3 +182,141 @@ func mustJSON(t *testing.T, v any) []byte {
 
 	return data
 }
+
+func TestMessageRateLimitedReturns429WithRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv := newTestServer(t, map[string]server.App{
+		"LIMITED": {Name: "app", ID: 1, RateLimit: server.RateLimit{RPS: 0.5, Burst: 1}},
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"http://example.local/message",
+			bytes.NewReader(mustJSON(t, gotify.MessageRequest{Message: "hello"})),
+		)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "LIMITED")
+
+		srv.Handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusOK {
+		t.Fatalf("expected first request status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec := send()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusTooManyRequests, rec.Code, rec.Body.String())
+	}
+
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("expected Retry-After %q, got %q", "2", got)
+	}
+}
+
+func TestMessageRequestIDPropagatesToForwarderAndResponse(t *testing.T) {
+	t.Parallel()
+
+	var forwardedRequestID string
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+		ForwardMessage: func(ctx context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+			forwardedRequestID = server.RequestIDFromContext(ctx)
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"http://example.local/message",
+		bytes.NewReader(mustJSON(t, gotify.MessageRequest{Message: "hello"})),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	responseRequestID := rec.Header().Get("X-Request-Id")
+	if len(responseRequestID) != 32 {
+		t.Fatalf("expected a 32 hex char X-Request-Id, got %q", responseRequestID)
+	}
+
+	if forwardedRequestID != responseRequestID {
+		t.Fatalf("expected forwarder request ID %q, got %q", responseRequestID, forwardedRequestID)
+	}
+}
+
+func TestRoutePrefixMountsMessageRoute(t *testing.T) {
+	t.Parallel()
+
+	forwarded := 0
+
+	httpServer, err := server.New(&server.Options{
+		RoutePrefix: "gotilert/",
+		ResolveApp:  func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			forwarded++
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/gotilert/message", wantStatus: http.StatusOK},
+		{path: "/message", wantStatus: http.StatusNotFound},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"http://example.local"+testCase.path,
+			bytes.NewReader(mustJSON(t, gotify.MessageRequest{Message: "hello"})),
+		)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d", testCase.path, testCase.wantStatus, rec.Code)
+		}
+	}
+
+	if forwarded != 1 {
+		t.Fatalf("expected 1 forwarded message, got %d", forwarded)
+	}
+}
+
+func TestNormalizeRoutePrefix(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		"":           "",
+		"/":          "",
+		"gotilert":   "/gotilert",
+		"/gotilert/": "/gotilert",
+		" /a/b/ ":    "/a/b",
+	} {
+		if got := server.NormalizeRoutePrefix(input); got != want {
+			t.Fatalf("NormalizeRoutePrefix(%q): expected %q, got %q", input, want, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+)
+
+// batchLineResult reports the outcome of one NDJSON line; Status is the HTTP status the line
+// would have received as a single /message request.
+type batchLineResult struct {
+	Line   int    `json:"line"`
+	ID     uint64 `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+type batchResponse struct {
+	Accepted int               `json:"accepted"`
+	Failed   int               `json:"failed"`
+	Results  []batchLineResult `json:"results"`
+}
+
+// forwardBatch forwards every valid line of an NDJSON batch as its own alert and answers
+// with a per-line summary: 200 (202 when async) if every line succeeded, 207 otherwise.
+// Errors affecting the whole body (too large, too many lines, no messages) are answered like
+// single messages. chargeLines takes a rate limit token per line; without enough tokens the
+// whole batch is rejected with 429 before anything is forwarded, and a batch that could never
+// fit in the burst with a non-retryable 413.
+func forwardBatch(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+	app App,
+	forward ForwardMessageFunc,
+	async bool,
+	parseOptions gotify.ParseOptions,
+	chargeLines func(lines int) (time.Duration, error),
+) {
+	lines, err := gotify.ParseMessageRequests(request, parseOptions)
+	if err != nil {
+		writeParseError(responseWriter, request, err)
+
+		return
+	}
+
+	wait, err := chargeLines(len(lines))
+	if errors.Is(err, ErrRateLimited) {
+		writeRateLimited(responseWriter, request, wait)
+
+		return
+	}
+
+	if err != nil {
+		writeParseError(responseWriter, request, err)
+
+		return
+	}
+
+	if forward == nil {
+		writeJSONError(responseWriter, request, http.StatusInternalServerError, ErrInternalMisconfigured)
+
+		return
+	}
+
+	okStatus := http.StatusOK
+	if async {
+		okStatus = http.StatusAccepted
+	}
+
+	resp := batchResponse{Results: make([]batchLineResult, 0, len(lines))}
+
+	for _, line := range lines {
+		result := forwardBatchLine(request, app, forward, line, okStatus)
+		if result.Error != "" {
+			resp.Failed++
+		} else {
+			resp.Accepted++
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	status := okStatus
+	if resp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	writeJSON(responseWriter, status, resp)
+}
+
+func forwardBatchLine(
+	request *http.Request,
+	app App,
+	forward ForwardMessageFunc,
+	line gotify.BatchLine,
+	okStatus int,
+) batchLineResult {
+	result := batchLineResult{Line: line.Line, Status: okStatus}
+
+	if line.Err != nil {
+		status, err := parseErrorStatus(line.Err)
+		result.Status, result.Error, result.Code = status, err.Error(), errorCode(err, status)
+
+		return result
+	}
+
+	messageIdentifier := nextMessageID()
+
+	err := forward(request.Context(), app, line.Message, messageIdentifier)
+	if err != nil {
+		// Forwarder logs upstream failures with context.
+		status, clientErr := forwardErrorStatus(err)
+		result.Status, result.Error, result.Code = status, clientErr.Error(), errorCode(clientErr, status)
+
+		return result
+	}
+
+	result.ID = messageIdentifier.Seq
+
+	return result
+}
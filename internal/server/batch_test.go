@@ -0,0 +1,226 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+type batchResult struct {
+	Accepted int `json:"accepted"`
+	Failed   int `json:"failed"`
+	Results  []struct {
+		Line   int    `json:"line"`
+		ID     uint64 `json:"id"`
+		Status int    `json:"status"`
+		Error  string `json:"error"`
+	} `json:"results"`
+}
+
+func TestMessageNDJSONBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex     sync.Mutex
+		forwarded []string
+	)
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, msg gotify.MessageRequest, _ server.MessageID) error {
+			if msg.Message == "upstream down" {
+				return errors.New("connection refused")
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			forwarded = append(forwarded, msg.Message)
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	post := func(body string) (*httptest.ResponseRecorder, batchResult) {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		var result batchResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+		}
+
+		return rec, result
+	}
+
+	rec, result := post(`{"message":"one"}` + "\n" + `{"message":"two"}` + "\n")
+	if rec.Code != http.StatusOK || result.Accepted != 2 || result.Failed != 0 {
+		t.Fatalf("expected 200 with 2 accepted, got %d %+v", rec.Code, result)
+	}
+
+	rec, result = post(`{"message":"three"}` + "\n" + `{"title":"missing"}` + "\n" + `{"message":"upstream down"}`)
+	if rec.Code != http.StatusMultiStatus || result.Accepted != 1 || result.Failed != 2 {
+		t.Fatalf("expected 207 with 1 accepted and 2 failed, got %d %+v", rec.Code, result)
+	}
+
+	wantStatus := []int{http.StatusOK, http.StatusBadRequest, http.StatusBadGateway}
+	for index, line := range result.Results {
+		if line.Line != index+1 || line.Status != wantStatus[index] {
+			t.Fatalf("line %d: expected status %d, got %+v", index+1, wantStatus[index], line)
+		}
+
+		if (line.Error == "") != (line.Status == http.StatusOK) || (line.ID != 0) != (line.Status == http.StatusOK) {
+			t.Fatalf("line %d: expected an id on success and an error on failure, got %+v", index+1, line)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if strings.Join(forwarded, ",") != "one,two,three" {
+		t.Fatalf("expected one,two,three forwarded, got %v", forwarded)
+	}
+}
+
+func TestMessageNDJSONBatchChargesRateLimitPerLine(t *testing.T) {
+	t.Parallel()
+
+	var forwarded atomic.Int64
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1, RateLimit: server.RateLimit{RPS: 0.001, Burst: 4}}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			forwarded.Add(1)
+
+			return nil
+		},
+		MaxBatchLines: 4,
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	post := func(lines int) *httptest.ResponseRecorder {
+		body := strings.Repeat(`{"message":"m"}`+"\n", lines)
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	if rec := post(5); rec.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rec.Body.String(), "batch_too_large") {
+		t.Fatalf("expected 413 batch_too_large above maxBatchLines, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The rejected batch paid for its request token: 3 of the burst of 4 are left.
+	if rec := post(2); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a batch within the remaining tokens, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The request token is still available, the second line's is not.
+	if rec := post(2); rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After once the batch needs more tokens than left, got %d", rec.Code)
+	}
+
+	if forwarded.Load() != 2 {
+		t.Fatalf("expected only the admitted batch to be forwarded, got %d messages", forwarded.Load())
+	}
+}
+
+func TestMessageNDJSONBatchAboveBurstIsNotRetryable(t *testing.T) {
+	t.Parallel()
+
+	var forwarded atomic.Int64
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1, RateLimit: server.RateLimit{RPS: 0.001, Burst: 3}}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			forwarded.Add(1)
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	post := func(lines int) *httptest.ResponseRecorder {
+		body := strings.Repeat(`{"message":"m"}`+"\n", lines)
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := post(4)
+	if rec.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rec.Body.String(), "batch_too_large") {
+		t.Fatalf("expected 413 batch_too_large for a batch above the burst, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec.Header().Get("Retry-After") != "" {
+		t.Fatalf("expected no Retry-After for a batch that can never be admitted, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	// Only the request token was spent: a batch within the burst still fits.
+	if rec := post(2); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a batch within the burst, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if forwarded.Load() != 2 {
+		t.Fatalf("expected only the admitted batch to be forwarded, got %d messages", forwarded.Load())
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const forwardedForHeader = "X-Forwarded-For"
+
+type clientIPContextKey struct{}
+
+// ClientIPFromContext returns the client IP resolved for the request by the server (see
+// Options.TrustedProxies); the zero Addr means it is unknown (e.g. a unix socket peer).
+func ClientIPFromContext(ctx context.Context) netip.Addr {
+	addr, _ := ctx.Value(clientIPContextKey{}).(netip.Addr)
+
+	return addr
+}
+
+// clientIPResolver derives the real client IP from the connection peer and X-Forwarded-For.
+type clientIPResolver struct {
+	// trustPeer honors X-Forwarded-For from any peer (server.trustProxy).
+	trustPeer bool
+	trusted   []netip.Prefix
+}
+
+func (resolver clientIPResolver) isTrusted(addr netip.Addr) bool {
+	return prefixesContain(resolver.trusted, addr)
+}
+
+// resolve returns the peer address unless the peer is a trusted proxy. Then the
+// X-Forwarded-For entries are walked right to left and the first one that is not itself a
+// trusted proxy wins; entries left of it are client-controlled and never consulted.
+func (resolver clientIPResolver) resolve(request *http.Request) netip.Addr {
+	peer, ok := remoteIP(request.RemoteAddr)
+	if !resolver.trustPeer && (!ok || !resolver.isTrusted(peer)) {
+		return peer
+	}
+
+	entries := forwardedFor(request.Header.Values(forwardedForHeader))
+
+	for index := len(entries) - 1; index >= 0; index-- {
+		addr, valid := parseIP(entries[index])
+		if !valid {
+			// A malformed hop cannot be attributed; stop at the last address we trust.
+			return peer
+		}
+
+		if index == 0 || !resolver.isTrusted(addr) {
+			return addr
+		}
+	}
+
+	return peer
+}
+
+// withClientIP stores the resolved client IP in the request context.
+func withClientIP(resolver clientIPResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		ctx := context.WithValue(request.Context(), clientIPContextKey{}, resolver.resolve(request))
+		next.ServeHTTP(responseWriter, request.WithContext(ctx))
+	})
+}
+
+func remoteIP(remoteAddr string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return parseIP(host)
+}
+
+func forwardedFor(values []string) []string {
+	var entries []string
+
+	for _, value := range values {
+		entries = append(entries, strings.Split(value, ",")...)
+	}
+
+	return entries
+}
+
+func parseIP(value string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap().WithZone(""), true
+}
+
+func clientIPString(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+
+	return addr.String()
+}
+
+// withAllowedClients answers 403 to clients whose IP is outside allowed before next runs,
+// so rejected callers never reach token checks. An empty allowed list disables the check.
+func withAllowedClients(allowed []netip.Prefix, next http.HandlerFunc) http.HandlerFunc {
+	if len(allowed) == 0 {
+		return next
+	}
+
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		addr := ClientIPFromContext(request.Context())
+		if !addr.IsValid() || !prefixesContain(allowed, addr) {
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrClientNotAllowed)
+
+			return
+		}
+
+		next(responseWriter, request)
+	}
+}
+
+func prefixesContain(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageAllowedClients(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(trustProxy bool) *http.Server {
+		httpServer, err := server.New(&server.Options{
+			ResolveApp: func(token string) (server.App, bool) {
+				return server.App{Name: "app", ID: 1}, token == "TOKEN"
+			},
+			ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+				return nil
+			},
+			AllowedClients: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")},
+			TrustProxy:     trustProxy,
+		})
+		if err != nil {
+			t.Fatalf("server.New: %v", err)
+		}
+
+		return httpServer
+	}
+
+	cases := []struct {
+		name         string
+		trustProxy   bool
+		remoteAddr   string
+		forwardedFor string
+		token        string
+		want         int
+	}{
+		{name: "direct allowed", remoteAddr: "10.1.2.3:5000", token: "TOKEN", want: http.StatusOK},
+		{name: "direct ipv6 allowed", remoteAddr: "[2001:db8::1]:5000", token: "TOKEN", want: http.StatusOK},
+		{name: "direct denied", remoteAddr: "192.0.2.1:5000", token: "TOKEN", want: http.StatusForbidden},
+		{name: "denied before token check", remoteAddr: "192.0.2.1:5000", want: http.StatusForbidden},
+		{name: "allowed bad token", remoteAddr: "10.1.2.3:5000", token: "WRONG", want: http.StatusForbidden},
+		{
+			name: "forwarded ignored without trustProxy", remoteAddr: "192.0.2.1:5000",
+			forwardedFor: "10.1.2.3", token: "TOKEN", want: http.StatusForbidden,
+		},
+		{
+			name: "proxied allowed", trustProxy: true, remoteAddr: "192.0.2.1:5000",
+			forwardedFor: "203.0.113.9, 10.1.2.3", token: "TOKEN", want: http.StatusOK,
+		},
+		{
+			name: "proxied denied", trustProxy: true, remoteAddr: "10.0.0.1:5000",
+			forwardedFor: "10.1.2.3, 203.0.113.9", token: "TOKEN", want: http.StatusForbidden,
+		},
+		{
+			name: "proxied without header uses peer", trustProxy: true, remoteAddr: "10.1.2.3:5000",
+			token: "TOKEN", want: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = testCase.remoteAddr
+
+		if testCase.token != "" {
+			req.Header.Set("X-Gotify-Key", testCase.token)
+		}
+
+		if testCase.forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+		}
+
+		rec := httptest.NewRecorder()
+		newServer(testCase.trustProxy).Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.want {
+			t.Fatalf("%s: expected status %d, got %d (body=%q)", testCase.name, testCase.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestClientIPFromTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	var resolved netip.Addr
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(ctx context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+			resolved = server.ClientIPFromContext(ctx)
+
+			return nil
+		},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{name: "no proxy", remoteAddr: "198.51.100.7:5000", want: "198.51.100.7"},
+		{
+			name: "spoofed header from untrusted peer", remoteAddr: "198.51.100.7:5000",
+			forwardedFor: []string{"192.0.2.1"}, want: "198.51.100.7",
+		},
+		{
+			name: "trusted proxy", remoteAddr: "10.0.0.1:5000",
+			forwardedFor: []string{"203.0.113.9"}, want: "203.0.113.9",
+		},
+		{
+			name: "spoofed entries left of the real client", remoteAddr: "10.0.0.1:5000",
+			forwardedFor: []string{"192.0.2.1, 203.0.113.9"}, want: "203.0.113.9",
+		},
+		{
+			name: "proxy chain", remoteAddr: "10.0.0.1:5000",
+			forwardedFor: []string{"192.0.2.1, 203.0.113.9", "10.2.3.4"}, want: "203.0.113.9",
+		},
+		{
+			name: "only proxies", remoteAddr: "10.0.0.1:5000",
+			forwardedFor: []string{"10.9.9.9, 10.2.3.4"}, want: "10.9.9.9",
+		},
+		{
+			name: "malformed hop", remoteAddr: "10.0.0.1:5000",
+			forwardedFor: []string{"203.0.113.9, not-an-ip"}, want: "10.0.0.1",
+		},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+		req.RemoteAddr = testCase.remoteAddr
+
+		for _, value := range testCase.forwardedFor {
+			req.Header.Add("X-Forwarded-For", value)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d (body=%q)", testCase.name, rec.Code, rec.Body.String())
+		}
+
+		if got := resolved.String(); got != testCase.want {
+			t.Fatalf("%s: expected client IP %s, got %s", testCase.name, testCase.want, got)
+		}
+	}
+}
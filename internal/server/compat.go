@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+)
+
+// gotifyErrorsKey marks requests whose errors use Gotify's envelope (see writeJSONError).
+type gotifyErrorsKey struct{}
+
+// wantsGotifyErrors reports whether request went through withGotifyErrors.
+func wantsGotifyErrors(request *http.Request) bool {
+	enabled, _ := request.Context().Value(gotifyErrorsKey{}).(bool)
+
+	return enabled
+}
+
+// withGotifyErrors makes every handler below it report errors as Gotify does:
+// {"error":"<status text>","errorCode":<status>,"errorDescription":"<message>"}.
+func withGotifyErrors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		ctx := context.WithValue(request.Context(), gotifyErrorsKey{}, true)
+
+		next.ServeHTTP(responseWriter, request.WithContext(ctx))
+	})
+}
+
+// compatUser is the single, static user reported by GET /current/user.
+var compatUser = gotify.User{ID: 1, Name: "gotilert", Admin: false}
+
+// applicationHandler serves a Gotify-like GET /application listing the caller's own app
+// (name and id only), so a token never reveals which other apps are configured.
+func applicationHandler(resolve ResolveAppFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet && request.Method != http.MethodHead {
+			writeJSONError(responseWriter, request, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+
+			return
+		}
+
+		app, ok := authenticate(request, resolve)
+		if !ok {
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrTokenMissingOrInvalid)
+
+			return
+		}
+
+		writeJSON(responseWriter, http.StatusOK, []gotify.Application{{
+			ID:              app.ID,
+			Name:            app.Name,
+			DefaultPriority: gotify.DefaultPriority,
+		}})
+	}
+}
+
+// currentUserHandler serves a Gotify-like GET /current/user to callers holding any valid app token.
+func currentUserHandler(resolve ResolveAppFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet && request.Method != http.MethodHead {
+			writeJSONError(responseWriter, request, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+
+			return
+		}
+
+		_, ok := authenticate(request, resolve)
+		if !ok {
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrTokenMissingOrInvalid)
+
+			return
+		}
+
+		writeJSON(responseWriter, http.StatusOK, compatUser)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestGotifyCompatApplicationListsOnlyCallerAppWithoutTokens(t *testing.T) {
+	t.Parallel()
+
+	apps := map[string]server.App{"secret-token": {Name: "backup", ID: 7}, "other-token": {Name: "nas", ID: 9}}
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			app, ok := apps[token]
+
+			return app, ok
+		},
+		GotifyEndpoints: true,
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local/application", nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d without a token, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.local/application", nil)
+	req.Header.Set("X-Gotify-Key", "secret-token")
+
+	rec = httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	if strings.Contains(rec.Body.String(), "secret-token") {
+		t.Fatalf("expected no token in response, got %s", rec.Body.String())
+	}
+
+	var got []gotify.Application
+
+	err = json.Unmarshal(rec.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Name != "backup" || got[0].ID != 7 {
+		t.Fatalf("expected only the caller's app, got %+v", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.local/current/user", nil)
+	req.Header.Set("X-Gotify-Key", "secret-token")
+
+	rec = httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /current/user status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestGotifyCompatRoutesAreOptIn(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local/application", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestErrorEnvelopeShape(t *testing.T) {
+	t.Parallel()
+
+	for _, gotifyErrors := range []bool{false, true} {
+		httpServer, err := server.New(&server.Options{
+			GotifyErrors: gotifyErrors,
+			ResolveApp: func(string) (server.App, bool) {
+				return server.App{}, false
+			},
+		})
+		if err != nil {
+			t.Fatalf("server.New: %v", err)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://example.local/message", nil))
+
+		var body map[string]any
+
+		err = json.Unmarshal(rec.Body.Bytes(), &body)
+		if err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+
+		if !gotifyErrors {
+			if len(body) != 2 || body["error"] != server.ErrTokenMissingOrInvalid.Error() || body["code"] != "token_invalid" {
+				t.Fatalf("expected slim error body, got %v", body)
+			}
+
+			continue
+		}
+
+		if body["error"] != "Forbidden" ||
+			body["errorCode"] != float64(http.StatusForbidden) ||
+			body["errorDescription"] != server.ErrTokenMissingOrInvalid.Error() ||
+			body["code"] != "token_invalid" {
+			t.Fatalf("expected Gotify error envelope, got %v", body)
+		}
+	}
+}
+
+func TestGotifyErrorEnvelopeBehindIdempotencyRecorder(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		GotifyErrors: true,
+		ResolveApp:   func(string) (server.App, bool) { return server.App{Name: "app"}, true },
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			return errUpstreamDown
+		},
+		Idempotency: &server.IdempotencyOptions{TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := postIdempotent(httpServer, "TOKEN", "retry-1")
+
+	var body map[string]any
+
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if body["errorCode"] != float64(rec.Code) || body["errorDescription"] == nil {
+		t.Fatalf("expected Gotify error envelope, got %v", body)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageContentTypeStatus(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{name: "unsupported media type", contentType: "text/plain", body: "hello", want: http.StatusUnsupportedMediaType},
+		{name: "missing message", contentType: "application/json", body: `{"title":"t"}`, want: http.StatusBadRequest},
+		{name: "invalid priority", contentType: "application/json", body: `{"message":"m","priority":"x"}`, want: http.StatusBadRequest},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", testCase.contentType)
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.want {
+			t.Fatalf("%s: expected status %d, got %d (body=%q)", testCase.name, testCase.want, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const corsAnyOrigin = "*"
+
+// CORSOptions enables CORS on /message. Origins must already be normalized
+// (scheme://host[:port], lowercase); "*" allows any origin.
+type CORSOptions struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodPost}
+	defaultCORSHeaders = []string{
+		"Content-Type", "Content-Encoding", "X-Gotify-Key", "Authorization", signatureHeader, idempotencyKeyHeader,
+	}
+)
+
+type corsPolicy struct {
+	anyOrigin bool
+	origins   map[string]bool
+	methods   string
+	headers   string
+	maxAge    string
+}
+
+func newCORSPolicy(opts *CORSOptions) *corsPolicy {
+	policy := &corsPolicy{origins: make(map[string]bool, len(opts.AllowedOrigins))}
+
+	for _, origin := range opts.AllowedOrigins {
+		if origin == corsAnyOrigin {
+			policy.anyOrigin = true
+		}
+
+		policy.origins[origin] = true
+	}
+
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+
+	policy.methods = strings.Join(methods, ", ")
+	policy.headers = strings.Join(headers, ", ")
+
+	if opts.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+
+	return policy
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin, or "" if it is not
+// allowed. Configured origins are reflected one at a time; "*" is sent literally.
+func (policy *corsPolicy) allowOrigin(origin string) string {
+	switch {
+	case origin == "":
+		return ""
+	case policy.origins[strings.ToLower(origin)]:
+		return origin
+	case policy.anyOrigin:
+		return corsAnyOrigin
+	default:
+		return ""
+	}
+}
+
+// withCORS answers preflight requests from allowed origins with 204 and adds
+// Access-Control-Allow-Origin to every other response for them. Requests from other origins
+// get no CORS headers, so browsers block them. A nil opts leaves next untouched.
+func withCORS(opts *CORSOptions, next http.HandlerFunc) http.HandlerFunc {
+	if opts == nil || len(opts.AllowedOrigins) == 0 {
+		return next
+	}
+
+	policy := newCORSPolicy(opts)
+
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		header := responseWriter.Header()
+		header.Add("Vary", "Origin")
+
+		allowedOrigin := policy.allowOrigin(request.Header.Get("Origin"))
+		if allowedOrigin == "" {
+			next(responseWriter, request)
+
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", allowedOrigin)
+
+		if request.Method != http.MethodOptions || request.Header.Get("Access-Control-Request-Method") == "" {
+			header.Set("Access-Control-Expose-Headers", requestIDHeader+", Retry-After")
+			next(responseWriter, request)
+
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", policy.methods)
+		header.Set("Access-Control-Allow-Headers", policy.headers)
+
+		if policy.maxAge != "" {
+			header.Set("Access-Control-Max-Age", policy.maxAge)
+		}
+
+		responseWriter.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func newCORSServer(t *testing.T, origins ...string) *http.Server {
+	t.Helper()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			return nil
+		},
+		CORS: &server.CORSOptions{AllowedOrigins: origins, MaxAge: 10 * time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	return httpServer
+}
+
+func TestCORSPreflight(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newCORSServer(t, "https://dashboard.example.com")
+
+	req := httptest.NewRequest(http.MethodOptions, "http://example.local/message", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type, x-gotify-key")
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", rec.Code)
+	}
+
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://dashboard.example.com",
+		"Access-Control-Allow-Methods": "POST",
+		"Access-Control-Max-Age":       "600",
+	}
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Fatalf("expected %s %q, got %q", name, value, got)
+		}
+	}
+
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "X-Gotify-Key") {
+		t.Fatalf("expected X-Gotify-Key in Access-Control-Allow-Headers, got %q", got)
+	}
+}
+
+func TestCORSReflectsOnlyAllowedOrigins(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    string
+	}{
+		{
+			name: "allowed", allowed: []string{"https://dashboard.example.com"},
+			origin: "https://dashboard.example.com", want: "https://dashboard.example.com",
+		},
+		{name: "other origin", allowed: []string{"https://dashboard.example.com"}, origin: "https://evil.example.com"},
+		{name: "no origin", allowed: []string{"https://dashboard.example.com"}},
+		{name: "wildcard", allowed: []string{"*"}, origin: "https://any.example.com", want: "*"},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		if testCase.origin != "" {
+			req.Header.Set("Origin", testCase.origin)
+		}
+
+		rec := httptest.NewRecorder()
+		newCORSServer(t, testCase.allowed...).Handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", testCase.name, rec.Code)
+		}
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != testCase.want {
+			t.Fatalf("%s: expected Access-Control-Allow-Origin %q, got %q", testCase.name, testCase.want, got)
+		}
+	}
+}
+
+func TestCORSDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodOptions, "http://example.local/message", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("expected no CORS headers, got Access-Control-Allow-Origin %q", got)
+	}
+}
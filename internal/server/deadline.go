@@ -0,0 +1,59 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/logger"
+)
+
+// timeoutHeader lets a client bound how long /message may take, e.g. "X-Gotilert-Timeout: 2s".
+const timeoutHeader = "X-Gotilert-Timeout"
+
+// withClientDeadline derives a context bounded by the X-Gotilert-Timeout header (a Go duration).
+// Absent, malformed or non-positive values leave ctx unchanged; the forwarder's own timeout
+// still applies, and the earlier of the two deadlines wins.
+func withClientDeadline(ctx context.Context, request *http.Request) (context.Context, context.CancelFunc) {
+	raw := strings.TrimSpace(request.Header.Get(timeoutHeader))
+	if raw == "" {
+		return ctx, func() {}
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		logger.L().Debug("ignoring invalid "+timeoutHeader+" header",
+			"value", raw,
+			"request_id", RequestIDFromContext(ctx),
+		)
+
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,102 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageClientTimeoutHeaderBoundsForwardContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		header       string
+		wantDeadline bool
+	}{
+		{name: "valid duration", header: "2s", wantDeadline: true},
+		{name: "absent", header: "", wantDeadline: false},
+		{name: "malformed", header: "soon", wantDeadline: false},
+		{name: "non-positive", header: "-1s", wantDeadline: false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				deadline    time.Time
+				hasDeadline bool
+			)
+
+			httpServer, err := server.New(&server.Options{
+				ResolveApp: func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+				ForwardMessage: func(ctx context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+					deadline, hasDeadline = ctx.Deadline()
+
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("server.New: %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"http://example.local/message",
+				bytes.NewReader(mustJSON(t, gotify.MessageRequest{Message: "hello"})),
+			)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Gotify-Key", "TOKEN")
+
+			if testCase.header != "" {
+				req.Header.Set("X-Gotilert-Timeout", testCase.header)
+			}
+
+			httpServer.Handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			if hasDeadline != testCase.wantDeadline {
+				t.Fatalf("expected deadline=%t, got %t", testCase.wantDeadline, hasDeadline)
+			}
+
+			if hasDeadline && time.Until(deadline) > 2*time.Second {
+				t.Fatalf("expected a deadline within 2s, got %s", time.Until(deadline))
+			}
+		})
+	}
+}
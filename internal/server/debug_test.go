@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageDebugResponse(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(ctx context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+			server.ForwardDetailsFromContext(ctx).Record(
+				map[string]string{"alertname": "Test", "severity": "warning"},
+				map[string]string{"summary": "hi"},
+				5*time.Minute,
+			)
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	for _, query := range []string{"", "?debug=1"} {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message"+query, strings.NewReader(`{"message":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		var body struct {
+			Message  string                 `json:"message"`
+			Gotilert *server.ForwardDetails `json:"_gotilert"`
+		}
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+		}
+
+		if body.Message != "hi" {
+			t.Fatalf("%q: expected the Gotify fields to be kept, got %q", query, rec.Body.String())
+		}
+
+		if query == "" {
+			if body.Gotilert != nil {
+				t.Fatalf("expected no _gotilert object without ?debug=1, got %+v", body.Gotilert)
+			}
+
+			continue
+		}
+
+		if body.Gotilert == nil || body.Gotilert.Severity != "warning" || body.Gotilert.TTL != "5m0s" ||
+			body.Gotilert.Labels["alertname"] != "Test" || body.Gotilert.Annotations["summary"] != "hi" {
+			t.Fatalf("expected forward details, got %q", rec.Body.String())
+		}
+	}
+}
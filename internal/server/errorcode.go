@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+)
+
+// errorCodes maps sentinel errors to the stable "code" of JSON error bodies, so clients can
+// branch on it instead of parsing the human-readable "error". The first match wins, so
+// wrapping sentinels must precede the ones they wrap.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrTokenMissingOrInvalid, "token_invalid"},
+	{ErrUnauthorized, "unauthorized"},
+	{ErrSignatureInvalid, "signature_invalid"},
+	{ErrClientNotAllowed, "client_not_allowed"},
+	{ErrMethodNotAllowed, "method_not_allowed"},
+	{ErrRateLimited, "rate_limited"},
+	{ErrBodyTooLarge, "body_too_large"},
+	{gotify.ErrBatchTooLarge, "batch_too_large"},
+	{ErrUnsupportedEncoding, "unsupported_encoding"},
+	{ErrInvalidGzipBody, "invalid_gzip_body"},
+	{gotify.ErrUnsupportedContentType, "unsupported_content_type"},
+	{gotify.ErrMessageRequired, "message_required"},
+	{gotify.ErrInvalidPriority, "invalid_priority"},
+	{gotify.ErrInvalidExtras, "invalid_extras"},
+	{ErrIdempotencyKeyReused, "idempotency_key_reused"},
+	{ErrIdempotencyPending, "idempotency_pending"},
+	{ErrForwardQueueFull, "queue_full"},
+	{ErrForwardBusy, "forward_busy"},
+	{ErrNotReady, "not_ready"},
+	{ErrUpstreamRejected, "upstream_rejected"},
+	{ErrUpstreamTimeout, "upstream_timeout"},
+	{ErrUpstreamFailed, "upstream_failed"},
+	{ErrReloadRejected, "reload_rejected"},
+	{ErrInternalMisconfigured, "misconfigured"},
+}
+
+// errorCode returns the code for err, falling back to the snake-cased status text
+// (e.g. "bad_request") for errors without a sentinel.
+func errorCode(err error, status int) string {
+	for _, entry := range errorCodes {
+		if errors.Is(err, entry.err) {
+			return entry.code
+		}
+	}
+
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
@@ -0,0 +1,91 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestErrorBodyCodes(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			return errors.New("connection refused")
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		method      string
+		token       string
+		contentType string
+		body        string
+		want        string
+	}{
+		{method: http.MethodGet, want: "method_not_allowed"},
+		{method: http.MethodPost, token: "WRONG", want: "token_invalid"},
+		{method: http.MethodPost, token: "TOKEN", contentType: "text/plain", body: "hi", want: "unsupported_content_type"},
+		{method: http.MethodPost, token: "TOKEN", contentType: "application/json", body: `{}`, want: "message_required"},
+		{method: http.MethodPost, token: "TOKEN", contentType: "application/json", body: `{"message":`, want: "bad_request"},
+		{method: http.MethodPost, token: "TOKEN", contentType: "application/json", body: `{"message":"hi"}`, want: "upstream_failed"},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(testCase.method, "http://example.local/message", strings.NewReader(testCase.body))
+		req.Header.Set("Content-Type", testCase.contentType)
+		req.Header.Set("X-Gotify-Key", testCase.token)
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		var body struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+		}
+
+		if body.Code != testCase.want || body.Error == "" {
+			t.Fatalf("expected code %q with an error message, got %+v", testCase.want, body)
+		}
+	}
+}
@@ -32,4 +32,21 @@ var (
 	ErrMethodNotAllowed      = errors.New("method not allowed")
 	ErrInternalMisconfigured = errors.New("server is misconfigured")
 	ErrUpstreamFailed        = errors.New("upstream delivery failed")
+	ErrUpstreamRejected      = errors.New("upstream rejected the alert")
+	ErrUpstreamTimeout       = errors.New("upstream timed out")
+	ErrForwardQueueFull      = errors.New("forward queue is full")
+	ErrForwardBusy           = errors.New("too many concurrent forwards")
+	ErrNotReady              = errors.New("waiting for alertmanager to become ready")
+	ErrReloadRejected        = errors.New("reload rejected")
+	ErrRateLimited           = errors.New("rate limit exceeded")
+	ErrTLSConfig             = errors.New("invalid server tls configuration")
+	ErrUnixSocketPath        = errors.New("invalid unix socket listen address")
+	ErrBodyTooLarge          = errors.New("request body too large")
+	ErrUnsupportedEncoding   = errors.New("unsupported content encoding")
+	ErrInvalidGzipBody       = errors.New("invalid gzip request body")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrSignatureInvalid      = errors.New("missing or invalid request signature")
+	ErrClientNotAllowed      = errors.New("client address not allowed")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key reused with a different body")
+	ErrIdempotencyPending    = errors.New("request with the same idempotency key still in flight")
 )
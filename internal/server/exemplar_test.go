@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestRequestDurationCarriesTraceExemplar(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	metricsCollector := metrics.New()
+
+	httpServer, err := server.New(&server.Options{Metrics: metricsCollector})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.local/healthz", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+spanID+"-01")
+	httpServer.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Malformed trace ids are ignored: all-zero trace id.
+	req = httptest.NewRequest(http.MethodGet, "http://example.local/readyz", nil)
+	req.Header.Set("traceparent", "00-00000000000000000000000000000000-"+spanID+"-01")
+	httpServer.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	scrape := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	scrape.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, scrape)
+	body := rec.Body.String()
+
+	if strings.Count(body, `trace_id="`+traceID+`"`) != 1 || !strings.Contains(body, `span_id="`+spanID+`"`) {
+		t.Fatalf("expected exactly one exemplar for trace %s, got:\n%s", traceID, body)
+	}
+
+	for line := range strings.SplitSeq(body, "\n") {
+		if strings.Contains(line, `path="/readyz"`) && strings.Contains(line, "# {") {
+			t.Fatalf("expected no exemplar for an invalid traceparent, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageResponseEchoesExtrasVerbatim(t *testing.T) {
+	t.Parallel()
+
+	const extras = `{"client::display":{"contentType":"text/markdown"},"custom":{"id":12345678901234567890,"ok":true}}`
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"http://example.local/message",
+		strings.NewReader(`{"message":"hello","extras":`+extras+`}`),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		Extras json.RawMessage `json:"extras"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if !reflect.DeepEqual(decodeJSON(t, string(response.Extras)), decodeJSON(t, extras)) {
+		t.Fatalf("expected extras %s, got %s", extras, response.Extras)
+	}
+
+	if !strings.Contains(string(response.Extras), "12345678901234567890") {
+		t.Fatalf("expected large numbers to be echoed without rounding, got %s", response.Extras)
+	}
+}
+
+func decodeJSON(t *testing.T, raw string) any {
+	t.Helper()
+
+	var value any
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+
+	return value
+}
@@ -0,0 +1,92 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// ForwardDetails is what the forwarder derived from a message. It is echoed as "_gotilert"
+// in the /message response when the client asks for it with ?debug=1, so mappings can be
+// checked without looking at Alertmanager. All methods are no-ops on a nil receiver.
+type ForwardDetails struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Severity    string            `json:"severity,omitempty"`
+	TTL         string            `json:"ttl,omitempty"`
+
+	// Dropped is the reason the message was accepted but not forwarded (e.g. "rule").
+	Dropped string `json:"dropped,omitempty"`
+
+	// Resolved is set when the message resolved firing alerts instead of creating one.
+	Resolved bool `json:"resolved,omitempty"`
+}
+
+type forwardDetailsContextKey struct{}
+
+// ForwardDetailsFromContext returns the details the handler wants filled in, or nil when the
+// client did not ask for them.
+func ForwardDetailsFromContext(ctx context.Context) *ForwardDetails {
+	details, _ := ctx.Value(forwardDetailsContextKey{}).(*ForwardDetails)
+
+	return details
+}
+
+// ContextWithForwardDetails asks the forwarder to fill details while handling the message.
+func ContextWithForwardDetails(ctx context.Context, details *ForwardDetails) context.Context {
+	return context.WithValue(ctx, forwardDetailsContextKey{}, details)
+}
+
+// Record stores the labels and annotations of the alert sent upstream and its TTL.
+func (details *ForwardDetails) Record(labels, annotations map[string]string, ttl time.Duration) {
+	if details == nil {
+		return
+	}
+
+	details.Labels = maps.Clone(labels)
+	details.Annotations = maps.Clone(annotations)
+	details.Severity = labels["severity"]
+	details.TTL = ttl.String()
+}
+
+// MarkDropped records why the message was not forwarded.
+func (details *ForwardDetails) MarkDropped(reason string) {
+	if details == nil {
+		return
+	}
+
+	details.Dropped = reason
+}
+
+// MarkResolved records that the message was handled as a resolution.
+func (details *ForwardDetails) MarkResolved() {
+	if details == nil {
+		return
+	}
+
+	details.Resolved = true
+}
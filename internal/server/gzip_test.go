@@ -0,0 +1,142 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageAcceptsGzipBodies(t *testing.T) {
+	t.Parallel()
+
+	var forwarded gotify.MessageRequest
+
+	httpServer := newGzipTestServer(t, 1024, &forwarded)
+
+	rec := postMessage(httpServer, gzipBytes(t, `{"title":"backup","message":"done","priority":5}`), "gzip")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	if forwarded.Message != "done" || forwarded.Title != "backup" || forwarded.Priority != 5 {
+		t.Fatalf("expected decompressed message to be forwarded, got %+v", forwarded)
+	}
+}
+
+func TestMessageGzipLimitsDecompressedSize(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newGzipTestServer(t, 256, nil)
+
+	// Compresses to far less than 256 bytes but inflates past the limit.
+	body := gzipBytes(t, `{"message":"`+strings.Repeat("x", 4096)+`"}`)
+
+	rec := postMessage(httpServer, body, "gzip")
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusRequestEntityTooLarge, rec.Code, rec.Body.String())
+	}
+}
+
+func TestMessageRejectsUnknownOrBrokenEncodings(t *testing.T) {
+	t.Parallel()
+
+	httpServer := newGzipTestServer(t, 1024, nil)
+
+	rec := postMessage(httpServer, []byte(`{"message":"hi"}`), "br")
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusUnsupportedMediaType, rec.Code, rec.Body.String())
+	}
+
+	rec = postMessage(httpServer, []byte(`{"message":"hi"}`), "gzip")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
+
+func newGzipTestServer(t *testing.T, maxBodyBytes int64, forwarded *gotify.MessageRequest) *http.Server {
+	t.Helper()
+
+	httpServer, err := server.New(&server.Options{
+		MaxBodyBytes: maxBodyBytes,
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, msg gotify.MessageRequest, _ server.MessageID) error {
+			if forwarded != nil {
+				*forwarded = msg
+			}
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	return httpServer
+}
+
+func postMessage(httpServer *http.Server, body []byte, encoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", encoding)
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func gzipBytes(t *testing.T, payload string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+
+	_, err := writer.Write([]byte(payload))
+	if err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+
+	return buf.Bytes()
+}
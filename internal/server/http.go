@@ -30,9 +30,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/pprof"
+	"net/netip"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/leinardi/gotilert/internal/gotify"
 	"github.com/leinardi/gotilert/internal/logger"
 	"github.com/leinardi/gotilert/internal/metrics"
 )
@@ -42,6 +49,22 @@ const (
 	healthzPath = "/healthz"
 	readyzPath  = "/readyz"
 	messagePath = "/message"
+	reloadPath  = "/-/reload"
+	testPath    = "/-/test"
+	versionPath = "/version"
+	pprofPath   = "/debug/pprof/"
+
+	applicationPath = "/application"
+	currentUserPath = "/current/user"
+
+	// otherRoute is the route label for requests that match no registered pattern, so
+	// scanners probing random URLs cannot blow up metric cardinality.
+	otherRoute = "<other>"
+
+	unixAddrPrefix = "unix:"
+
+	// defaultMaxBatchLines caps NDJSON batches when Options.MaxBatchLines is 0.
+	defaultMaxBatchLines = 1000
 
 	okBody = "ok\n"
 )
@@ -63,13 +86,83 @@ type Options struct {
 
 	MaxBodyBytes int64
 
+	// MaxBatchLines caps the messages of an NDJSON /message batch (413 above it);
+	// 0 means the built-in default (1000).
+	MaxBatchLines int
+
+	// ParseOptions tunes /message body parsing (e.g. the fallback for a missing Content-Type).
+	ParseOptions gotify.ParseOptions
+
+	// TLS serves HTTPS when set; certificates are loaded (and validated) by New.
+	TLS *TLSOptions
+
 	Health HealthFunc
 	Ready  ReadyFunc
 
 	ResolveApp     ResolveAppFunc
 	ForwardMessage ForwardMessageFunc
 
+	// AsyncForward answers /message with 202 Accepted: ForwardMessage only queues the alert.
+	// A forwarder error wrapping ErrForwardQueueFull is answered with 429.
+	AsyncForward bool
+
+	// Idempotency replays the stored response to /message retries carrying the same
+	// Idempotency-Key (per token) instead of forwarding again; nil disables it.
+	Idempotency *IdempotencyOptions
+
+	// TestAlert enables POST /-/test when set: callers presenting an app token get a synthetic
+	// alert forwarded and the upstream result back.
+	TestAlert TestAlertFunc
+
+	// Reload enables POST /-/reload when set; callers must present a token accepted by AuthorizeAdmin.
+	Reload         ReloadFunc
+	AuthorizeAdmin AuthorizeAdminFunc
+
 	Metrics *metrics.Metrics
+
+	// MetricsAuth, when set, requires credentials on /metrics only (probes stay open).
+	MetricsAuth *MetricsAuth
+
+	// GotifyErrors reports errors in Gotify's {error, errorCode, errorDescription} envelope
+	// instead of the slim {error}.
+	GotifyErrors bool
+
+	// GotifyEndpoints enables the Gotify-compatible GET /application and GET /current/user.
+	// Both require a token accepted by ResolveApp; /application lists only the caller's app.
+	GotifyEndpoints bool
+
+	// BuildInfo enables GET /version when set.
+	BuildInfo *BuildInfo
+
+	// RoutePrefix mounts every route under a base path (e.g. "/gotilert" serves
+	// "/gotilert/message"). It is normalized by NormalizeRoutePrefix; empty keeps root paths.
+	RoutePrefix string
+
+	// EnablePprof registers the net/http/pprof handlers under <RoutePrefix>/debug/pprof/.
+	// Off by default: profiles expose internals and can be expensive to compute.
+	EnablePprof bool
+
+	// LogProbes logs /healthz and /readyz requests at info like every other route. When false
+	// they are logged at debug so frequent orchestrator probes do not flood the access log.
+	LogProbes bool
+
+	// AllowedClients restricts /message to client IPs within these prefixes (403 otherwise,
+	// before any token check); empty allows every client.
+	AllowedClients []netip.Prefix
+
+	// TrustProxy treats every connection peer as a reverse proxy: the client IP is taken
+	// from X-Forwarded-For (see TrustedProxies).
+	TrustProxy bool
+
+	// TrustedProxies lists reverse proxies whose X-Forwarded-For is honored. The client IP
+	// is the rightmost entry that is not a trusted proxy; requests from other peers keep
+	// the peer address, so spoofed headers are ignored. The resolved IP is logged and
+	// available via ClientIPFromContext.
+	TrustedProxies []netip.Prefix
+
+	// CORS answers browser preflights and sets Access-Control-Allow-* headers on /message
+	// for the allowed origins; nil disables CORS.
+	CORS *CORSOptions
 }
 
 // New returns a configured *http.Server with handlers and timeouts.
@@ -78,7 +171,42 @@ func New(opts *Options) (*http.Server, error) {
 		return nil, ErrServerOptionsNil
 	}
 
+	mux := newMux(opts)
+
+	handler := withRequestLogging(opts.Metrics, mux, quietRoutes(opts))
+	if opts.GotifyErrors {
+		handler = withGotifyErrors(handler)
+	}
+
+	handler = withRequestID(withClientIP(
+		clientIPResolver{trustPeer: opts.TrustProxy, trusted: opts.TrustedProxies},
+		withTraceContext(handler),
+	))
+
+	srv := &http.Server{
+		Addr:         opts.Addr,
+		Handler:      handler,
+		ReadTimeout:  opts.ReadTimeout,
+		WriteTimeout: opts.WriteTimeout,
+		IdleTimeout:  opts.IdleTimeout,
+	}
+
+	if opts.TLS != nil {
+		tlsConfig, err := buildTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, err
+		}
+
+		srv.TLSConfig = tlsConfig
+	}
+
+	return srv, nil
+}
+
+// newMux registers every route under opts.RoutePrefix.
+func newMux(opts *Options) *http.ServeMux {
 	mux := http.NewServeMux()
+	prefix := NormalizeRoutePrefix(opts.RoutePrefix)
 
 	healthFunc := opts.Health
 	if healthFunc == nil {
@@ -95,35 +223,109 @@ func New(opts *Options) (*http.Server, error) {
 		maxBodyBytes = 1 << 20 // 1 MiB
 	}
 
-	mux.HandleFunc(healthzPath, healthHandler(healthFunc))
-	mux.HandleFunc(readyzPath, readyHandler(readyFunc))
-	mux.HandleFunc(messagePath, messageHandler(opts.ResolveApp, opts.ForwardMessage, maxBodyBytes))
+	parseOptions := opts.ParseOptions
+
+	parseOptions.MaxBatchLines = opts.MaxBatchLines
+	if parseOptions.MaxBatchLines == 0 {
+		parseOptions.MaxBatchLines = defaultMaxBatchLines
+	}
+
+	mux.HandleFunc(prefix+healthzPath, healthHandler(healthFunc))
+	mux.HandleFunc(prefix+readyzPath, readyHandler(readyFunc))
+	// /-/test sends real alerts, so it shares the /message allowlist and per-app rate limits.
+	limiter := newRateLimiter()
+
+	mux.HandleFunc(prefix+messagePath, withCORS(opts.CORS, withAllowedClients(opts.AllowedClients, messageHandler(
+		opts.ResolveApp,
+		opts.ForwardMessage,
+		opts.AsyncForward,
+		maxBodyBytes,
+		parseOptions,
+		limiter,
+		newIdempotencyCache(opts.Idempotency),
+		opts.Metrics,
+	))))
+
+	if opts.TestAlert != nil {
+		mux.HandleFunc(prefix+testPath, withAllowedClients(opts.AllowedClients, testAlertHandler(
+			opts.ResolveApp,
+			opts.TestAlert,
+			limiter,
+			opts.Metrics,
+		)))
+	}
+
+	if opts.Reload != nil {
+		mux.HandleFunc(prefix+reloadPath, reloadHandler(opts.Reload, opts.AuthorizeAdmin))
+	}
+
+	if opts.GotifyEndpoints {
+		mux.HandleFunc(prefix+applicationPath, applicationHandler(opts.ResolveApp))
+		mux.HandleFunc(prefix+currentUserPath, currentUserHandler(opts.ResolveApp))
+	}
+
+	if opts.BuildInfo != nil {
+		mux.HandleFunc(prefix+versionPath, versionHandler(*opts.BuildInfo))
+	}
 
 	if opts.Metrics != nil {
-		mux.Handle(metricsPath, opts.Metrics.Handler())
+		mux.Handle(prefix+metricsPath, requireMetricsAuth(opts.MetricsAuth, opts.Metrics.Handler()))
 	}
 
-	handler := withRequestLogging(opts.Metrics, mux)
+	if opts.EnablePprof {
+		registerPprof(mux, prefix)
+	}
 
-	srv := &http.Server{
-		Addr:         opts.Addr,
-		Handler:      handler,
-		ReadTimeout:  opts.ReadTimeout,
-		WriteTimeout: opts.WriteTimeout,
-		IdleTimeout:  opts.IdleTimeout,
+	return mux
+}
+
+// NormalizeRoutePrefix returns prefix with a leading slash and no trailing slash;
+// an empty (or "/") prefix stays empty so routes keep their root paths.
+func NormalizeRoutePrefix(prefix string) string {
+	trimmed := strings.Trim(strings.TrimSpace(prefix), "/")
+	if trimmed == "" {
+		return ""
 	}
 
-	return srv, nil
+	return "/" + trimmed
 }
 
-// ListenAndServe starts the server and blocks until it exits.
+func registerPprof(mux *http.ServeMux, prefix string) {
+	// pprof.Index resolves named profiles (heap, goroutine, allocs, ...) from the path
+	// relative to /debug/pprof/, so the route prefix is stripped before it runs.
+	handle := func(path string, handler http.HandlerFunc) {
+		mux.Handle(prefix+path, http.StripPrefix(prefix, handler))
+	}
+
+	handle(pprofPath, pprof.Index)
+	handle(pprofPath+"cmdline", pprof.Cmdline)
+	handle(pprofPath+"profile", pprof.Profile)
+	handle(pprofPath+"symbol", pprof.Symbol)
+	handle(pprofPath+"trace", pprof.Trace)
+}
+
+// ListenAndServe starts the server (HTTPS when srv.TLSConfig is set) and blocks until it exits.
+// An Addr of the form "unix:/path/to.sock" listens on a Unix domain socket instead of TCP.
 // It returns http.ErrServerClosed on normal shutdown.
 func ListenAndServe(srv *http.Server) error {
 	if srv == nil {
 		return ErrServerNil
 	}
 
-	err := srv.ListenAndServe()
+	var err error
+
+	socketPath, isUnix := UnixSocketPath(srv.Addr)
+
+	switch {
+	case isUnix:
+		err = serveUnix(srv, socketPath)
+	case srv.TLSConfig != nil:
+		// Certificates are already in TLSConfig.
+		err = srv.ListenAndServeTLS("", "")
+	default:
+		err = srv.ListenAndServe()
+	}
+
 	if err != nil {
 		return fmt.Errorf("listen and serve: %w", err)
 	}
@@ -131,6 +333,60 @@ func ListenAndServe(srv *http.Server) error {
 	return nil
 }
 
+// UnixSocketPath returns the socket path of a "unix:<path>" listen address.
+func UnixSocketPath(addr string) (string, bool) {
+	path, ok := strings.CutPrefix(addr, unixAddrPrefix)
+
+	return path, ok
+}
+
+func serveUnix(srv *http.Server, socketPath string) error {
+	if socketPath == "" {
+		return fmt.Errorf("%w: %q", ErrUnixSocketPath, srv.Addr)
+	}
+
+	err := removeStaleSocket(socketPath)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listen on unix socket %q: %w", socketPath, err)
+	}
+
+	// The listener unlinks the socket file when the server closes it on shutdown.
+	if srv.TLSConfig != nil {
+		return srv.ServeTLS(listener, "", "") //nolint:wrapcheck // wrapped by ListenAndServe.
+	}
+
+	return srv.Serve(listener) //nolint:wrapcheck // wrapped by ListenAndServe.
+}
+
+// removeStaleSocket deletes a leftover socket file (e.g. after a crash) but refuses to
+// remove anything that is not a socket.
+func removeStaleSocket(socketPath string) error {
+	info, err := os.Lstat(socketPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("stat unix socket %q: %w", socketPath, err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%w: %q exists and is not a socket", ErrUnixSocketPath, socketPath)
+	}
+
+	err = os.Remove(socketPath)
+	if err != nil {
+		return fmt.Errorf("remove stale unix socket %q: %w", socketPath, err)
+	}
+
+	return nil
+}
+
 // Shutdown gracefully shuts down the server with the given timeout.
 func Shutdown(ctx context.Context, srv *http.Server, timeout time.Duration) error {
 	if srv == nil {
@@ -206,33 +462,82 @@ func (recorder *statusRecorder) WriteHeader(code int) {
 	recorder.ResponseWriter.WriteHeader(code)
 }
 
-func withRequestLogging(metricsCollector *metrics.Metrics, next http.Handler) http.Handler {
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
+
+// routeOf returns the mux pattern that serves request (e.g. "/message" or "/debug/pprof/"),
+// or otherRoute when nothing matches.
+func routeOf(mux *http.ServeMux, request *http.Request) string {
+	_, pattern := mux.Handler(request)
+	if pattern == "" {
+		return otherRoute
+	}
+
+	return pattern
+}
+
+// quietRoutes returns the routes whose access log is downgraded to debug: the probe
+// endpoints unless opts.LogProbes is set.
+func quietRoutes(opts *Options) map[string]bool {
+	if opts.LogProbes {
+		return nil
+	}
+
+	prefix := NormalizeRoutePrefix(opts.RoutePrefix)
+
+	return map[string]bool{
+		prefix + healthzPath: true,
+		prefix + readyzPath:  true,
+	}
+}
+
+func withRequestLogging(
+	metricsCollector *metrics.Metrics,
+	mux *http.ServeMux,
+	quiet map[string]bool,
+) http.Handler {
 	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 		start := time.Now()
+		route := routeOf(mux, request)
+
+		ctx, span := startServerSpan(request, route)
+		request = request.WithContext(ctx)
 
 		recorder := &statusRecorder{
 			ResponseWriter: responseWriter,
 			status:         http.StatusOK,
 		}
 
-		next.ServeHTTP(recorder, request)
+		mux.ServeHTTP(recorder, request)
 
 		duration := time.Since(start)
 
-		logger.L().Info("http request",
+		endServerSpan(span, recorder.status)
+
+		level := slog.LevelInfo
+		if quiet[route] {
+			level = slog.LevelDebug
+		}
+
+		logger.L().Log(request.Context(), level, "http request",
+			"request_id", RequestIDFromContext(request.Context()),
+			"client_ip", clientIPString(ClientIPFromContext(request.Context())),
 			"method", request.Method,
 			"path", request.URL.Path,
+			"route", route,
 			"status", recorder.status,
 			"duration", duration.String(),
 		)
 
 		if metricsCollector != nil {
-			// Path cardinality is low (fixed endpoints).
 			metricsCollector.ObserveRequest(
 				request.Method,
-				request.URL.Path,
+				route,
 				recorder.status,
 				duration,
+				TraceFromContext(request.Context()),
 			)
 		}
 	})
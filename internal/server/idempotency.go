@@ -0,0 +1,278 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLen ignores oversized keys instead of holding them in memory.
+	maxIdempotencyKeyLen = 255
+
+	defaultIdempotencyMaxEntries = 10000
+)
+
+// IdempotencyOptions enables Idempotency-Key handling on /message: a successful response is
+// kept for TTL (counted from when it was written) per (token, key) and replayed to retries
+// instead of forwarding again.
+type IdempotencyOptions struct {
+	TTL time.Duration
+	// MaxEntries bounds the cache; the oldest keys are evicted first (default 10000).
+	MaxEntries int
+}
+
+type idempotencyEntry struct {
+	key      string
+	bodyHash [sha256.Size]byte
+	expires  time.Time
+	element  *list.Element
+
+	// done is closed once the first request finished; response is nil when it failed.
+	done     chan struct{}
+	response *idempotentResponse
+}
+
+type idempotentResponse struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+// idempotencyCache is safe for concurrent use. Entries share one TTL and are moved to the back
+// of the list whenever their expiry is (re)set, so the list doubles as the eviction and
+// expiry queue.
+type idempotencyCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*idempotencyEntry
+	order   *list.List
+}
+
+// newIdempotencyCache returns nil (idempotency disabled) for nil options or a zero TTL.
+func newIdempotencyCache(opts *IdempotencyOptions) *idempotencyCache {
+	if opts == nil || opts.TTL <= 0 {
+		return nil
+	}
+
+	maxEntries := opts.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+
+	return &idempotencyCache{
+		ttl:        opts.TTL,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*idempotencyEntry),
+		order:      list.New(),
+	}
+}
+
+// start looks up the request's Idempotency-Key. It returns the stored response to replay, or
+// a recorder that must replace responseWriter and be finished once the response is written.
+// Both are nil when the request carries no usable key. A duplicate of a request still in
+// flight waits for it, so concurrent client retries are forwarded once; it gets
+// ErrIdempotencyPending if its own context ends first. Reusing a key with a different body
+// returns ErrIdempotencyKeyReused.
+func (cache *idempotencyCache) start(
+	responseWriter http.ResponseWriter,
+	request *http.Request,
+) (*idempotentResponse, *idempotencyRecorder, error) {
+	if cache == nil {
+		return nil, nil, nil
+	}
+
+	key := strings.TrimSpace(request.Header.Get(idempotencyKeyHeader))
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return nil, nil, nil
+	}
+
+	bodyHash, err := hashBody(request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheKey := idempotencyCacheKey(extractToken(request), key)
+
+	cache.mutex.Lock()
+	cache.evict(cache.now())
+
+	entry, found := cache.entries[cacheKey]
+	if !found {
+		// In-flight entries expire after TTL too, so a stuck request cannot pin its key.
+		entry = &idempotencyEntry{key: cacheKey, bodyHash: bodyHash, expires: cache.now().Add(cache.ttl), done: make(chan struct{})}
+		entry.element = cache.order.PushBack(entry)
+		cache.entries[cacheKey] = entry
+		cache.evict(cache.now())
+	}
+	cache.mutex.Unlock()
+
+	if !found {
+		return nil, &idempotencyRecorder{ResponseWriter: responseWriter, cache: cache, entry: entry}, nil
+	}
+
+	if entry.bodyHash != bodyHash {
+		return nil, nil, ErrIdempotencyKeyReused
+	}
+
+	select {
+	case <-entry.done:
+	case <-request.Context().Done():
+		return nil, nil, fmt.Errorf("%w: %w", ErrIdempotencyPending, request.Context().Err())
+	}
+
+	// The first request failed: handle this one normally, without recording it.
+	return entry.response, nil, nil
+}
+
+// idempotencyCacheKey scopes key to the app token by its SHA-256, so the cache never holds
+// plaintext tokens for the whole TTL.
+func idempotencyCacheKey(token, key string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(tokenHash[:]) + "\x00" + key
+}
+
+// hashBody reads the (already limited and decoded) body, puts it back for parsing and
+// returns its SHA-256.
+func hashBody(request *http.Request) ([sha256.Size]byte, error) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("read body: %w", err)
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return sha256.Sum256(body), nil
+}
+
+// evict drops expired entries and, past maxEntries, the oldest ones. The caller holds the mutex.
+func (cache *idempotencyCache) evict(now time.Time) {
+	for front := cache.order.Front(); front != nil; front = cache.order.Front() {
+		entry, _ := front.Value.(*idempotencyEntry)
+		if cache.order.Len() <= cache.maxEntries && now.Before(entry.expires) {
+			return
+		}
+
+		cache.remove(entry)
+	}
+}
+
+func (cache *idempotencyCache) remove(entry *idempotencyEntry) {
+	if cache.entries[entry.key] != entry {
+		return
+	}
+
+	delete(cache.entries, entry.key)
+	cache.order.Remove(entry.element)
+}
+
+// finish stores a successful response for replays; failures are forgotten so a retry is
+// forwarded again.
+func (cache *idempotencyCache) finish(entry *idempotencyEntry, response *idempotentResponse) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if response.status >= http.StatusOK && response.status < http.StatusMultipleChoices {
+		entry.response = response
+
+		// The TTL counts from the stored response, not from when the request arrived.
+		if cache.entries[entry.key] == entry {
+			entry.expires = cache.now().Add(cache.ttl)
+			cache.order.MoveToBack(entry.element)
+		}
+	} else {
+		cache.remove(entry)
+	}
+
+	close(entry.done)
+}
+
+func (response *idempotentResponse) writeTo(responseWriter http.ResponseWriter) {
+	if response.contentType != "" {
+		responseWriter.Header().Set("Content-Type", response.contentType)
+	}
+
+	responseWriter.Header().Set(idempotencyReplayedHeader, "true")
+	responseWriter.WriteHeader(response.status)
+	_, _ = responseWriter.Write(response.body)
+}
+
+// idempotencyRecorder tees the response of the first request for a key.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+
+	cache  *idempotencyCache
+	entry  *idempotencyEntry
+	status int
+	body   bytes.Buffer
+}
+
+func (recorder *idempotencyRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *idempotencyRecorder) Write(data []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	recorder.body.Write(data)
+
+	return recorder.ResponseWriter.Write(data) //nolint:wrapcheck // plain ResponseWriter passthrough.
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (recorder *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
+
+func (recorder *idempotencyRecorder) finish() {
+	recorder.cache.finish(recorder.entry, &idempotentResponse{
+		status:      recorder.status,
+		contentType: recorder.Header().Get("Content-Type"),
+		body:        recorder.body.Bytes(),
+	})
+}
@@ -0,0 +1,64 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyCacheDoesNotHoldPlaintextTokens(t *testing.T) {
+	t.Parallel()
+
+	const token = "SECRET-APP-TOKEN"
+
+	cache := newIdempotencyCache(&IdempotencyOptions{TTL: time.Minute})
+
+	request := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"m"}`))
+	request.Header.Set("X-Gotify-Key", token)
+	request.Header.Set(idempotencyKeyHeader, "retry-1")
+
+	_, recorder, err := cache.start(httptest.NewRecorder(), request)
+	if err != nil || recorder == nil {
+		t.Fatalf("expected a recorder for a new key, got %v, %v", recorder, err)
+	}
+
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected one cache entry, got %d", len(cache.entries))
+	}
+
+	for key := range cache.entries {
+		if strings.Contains(key, token) {
+			t.Fatalf("expected the cache key to hold no plaintext token, got %q", key)
+		}
+
+		if !strings.HasSuffix(key, "\x00retry-1") {
+			t.Fatalf("expected the cache key to end with the idempotency key, got %q", key)
+		}
+	}
+}
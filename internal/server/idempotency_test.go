@@ -0,0 +1,277 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+var errUpstreamDown = errors.New("upstream down")
+
+func newIdempotentServer(t *testing.T, maxEntries int, failing *atomic.Bool) (*http.Server, *metrics.Metrics, *atomic.Int32) {
+	t.Helper()
+
+	var forwards atomic.Int32
+
+	metricsCollector := metrics.New()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) { return server.App{Name: "app-" + token, ID: 1}, true },
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			forwards.Add(1)
+
+			if failing != nil && failing.Load() {
+				return errUpstreamDown
+			}
+
+			return nil
+		},
+		Idempotency: &server.IdempotencyOptions{TTL: time.Minute, MaxEntries: maxEntries},
+		Metrics:     metricsCollector,
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	return httpServer, metricsCollector, &forwards
+}
+
+func postIdempotent(httpServer *http.Server, token, key string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader("message=hello"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Gotify-Key", token)
+	req.Header.Set("Idempotency-Key", key)
+
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestIdempotencyKeyReplaysResponse(t *testing.T) {
+	t.Parallel()
+
+	httpServer, metricsCollector, forwards := newIdempotentServer(t, 0, nil)
+
+	first := postIdempotent(httpServer, "TOKEN", "retry-1")
+	replay := postIdempotent(httpServer, "TOKEN", "retry-1")
+
+	if first.Code != http.StatusOK || replay.Code != http.StatusOK {
+		t.Fatalf("expected 200 twice, got %d and %d", first.Code, replay.Code)
+	}
+
+	if got := forwards.Load(); got != 1 {
+		t.Fatalf("expected 1 forward, got %d", got)
+	}
+
+	if replay.Body.String() != first.Body.String() {
+		t.Fatalf("expected replayed body %q, got %q", first.Body.String(), replay.Body.String())
+	}
+
+	if replay.Header().Get("Idempotent-Replayed") != "true" || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("expected only the replay to carry Idempotent-Replayed")
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `gotilert_idempotent_replays_total{app="app-TOKEN"} 1`
+	if !strings.Contains(rec.Body.String(), want) {
+		t.Fatalf("expected %q in metrics output:\n%s", want, rec.Body.String())
+	}
+}
+
+func TestIdempotencyKeyScopedPerToken(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, forwards := newIdempotentServer(t, 0, nil)
+
+	postIdempotent(httpServer, "TOKEN-A", "retry-1")
+	postIdempotent(httpServer, "TOKEN-B", "retry-1")
+
+	if got := forwards.Load(); got != 2 {
+		t.Fatalf("expected one forward per token, got %d", got)
+	}
+}
+
+func TestIdempotencyKeyForgetsFailures(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+
+	failing.Store(true)
+
+	httpServer, _, forwards := newIdempotentServer(t, 0, &failing)
+
+	if rec := postIdempotent(httpServer, "TOKEN", "retry-1"); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+
+	failing.Store(false)
+
+	if rec := postIdempotent(httpServer, "TOKEN", "retry-1"); rec.Code != http.StatusOK {
+		t.Fatalf("expected the retry to be forwarded again, got %d", rec.Code)
+	}
+
+	if got := forwards.Load(); got != 2 {
+		t.Fatalf("expected 2 forwards, got %d", got)
+	}
+}
+
+func TestIdempotencyCacheIsBounded(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, forwards := newIdempotentServer(t, 1, nil)
+
+	postIdempotent(httpServer, "TOKEN", "key-a")
+	postIdempotent(httpServer, "TOKEN", "key-b")
+	postIdempotent(httpServer, "TOKEN", "key-a")
+
+	if got := forwards.Load(); got != 3 {
+		t.Fatalf("expected the evicted key to be forwarded again, got %d forwards", got)
+	}
+}
+
+func TestIdempotencyKeyRejectsDifferentBody(t *testing.T) {
+	t.Parallel()
+
+	httpServer, _, forwards := newIdempotentServer(t, 0, nil)
+
+	if rec := postIdempotent(httpServer, "TOKEN", "retry-1"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader("message=other"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+	req.Header.Set("Idempotency-Key", "retry-1")
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), "idempotency_key_reused") {
+		t.Fatalf("expected 422 idempotency_key_reused, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if got := forwards.Load(); got != 1 {
+		t.Fatalf("expected the mismatched retry not to be forwarded, got %d forwards", got)
+	}
+}
+
+func TestIdempotencyKeyWaitingDuplicateGivesUp(t *testing.T) {
+	t.Parallel()
+
+	var forwards atomic.Int32
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			if forwards.Add(1) == 1 {
+				close(entered)
+				<-release
+			}
+
+			return nil
+		},
+		Idempotency: &server.IdempotencyOptions{TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	firstDone := make(chan int)
+
+	go func() {
+		firstDone <- postIdempotent(httpServer, "TOKEN", "retry-1").Code
+	}()
+
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "http://example.local/message", strings.NewReader("message=hello"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+	req.Header.Set("Idempotency-Key", "retry-1")
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "idempotency_pending") {
+		t.Fatalf("expected 503 idempotency_pending, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	close(release)
+
+	if status := <-firstDone; status != http.StatusOK {
+		t.Fatalf("expected the first request to succeed, got %d", status)
+	}
+
+	if got := forwards.Load(); got != 1 {
+		t.Fatalf("expected the abandoned duplicate not to be forwarded, got %d forwards", got)
+	}
+}
+
+func TestIdempotencyTTLCountsFromResponse(t *testing.T) {
+	t.Parallel()
+
+	const ttl = 100 * time.Millisecond
+
+	var forwards atomic.Int32
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(string) (server.App, bool) { return server.App{Name: "app", ID: 1}, true },
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			forwards.Add(1)
+			// Outlive the TTL while forwarding.
+			time.Sleep(2 * ttl)
+
+			return nil
+		},
+		Idempotency: &server.IdempotencyOptions{TTL: ttl},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	postIdempotent(httpServer, "TOKEN", "retry-1")
+
+	if rec := postIdempotent(httpServer, "TOKEN", "retry-1"); rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected an immediate retry to be replayed, got %d forwards", forwards.Load())
+	}
+}
@@ -25,72 +25,165 @@
 package server
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
-	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/leinardi/gotilert/internal/gotify"
 	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/metrics"
 )
 
-var messageID atomic.Uint64
+// messageAllowedMethods is the Allow header of /message (OPTIONS and 405 responses).
+const messageAllowedMethods = "POST, OPTIONS"
 
 func messageHandler(
 	resolve ResolveAppFunc,
 	forward ForwardMessageFunc,
+	async bool,
 	maxBodyBytes int64,
+	parseOptions gotify.ParseOptions,
+	limiter *rateLimiter,
+	idempotency *idempotencyCache,
+	metricsCollector *metrics.Metrics,
 ) http.HandlerFunc {
 	return func(responseWriter http.ResponseWriter, request *http.Request) {
-		if request.Method != http.MethodPost {
-			writeJSONError(responseWriter, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+		switch request.Method {
+		case http.MethodPost:
+		case http.MethodOptions:
+			responseWriter.Header().Set("Allow", messageAllowedMethods)
+			responseWriter.WriteHeader(http.StatusNoContent)
+
+			return
+		default:
+			responseWriter.Header().Set("Allow", messageAllowedMethods)
+			writeJSONError(responseWriter, request, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
 
 			return
 		}
 
 		app, ok := authenticate(request, resolve)
 		if !ok {
-			writeJSONError(responseWriter, http.StatusForbidden, ErrTokenMissingOrInvalid)
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrTokenMissingOrInvalid)
 
 			return
 		}
 
-		request.Body = http.MaxBytesReader(responseWriter, request.Body, maxBodyBytes)
+		allowed, wait := limiter.allow(app.Name, app.RateLimit, time.Now())
+		if !allowed {
+			metricsCollector.IncRateLimited(app.Name)
+			writeRateLimited(responseWriter, request, wait)
+
+			return
+		}
+
+		if app.SigningSecret != "" {
+			// The signature covers the bytes on the wire, so it is checked before gzip decoding.
+			request.Body = http.MaxBytesReader(responseWriter, request.Body, maxBodyBytes)
+
+			err := verifySignature(request, app.SigningSecret)
+			if err != nil {
+				writeSignatureError(responseWriter, request, err)
+
+				return
+			}
+		}
 
-		msg, err := gotify.ParseMessageRequest(request)
+		err := decodeBody(responseWriter, request, maxBodyBytes)
 		if err != nil {
-			writeParseError(responseWriter, err)
+			writeDecodeError(responseWriter, request, err)
+
+			return
+		}
+
+		replay, recorder, err := idempotency.start(responseWriter, request)
+		if err != nil {
+			writeIdempotencyError(responseWriter, request, err)
+
+			return
+		}
+
+		if replay != nil {
+			metricsCollector.IncIdempotentReplay(app.Name)
+			replay.writeTo(responseWriter)
 
 			return
 		}
 
-		messageIdentifier := messageID.Add(1)
+		if recorder != nil {
+			responseWriter = recorder
+			defer recorder.finish()
+		}
+
+		ctx, cancel := withClientDeadline(request.Context(), request)
+		defer cancel()
+
+		request = request.WithContext(ctx)
+
+		if gotify.IsBatch(request) {
+			// Every message of a batch costs a token; the request itself already paid for one.
+			chargeLines := func(lines int) (time.Duration, error) {
+				if app.RateLimit.enabled() && float64(lines) > app.RateLimit.burst() {
+					// Retrying could never succeed: the bucket never holds more than the burst.
+					return 0, fmt.Errorf("%w: %d messages exceed the rate limit burst of %.0f",
+						gotify.ErrBatchTooLarge, lines, app.RateLimit.burst())
+				}
+
+				allowed, wait := limiter.allowN(app.Name, app.RateLimit, lines-1, time.Now())
+				if !allowed {
+					metricsCollector.IncRateLimited(app.Name)
+
+					return wait, ErrRateLimited
+				}
+
+				return 0, nil
+			}
+
+			forwardBatch(responseWriter, request, app, forward, async, parseOptions, chargeLines)
+
+			return
+		}
+
+		msg, err := gotify.ParseMessageRequest(request, parseOptions)
+		if err != nil {
+			writeParseError(responseWriter, request, err)
+
+			return
+		}
+
+		messageIdentifier := nextMessageID()
 
 		if forward == nil {
-			writeJSONError(responseWriter, http.StatusInternalServerError, ErrInternalMisconfigured)
+			writeJSONError(responseWriter, request, http.StatusInternalServerError, ErrInternalMisconfigured)
 
 			return
 		}
 
-		ctx := request.Context()
+		var details *ForwardDetails
+		if wantsDebug(request) {
+			details = &ForwardDetails{}
+			ctx = ContextWithForwardDetails(ctx, details)
+		}
 
 		err = forward(ctx, app, msg, messageIdentifier)
 		if err != nil {
-			// Forwarder logs upstream failures with context; return 502.
-			writeJSONError(
-				responseWriter,
-				http.StatusBadGateway,
-				fmt.Errorf("%w", ErrUpstreamFailed),
-			)
+			// Forwarder logs upstream failures with context.
+			writeForwardError(responseWriter, request, err)
 
 			return
 		}
 
 		resp := gotify.MessageResponse{
-			ID:       messageIdentifier,
+			ID:       messageIdentifier.Seq,
 			AppID:    app.ID,
 			Message:  msg.Message,
 			Title:    msg.Title,
@@ -99,10 +192,35 @@ func messageHandler(
 			Extras:   msg.Extras,
 		}
 
-		writeJSON(responseWriter, http.StatusOK, resp)
+		status := http.StatusOK
+		if async {
+			status = http.StatusAccepted
+		}
+
+		if details != nil {
+			writeJSON(responseWriter, status, debugMessageResponse{MessageResponse: resp, Gotilert: details})
+
+			return
+		}
+
+		writeJSON(responseWriter, status, resp)
 	}
 }
 
+// debugMessageResponse extends the Gotify response with what gotilert derived (?debug=1).
+type debugMessageResponse struct {
+	gotify.MessageResponse
+
+	Gotilert *ForwardDetails `json:"_gotilert"`
+}
+
+// wantsDebug reports whether the client asked for ForwardDetails with ?debug=1 (or true).
+func wantsDebug(request *http.Request) bool {
+	debug, err := strconv.ParseBool(request.URL.Query().Get("debug"))
+
+	return err == nil && debug
+}
+
 func authenticate(request *http.Request, resolve ResolveAppFunc) (App, bool) {
 	if resolve == nil {
 		return App{}, false
@@ -118,16 +236,206 @@ func authenticate(request *http.Request, resolve ResolveAppFunc) (App, bool) {
 	return app, ok
 }
 
-func writeParseError(responseWriter http.ResponseWriter, err error) {
+// decodeBody bounds the request body by maxBodyBytes and transparently inflates
+// Content-Encoding: gzip. The limit applies to the decompressed size as well, so a small
+// compressed body cannot expand past it.
+func decodeBody(responseWriter http.ResponseWriter, request *http.Request, maxBodyBytes int64) error {
+	request.Body = http.MaxBytesReader(responseWriter, request.Body, maxBodyBytes)
+
+	encoding := strings.ToLower(strings.TrimSpace(request.Header.Get("Content-Encoding")))
+
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+
+	gzipReader, err := gzip.NewReader(request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err //nolint:wrapcheck // writeParseError matches *http.MaxBytesError.
+		}
+
+		return fmt.Errorf("%w: %w", ErrInvalidGzipBody, err)
+	}
+
+	request.Body = http.MaxBytesReader(responseWriter, gzipReader, maxBodyBytes)
+	request.Header.Del("Content-Encoding")
+	request.Header.Del("Content-Length")
+	request.ContentLength = -1
+
+	return nil
+}
+
+func writeDecodeError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	if errors.Is(err, ErrUnsupportedEncoding) {
+		writeJSONError(responseWriter, request, http.StatusUnsupportedMediaType, err)
+
+		return
+	}
+
+	if errors.Is(err, ErrInvalidGzipBody) {
+		writeJSONError(responseWriter, request, http.StatusBadRequest, err)
+
+		return
+	}
+
+	writeParseError(responseWriter, request, err)
+}
+
+// writeIdempotencyError answers 422 for a reused key, 503 when a duplicate gave up waiting for
+// the request in flight and like a parse error otherwise (e.g. an oversized body).
+func writeIdempotencyError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrIdempotencyKeyReused):
+		writeJSONError(responseWriter, request, http.StatusUnprocessableEntity, err)
+	case errors.Is(err, ErrIdempotencyPending):
+		writeJSONError(responseWriter, request, http.StatusServiceUnavailable, err)
+	default:
+		writeParseError(responseWriter, request, err)
+	}
+}
+
+func writeRateLimited(responseWriter http.ResponseWriter, request *http.Request, wait time.Duration) {
+	responseWriter.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(wait)))
+	writeJSONError(responseWriter, request, http.StatusTooManyRequests, ErrRateLimited)
+}
+
+func writeSignatureError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	if errors.Is(err, ErrSignatureInvalid) {
+		writeJSONError(responseWriter, request, http.StatusUnauthorized, err)
+
+		return
+	}
+
+	writeParseError(responseWriter, request, err)
+}
+
+func writeParseError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	status, clientErr := parseErrorStatus(err)
+	writeJSONError(responseWriter, request, status, clientErr)
+}
+
+// parseErrorStatus maps a body parsing error to the status and error reported to the client.
+func parseErrorStatus(err error) (int, error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxBytesErr.Limit)
+	}
+
+	if errors.Is(err, gotify.ErrUnsupportedContentType) {
+		return http.StatusUnsupportedMediaType, err
+	}
+
+	if errors.Is(err, gotify.ErrBatchTooLarge) {
+		return http.StatusRequestEntityTooLarge, err
+	}
+
 	if errors.Is(err, gotify.ErrMessageRequired) ||
 		errors.Is(err, gotify.ErrInvalidPriority) ||
-		errors.Is(err, gotify.ErrUnsupportedContentType) {
-		writeJSONError(responseWriter, http.StatusBadRequest, err)
+		errors.Is(err, gotify.ErrInvalidExtras) {
+		return http.StatusBadRequest, err
+	}
 
-		return
+	return http.StatusBadRequest, fmt.Errorf("parse message: %w", err)
+}
+
+// upstreamStatusError matches alertmanager.HTTPStatusError without importing the client.
+type upstreamStatusError interface {
+	error
+	StatusCode() int
+	Body() string
+}
+
+// maxUpstreamBodyExcerpt caps the upstream body echoed back to clients.
+const maxUpstreamBodyExcerpt = 256
+
+// writeForwardError answers 429 when the async queue is full, 503 when no forward slot freed up
+// in time (alertmanager.maxConcurrency), 422 with the upstream status and body excerpt when
+// Alertmanager rejected the alert itself (400 or 422), 504 when the upstream timed out and 502
+// for everything else. Other 4xx (auth, 404/405 from a wrong path or API version, 429) stay
+// 502: they describe gotilert's upstream setup, not the message.
+func writeForwardError(responseWriter http.ResponseWriter, request *http.Request, err error) {
+	status, clientErr := forwardErrorStatus(err)
+	writeJSONError(responseWriter, request, status, clientErr)
+}
+
+// forwardErrorStatus maps a forwarder error to the status and error reported to the client.
+func forwardErrorStatus(err error) (int, error) {
+	if errors.Is(err, ErrForwardQueueFull) {
+		return http.StatusTooManyRequests, fmt.Errorf("%w", ErrForwardQueueFull)
+	}
+
+	if errors.Is(err, ErrForwardBusy) {
+		return http.StatusServiceUnavailable, fmt.Errorf("%w", ErrForwardBusy)
+	}
+
+	if errors.Is(err, ErrNotReady) {
+		return http.StatusServiceUnavailable, fmt.Errorf("%w", ErrNotReady)
+	}
+
+	var statusErr upstreamStatusError
+	if errors.As(err, &statusErr) && isRejection(statusErr.StatusCode()) {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"%w: alertmanager status %d: %s",
+			ErrUpstreamRejected,
+			statusErr.StatusCode(),
+			sanitizeUpstreamBody(statusErr.Body()),
+		)
+	}
+
+	if isTimeout(err) {
+		return http.StatusGatewayTimeout, fmt.Errorf("%w", ErrUpstreamTimeout)
 	}
 
-	writeJSONError(responseWriter, http.StatusBadRequest, fmt.Errorf("parse message: %w", err))
+	return http.StatusBadGateway, fmt.Errorf("%w", ErrUpstreamFailed)
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isRejection reports whether Alertmanager refused the alert's content (its validation errors).
+func isRejection(status int) bool {
+	return status == http.StatusBadRequest || status == http.StatusUnprocessableEntity
+}
+
+// sanitizeUpstreamBody collapses whitespace, drops control characters and truncates the
+// excerpt on a rune boundary.
+func sanitizeUpstreamBody(body string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+
+		return r
+	}, body)
+
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if len(cleaned) <= maxUpstreamBodyExcerpt {
+		return cleaned
+	}
+
+	cut := maxUpstreamBodyExcerpt
+	for cut > 0 && !utf8.RuneStart(cleaned[cut]) {
+		cut--
+	}
+
+	return cleaned[:cut] + "..."
 }
 
 func extractToken(request *http.Request) string {
@@ -144,6 +452,11 @@ func extractToken(request *http.Request) string {
 	}
 
 	// 3) Authorization: Bearer <token>
+	return bearerToken(request)
+}
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header, or "".
+func bearerToken(request *http.Request) string {
 	authHeader := strings.TrimSpace(request.Header.Get("Authorization"))
 	if authHeader == "" {
 		return ""
@@ -172,10 +485,33 @@ func writeJSON(responseWriter http.ResponseWriter, status int, payload any) {
 	}
 }
 
-func writeJSONError(responseWriter http.ResponseWriter, status int, err error) {
+// writeJSONError writes {"error":"...","code":"..."}, or Gotify's full envelope (plus code)
+// when the request went through withGotifyErrors (server.gotifyCompat).
+func writeJSONError(responseWriter http.ResponseWriter, request *http.Request, status int, err error) {
 	type errorBody struct {
 		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+
+	type gotifyErrorBody struct {
+		Error            string `json:"error"`
+		ErrorCode        int    `json:"errorCode"`
+		ErrorDescription string `json:"errorDescription"`
+		Code             string `json:"code"`
+	}
+
+	code := errorCode(err, status)
+
+	if wantsGotifyErrors(request) {
+		writeJSON(responseWriter, status, gotifyErrorBody{
+			Error:            http.StatusText(status),
+			ErrorCode:        status,
+			ErrorDescription: err.Error(),
+			Code:             code,
+		})
+
+		return
 	}
 
-	writeJSON(responseWriter, status, errorBody{Error: err.Error()})
+	writeJSON(responseWriter, status, errorBody{Error: err.Error(), Code: code})
 }
@@ -0,0 +1,69 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+)
+
+// MessageID identifies one accepted /message. Seq is the per-process counter returned as the
+// Gotify message id; String prefixes it with a nonce drawn at process start, so the
+// gotilert_id sent to Alertmanager stays unique across restarts and replicas.
+type MessageID struct {
+	Nonce string
+	Seq   uint64
+}
+
+// String formats the ID as "<nonce>-<seq>", e.g. "5f0c2a9e81d4b736-42"; just "<seq>" without a nonce.
+func (id MessageID) String() string {
+	seq := strconv.FormatUint(id.Seq, 10)
+	if id.Nonce == "" {
+		return seq
+	}
+
+	return id.Nonce + "-" + seq
+}
+
+var (
+	processNonce = newProcessNonce()
+	messageSeq   atomic.Uint64
+)
+
+func newProcessNonce() string {
+	var buf [8]byte
+
+	// crypto/rand.Read never returns an error on supported platforms.
+	_, _ = rand.Read(buf[:])
+
+	return hex.EncodeToString(buf[:])
+}
+
+// nextMessageID returns the ID for the next accepted message.
+func nextMessageID() MessageID {
+	return MessageID{Nonce: processNonce, Seq: messageSeq.Add(1)}
+}
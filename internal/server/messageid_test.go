@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+var messageIDPattern = regexp.MustCompile(`^[0-9a-f]{16}-[0-9]+$`)
+
+func TestMessageIDString(t *testing.T) {
+	t.Parallel()
+
+	if got := (server.MessageID{Nonce: "5f0c2a9e81d4b736", Seq: 42}).String(); got != "5f0c2a9e81d4b736-42" {
+		t.Fatalf("expected nonce-seq, got %q", got)
+	}
+
+	if got := (server.MessageID{Seq: 7}).String(); got != "7" {
+		t.Fatalf("expected bare seq without nonce, got %q", got)
+	}
+}
+
+func TestMessageForwardsNonceQualifiedID(t *testing.T) {
+	t.Parallel()
+
+	var forwarded server.MessageID
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, _ gotify.MessageRequest, messageID server.MessageID) error {
+			forwarded = messageID
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	if !messageIDPattern.MatchString(forwarded.String()) {
+		t.Fatalf("expected gotilert_id like <16 hex>-<seq>, got %q", forwarded.String())
+	}
+
+	var response struct {
+		ID uint64 `json:"id"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if response.ID != forwarded.Seq {
+		t.Fatalf("expected response id %d to match the forwarded seq, got %d", forwarded.Seq, response.ID)
+	}
+}
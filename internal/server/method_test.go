@@ -0,0 +1,65 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageAllowHeader(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		method string
+		want   int
+	}{
+		{method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{method: http.MethodPut, want: http.StatusMethodNotAllowed},
+		{method: http.MethodOptions, want: http.StatusNoContent},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(testCase.method, "http://example.local/message", nil)
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.want {
+			t.Fatalf("%s: expected status %d, got %d", testCase.method, testCase.want, rec.Code)
+		}
+
+		if got := rec.Header().Get("Allow"); got != "POST, OPTIONS" {
+			t.Fatalf("%s: expected Allow %q, got %q", testCase.method, "POST, OPTIONS", got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+const metricsAuthRealm = `Basic realm="gotilert metrics"`
+
+// MetricsAuth protects /metrics with a bearer token or, when Username is set, basic auth.
+type MetricsAuth struct {
+	BearerToken string
+	Username    string
+	Password    string
+}
+
+// requireMetricsAuth wraps next with a credential check; a nil auth leaves it open.
+func requireMetricsAuth(auth *MetricsAuth, next http.Handler) http.Handler {
+	if auth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		if !auth.authorized(request) {
+			if auth.Username != "" {
+				responseWriter.Header().Set("WWW-Authenticate", metricsAuthRealm)
+			} else {
+				responseWriter.Header().Set("WWW-Authenticate", "Bearer")
+			}
+
+			writeJSONError(responseWriter, request, http.StatusUnauthorized, ErrUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(responseWriter, request)
+	})
+}
+
+func (auth *MetricsAuth) authorized(request *http.Request) bool {
+	if auth.Username != "" {
+		username, password, ok := request.BasicAuth()
+
+		return ok && constantTimeEqual(username, auth.Username) && constantTimeEqual(password, auth.Password)
+	}
+
+	token := bearerToken(request)
+
+	return token != "" && constantTimeEqual(token, auth.BearerToken)
+}
+
+func constantTimeEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
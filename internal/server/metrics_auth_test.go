@@ -0,0 +1,102 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMetricsBearerAuth(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		Metrics:     metrics.New(),
+		MetricsAuth: &server.MetricsAuth{BearerToken: "scrape-token"},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		path          string
+		authorization string
+		wantStatus    int
+	}{
+		{path: "/metrics", authorization: "", wantStatus: http.StatusUnauthorized},
+		{path: "/metrics", authorization: "Bearer wrong", wantStatus: http.StatusUnauthorized},
+		{path: "/metrics", authorization: "Bearer scrape-token", wantStatus: http.StatusOK},
+		{path: "/healthz", authorization: "", wantStatus: http.StatusOK},
+		{path: "/readyz", authorization: "", wantStatus: http.StatusOK},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodGet, "http://example.local"+testCase.path, nil)
+		if testCase.authorization != "" {
+			req.Header.Set("Authorization", testCase.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.wantStatus {
+			t.Fatalf("%s with %q: expected status %d, got %d",
+				testCase.path, testCase.authorization, testCase.wantStatus, rec.Code)
+		}
+	}
+}
+
+func TestMetricsBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		Metrics:     metrics.New(),
+		MetricsAuth: &server.MetricsAuth{Username: "prometheus", Password: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local/metrics", nil))
+
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected 401 with WWW-Authenticate, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.local/metrics", nil)
+	req.SetBasicAuth("prometheus", "secret")
+
+	rec = httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestPprofRoutesOnlyWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	for _, enabled := range []bool{false, true} {
+		httpServer, err := server.New(&server.Options{EnablePprof: enabled})
+		if err != nil {
+			t.Fatalf("server.New: %v", err)
+		}
+
+		wantStatus := http.StatusNotFound
+		if enabled {
+			wantStatus = http.StatusOK
+		}
+
+		for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/goroutine"} {
+			rec := httptest.NewRecorder()
+			httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local"+path, nil))
+
+			if rec.Code != wantStatus {
+				t.Fatalf("pprof enabled=%v: expected %s status %d, got %d", enabled, path, wantStatus, rec.Code)
+			}
+		}
+	}
+}
+
+func TestPprofRoutesUnderRoutePrefix(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{EnablePprof: true, RoutePrefix: "/gotilert"})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local/gotilert/debug/pprof/goroutine", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/logger"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+// Not parallel: it swaps the global logger.
+func TestProbeRequestsAreLoggedAtDebugByDefault(t *testing.T) {
+	cases := []struct {
+		name      string
+		logProbes bool
+		path      string
+		want      string
+	}{
+		{name: "healthz quiet", path: "/gotilert/healthz", want: "DEBUG"},
+		{name: "readyz quiet", path: "/gotilert/readyz", want: "DEBUG"},
+		{name: "other routes stay info", path: "/gotilert/missing", want: "INFO"},
+		{name: "logProbes keeps info", logProbes: true, path: "/gotilert/healthz", want: "INFO"},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			httpServer, err := server.New(&server.Options{
+				Addr:        "127.0.0.1:0",
+				RoutePrefix: "/gotilert",
+				LogProbes:   testCase.logProbes,
+			})
+			if err != nil {
+				t.Fatalf("server.New: %v", err)
+			}
+
+			got := accessLogLevel(t, httpServer.Handler, testCase.path)
+			if got != testCase.want {
+				t.Fatalf("expected access log level %s for %s, got %s", testCase.want, testCase.path, got)
+			}
+		})
+	}
+}
+
+func accessLogLevel(t *testing.T, handler http.Handler, path string) string {
+	t.Helper()
+
+	previous := logger.L()
+	t.Cleanup(func() { logger.Set(previous) })
+
+	var buf bytes.Buffer
+
+	logger.Set(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+	var record struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+
+	err := json.Unmarshal(buf.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("decode access log %q: %v", buf.String(), err)
+	}
+
+	if record.Msg != "http request" {
+		t.Fatalf("expected an http request record, got %q", buf.String())
+	}
+
+	return record.Level
+}
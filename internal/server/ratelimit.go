@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often idle buckets are evicted.
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit is a token-bucket limit for one app. RPS <= 0 disables limiting.
+type RateLimit struct {
+	RPS   float64
+	Burst int
+}
+
+func (limit RateLimit) enabled() bool {
+	return limit.RPS > 0
+}
+
+// burst defaults to one second worth of requests (at least 1).
+func (limit RateLimit) burst() float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+
+	return math.Max(1, math.Ceil(limit.RPS))
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+// rateLimiter holds one token bucket per key. Buckets that have been idle long enough to
+// refill completely are indistinguishable from new ones and are evicted periodically.
+type rateLimiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// allow consumes a token for key. When the bucket is empty it returns false and how long
+// until the next token is available.
+func (limiter *rateLimiter) allow(key string, limit RateLimit, now time.Time) (bool, time.Duration) {
+	return limiter.allowN(key, limit, 1, now)
+}
+
+// allowN consumes count tokens for key at once, or none: when fewer are available it
+// returns false and how long until enough have refilled.
+func (limiter *rateLimiter) allowN(key string, limit RateLimit, count int, now time.Time) (bool, time.Duration) {
+	if !limit.enabled() || count <= 0 {
+		return true, 0
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.sweepLocked(now)
+
+	bucket, ok := limiter.buckets[key]
+	if !ok || bucket.limit != limit {
+		// New key, or the limit changed on reload: start from a full bucket.
+		bucket = &tokenBucket{tokens: limit.burst(), last: now, limit: limit}
+		limiter.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	bucket.tokens = math.Min(limit.burst(), bucket.tokens+elapsed*limit.RPS)
+	bucket.last = now
+
+	if bucket.tokens >= float64(count) {
+		bucket.tokens -= float64(count)
+
+		return true, 0
+	}
+
+	wait := time.Duration((float64(count) - bucket.tokens) / limit.RPS * float64(time.Second))
+
+	return false, wait
+}
+
+func (limiter *rateLimiter) sweepLocked(now time.Time) {
+	if now.Sub(limiter.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+
+	limiter.lastSweep = now
+
+	for key, bucket := range limiter.buckets {
+		refill := time.Duration(bucket.limit.burst() / bucket.limit.RPS * float64(time.Second))
+		if now.Sub(bucket.last) >= refill {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+// retryAfterSeconds rounds wait up to whole seconds for the Retry-After header.
+func retryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
@@ -22,50 +22,38 @@
  * SOFTWARE.
  */
 
-package main
+package server
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
-func TestSeverityForPriorityExactMatch(t *testing.T) {
+func TestRateLimiterRefillsAndEvictsIdleBuckets(t *testing.T) {
 	t.Parallel()
 
-	mapping := map[int]string{
-		0: "info",
-		2: "warning",
-		5: "critical",
-	}
+	limiter := newRateLimiter()
+	limit := RateLimit{RPS: 1, Burst: 2}
+	start := time.Unix(1_700_000_000, 0)
 
-	got := severityForPriority(mapping, 2)
-	if got != "warning" {
-		t.Fatalf("expected %q, got %q", "warning", got)
+	for attempt := range 2 {
+		if ok, _ := limiter.allow("app", limit, start); !ok {
+			t.Fatalf("expected burst request %d to be allowed", attempt)
+		}
 	}
-}
-
-func TestSeverityForPriorityClosestLower(t *testing.T) {
-	t.Parallel()
 
-	mapping := map[int]string{
-		0: "info",
-		2: "warning",
-		5: "critical",
+	ok, wait := limiter.allow("app", limit, start)
+	if ok || wait != time.Second {
+		t.Fatalf("expected denial with 1s wait, got ok=%v wait=%v", ok, wait)
 	}
 
-	got := severityForPriority(mapping, 3)
-	if got != "warning" {
-		t.Fatalf("expected %q, got %q", "warning", got)
+	if ok, _ := limiter.allow("app", limit, start.Add(time.Second)); !ok {
+		t.Fatalf("expected a token after one second")
 	}
-}
-
-func TestSeverityForPriorityBelowAllChoosesSmallestKey(t *testing.T) {
-	t.Parallel()
 
-	mapping := map[int]string{
-		5:  "critical",
-		10: "warning",
-	}
+	limiter.allow("other", limit, start.Add(2*rateLimitSweepInterval))
 
-	got := severityForPriority(mapping, 1)
-	if got != "critical" {
-		t.Fatalf("expected %q, got %q", "critical", got)
+	if _, exists := limiter.buckets["app"]; exists {
+		t.Fatalf("expected idle bucket to be evicted")
 	}
 }
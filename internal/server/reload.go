@@ -0,0 +1,73 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ReloadFunc reloads the configuration. Errors wrapping ErrReloadRejected mean the new
+// configuration was invalid and the running one was kept.
+type ReloadFunc func(ctx context.Context) error
+
+// AuthorizeAdminFunc reports whether token grants access to admin endpoints.
+type AuthorizeAdminFunc func(token string) bool
+
+type reloadResponse struct {
+	Status string `json:"status"`
+}
+
+func reloadHandler(reload ReloadFunc, authorize AuthorizeAdminFunc) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writeJSONError(responseWriter, request, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+
+			return
+		}
+
+		token := extractToken(request)
+		if token == "" || authorize == nil || !authorize(token) {
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrTokenMissingOrInvalid)
+
+			return
+		}
+
+		err := reload(request.Context())
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrReloadRejected) {
+				status = http.StatusBadRequest
+			}
+
+			writeJSONError(responseWriter, request, status, err)
+
+			return
+		}
+
+		writeJSON(responseWriter, http.StatusOK, reloadResponse{Status: "reloaded"})
+	}
+}
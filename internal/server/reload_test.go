@@ -0,0 +1,108 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+var errInvalidConfig = errors.New("defaults.ttl must be > 0")
+
+func TestReloadRequiresAdminToken(t *testing.T) {
+	t.Parallel()
+
+	srv := newReloadTestServer(t, func(context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/-/reload", http.NoBody)
+	req.Header.Set("X-Gotify-Key", "WRONG")
+
+	srv.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+}
+
+func TestReloadSucceeds(t *testing.T) {
+	t.Parallel()
+
+	reloaded := false
+	srv := newReloadTestServer(t, func(context.Context) error {
+		reloaded = true
+
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/-/reload", http.NoBody)
+	req.Header.Set("Authorization", "Bearer ADMIN")
+
+	srv.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || !reloaded {
+		t.Fatalf("expected status %d and reload, got %d body=%s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+func TestReloadRejectedConfigReturnsBadRequest(t *testing.T) {
+	t.Parallel()
+
+	srv := newReloadTestServer(t, func(context.Context) error {
+		return fmt.Errorf("%w: %w", server.ErrReloadRejected, errInvalidConfig)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/-/reload", http.NoBody)
+	req.Header.Set("X-Gotify-Key", "ADMIN")
+
+	srv.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
+
+func newReloadTestServer(t *testing.T, reload server.ReloadFunc) *http.Server {
+	t.Helper()
+
+	httpServer, err := server.New(&server.Options{
+		Addr:           "127.0.0.1:0",
+		Reload:         reload,
+		AuthorizeAdmin: func(token string) bool { return token == "ADMIN" },
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	return httpServer
+}
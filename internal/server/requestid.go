@@ -0,0 +1,88 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	requestIDHeader = "X-Request-Id"
+
+	maxInboundRequestIDLen = 128
+)
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the ID assigned to the request by the server, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+
+	return requestID
+}
+
+// withRequestID assigns a request ID (reusing a sane inbound X-Request-Id, e.g. from a
+// reverse proxy), echoes it in the response header and stores it in the request context.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		requestID := request.Header.Get(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		responseWriter.Header().Set(requestIDHeader, requestID)
+
+		ctx := context.WithValue(request.Context(), requestIDContextKey{}, requestID)
+		next.ServeHTTP(responseWriter, request.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	var buf [16]byte
+
+	// crypto/rand.Read never returns an error on supported platforms.
+	_, _ = rand.Read(buf[:])
+
+	return hex.EncodeToString(buf[:])
+}
+
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxInboundRequestIDLen {
+		return false
+	}
+
+	for _, char := range requestID {
+		valid := char == '-' || char == '_' || char == '.' ||
+			(char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+		if !valid {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/metrics"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestRequestMetricsUseRouteTemplates(t *testing.T) {
+	t.Parallel()
+
+	metricsCollector := metrics.New()
+
+	httpServer, err := server.New(&server.Options{Metrics: metricsCollector})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	for _, path := range []string{"/healthz", "/wp-login.php", "/.env", "/healthz/extra"} {
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.local"+path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	metricsCollector.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`gotilert_http_requests_total{method="GET",path="/healthz",status="200"} 1`,
+		`gotilert_http_requests_total{method="GET",path="<other>",status="404"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	for _, raw := range []string{"/wp-login.php", "/.env", "/healthz/extra"} {
+		if strings.Contains(body, `path="`+raw+`"`) {
+			t.Fatalf("expected raw path %q not to be used as a label", raw)
+		}
+	}
+}
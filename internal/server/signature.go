@@ -0,0 +1,72 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	signatureHeader = "X-Gotilert-Signature"
+	signaturePrefix = "sha256="
+)
+
+// verifySignature checks X-Gotilert-Signature ("sha256=<hex HMAC-SHA256 of the body>")
+// against secret. The raw body (still gzip-compressed if it was sent that way) is read
+// once and put back so decodeBody can still decode and parse it.
+func verifySignature(request *http.Request, secret string) error {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	encoded, found := strings.CutPrefix(strings.TrimSpace(request.Header.Get(signatureHeader)), signaturePrefix)
+	if !found {
+		return ErrSignatureInvalid
+	}
+
+	signature, err := hex.DecodeString(encoded)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body) // hash.Hash writes never fail.
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestMessageVerifiesSignature(t *testing.T) {
+	t.Parallel()
+
+	const (
+		secret = "s3cret"
+		body   = `{"title":"backup","message":"done"}`
+	)
+
+	var forwarded gotify.MessageRequest
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1, SigningSecret: secret}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, msg gotify.MessageRequest, _ server.MessageID) error {
+			forwarded = msg
+
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{name: "valid", signature: "sha256=" + sign(secret, body), want: http.StatusOK},
+		{name: "missing", want: http.StatusUnauthorized},
+		{name: "wrong secret", signature: "sha256=" + sign("other", body), want: http.StatusUnauthorized},
+		{name: "not hex", signature: "sha256=zz", want: http.StatusUnauthorized},
+		{name: "wrong scheme", signature: "sha1=" + sign(secret, body), want: http.StatusUnauthorized},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+
+		if testCase.signature != "" {
+			req.Header.Set("X-Gotilert-Signature", testCase.signature)
+		}
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.want {
+			t.Fatalf("%s: expected status %d, got %d body=%s", testCase.name, testCase.want, rec.Code, rec.Body.String())
+		}
+	}
+
+	if forwarded.Message != "done" || forwarded.Title != "backup" {
+		t.Fatalf("expected the signed body to be parsed after verification, got %+v", forwarded)
+	}
+}
+
+func TestMessageVerifiesSignatureOverCompressedBody(t *testing.T) {
+	t.Parallel()
+
+	const secret = "s3cret"
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1, SigningSecret: secret}, token == "TOKEN"
+		},
+		ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	const body = `{"title":"backup","message":"done"}`
+
+	var compressed bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, _ = gzipWriter.Write([]byte(body))
+	_ = gzipWriter.Close()
+
+	cases := []struct {
+		name      string
+		signature string
+		want      int
+	}{
+		{name: "wire bytes", signature: "sha256=" + sign(secret, compressed.String()), want: http.StatusOK},
+		{name: "decoded body", signature: "sha256=" + sign(secret, body), want: http.StatusUnauthorized},
+	}
+
+	for _, testCase := range cases {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/message", bytes.NewReader(compressed.Bytes()))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+		req.Header.Set("X-Gotify-Key", "TOKEN")
+		req.Header.Set("X-Gotilert-Signature", testCase.signature)
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.want {
+			t.Fatalf("%s: expected status %d, got %d body=%s", testCase.name, testCase.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(body))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
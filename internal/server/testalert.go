@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/metrics"
+)
+
+// TestAlertFunc forwards a synthetic alert for app straight to Alertmanager (bypassing any
+// async queue) and returns the upstream result.
+type TestAlertFunc func(ctx context.Context, app App, messageID MessageID) error
+
+type testAlertResponse struct {
+	Accepted bool   `json:"accepted"`
+	App      string `json:"app"`
+	ID       uint64 `json:"id,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Code     string `json:"code,omitempty"`
+
+	// UpstreamStatus and UpstreamBody are set when Alertmanager answered with an error status.
+	UpstreamStatus int    `json:"upstreamStatus,omitempty"`
+	UpstreamBody   string `json:"upstreamBody,omitempty"`
+}
+
+// testAlertHandler serves POST /-/test: it authenticates an app token and charges its rate
+// limit like /message, and reports whether Alertmanager accepted the synthetic alert, using
+// the /message status codes.
+func testAlertHandler(
+	resolve ResolveAppFunc,
+	testAlert TestAlertFunc,
+	limiter *rateLimiter,
+	metricsCollector *metrics.Metrics,
+) http.HandlerFunc {
+	return func(responseWriter http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writeJSONError(responseWriter, request, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+
+			return
+		}
+
+		app, ok := authenticate(request, resolve)
+		if !ok {
+			writeJSONError(responseWriter, request, http.StatusForbidden, ErrTokenMissingOrInvalid)
+
+			return
+		}
+
+		allowed, wait := limiter.allow(app.Name, app.RateLimit, time.Now())
+		if !allowed {
+			metricsCollector.IncRateLimited(app.Name)
+			writeRateLimited(responseWriter, request, wait)
+
+			return
+		}
+
+		messageIdentifier := nextMessageID()
+
+		err := testAlert(request.Context(), app, messageIdentifier)
+		if err != nil {
+			status, clientErr := forwardErrorStatus(err)
+			resp := testAlertResponse{App: app.Name, Error: clientErr.Error(), Code: errorCode(clientErr, status)}
+
+			var statusErr upstreamStatusError
+			if errors.As(err, &statusErr) {
+				resp.UpstreamStatus = statusErr.StatusCode()
+				resp.UpstreamBody = sanitizeUpstreamBody(statusErr.Body())
+			}
+
+			writeJSON(responseWriter, status, resp)
+
+			return
+		}
+
+		writeJSON(responseWriter, http.StatusOK, testAlertResponse{Accepted: true, App: app.Name, ID: messageIdentifier.Seq})
+	}
+}
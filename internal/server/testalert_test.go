@@ -0,0 +1,178 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestTestAlertEndpoint(t *testing.T) {
+	t.Parallel()
+
+	type response struct {
+		Accepted       bool   `json:"accepted"`
+		App            string `json:"app"`
+		Error          string `json:"error"`
+		UpstreamStatus int    `json:"upstreamStatus"`
+		UpstreamBody   string `json:"upstreamBody"`
+	}
+
+	cases := []struct {
+		name       string
+		token      string
+		upstream   error
+		wantStatus int
+		want       response
+	}{
+		{name: "accepted", token: "TOKEN", wantStatus: http.StatusOK, want: response{Accepted: true, App: "nas"}},
+		{
+			name: "rejected", token: "TOKEN",
+			upstream:   fmt.Errorf("post alert: %w", &fakeStatusError{status: 400, body: "bad labels"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			want:       response{App: "nas", UpstreamStatus: 400, UpstreamBody: "bad labels"},
+		},
+		{name: "bad token", token: "WRONG", wantStatus: http.StatusForbidden},
+	}
+
+	for _, testCase := range cases {
+		var sent []server.App
+
+		httpServer, err := server.New(&server.Options{
+			ResolveApp: func(token string) (server.App, bool) {
+				return server.App{Name: "nas", ID: 1}, token == "TOKEN"
+			},
+			TestAlert: func(_ context.Context, app server.App, _ server.MessageID) error {
+				sent = append(sent, app)
+
+				return testCase.upstream
+			},
+		})
+		if err != nil {
+			t.Fatalf("server.New: %v", err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "http://example.local/-/test?token="+testCase.token, nil)
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != testCase.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d (body=%q)", testCase.name, testCase.wantStatus, rec.Code, rec.Body.String())
+		}
+
+		if testCase.wantStatus == http.StatusForbidden {
+			if len(sent) != 0 {
+				t.Fatalf("%s: expected no test alert for an invalid token", testCase.name)
+			}
+
+			continue
+		}
+
+		var got response
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("%s: decode response: %v", testCase.name, err)
+		}
+
+		got.Error = ""
+		if got != testCase.want {
+			t.Fatalf("%s: expected %+v, got %+v", testCase.name, testCase.want, got)
+		}
+	}
+}
+
+func TestTestAlertEndpointDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/-/test?token=TOKEN", nil)
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rec.Code)
+	}
+}
+
+func TestTestAlertEndpointSharesMessageAllowlistAndRateLimit(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(allowed string) *http.Server {
+		httpServer, err := server.New(&server.Options{
+			ResolveApp: func(token string) (server.App, bool) {
+				return server.App{Name: "nas", ID: 1, RateLimit: server.RateLimit{RPS: 0.001, Burst: 1}}, token == "TOKEN"
+			},
+			ForwardMessage: func(context.Context, server.App, gotify.MessageRequest, server.MessageID) error {
+				return nil
+			},
+			TestAlert: func(context.Context, server.App, server.MessageID) error {
+				return nil
+			},
+			AllowedClients: []netip.Prefix{netip.MustParsePrefix(allowed)},
+		})
+		if err != nil {
+			t.Fatalf("server.New: %v", err)
+		}
+
+		return httpServer
+	}
+
+	post := func(httpServer *http.Server, path string) int {
+		req := httptest.NewRequest(http.MethodPost, "http://example.local"+path+"?token=TOKEN", strings.NewReader(`{"message":"m"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		rec := httptest.NewRecorder()
+		httpServer.Handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	if status := post(newServer("10.0.0.0/8"), "/-/test"); status != http.StatusForbidden {
+		t.Fatalf("expected 403 for a client outside allowedCIDRs, got %d", status)
+	}
+
+	allowedServer := newServer("192.0.2.0/24")
+
+	if status := post(allowedServer, "/message"); status != http.StatusOK {
+		t.Fatalf("expected 200 for the first /message, got %d", status)
+	}
+
+	if status := post(allowedServer, "/-/test"); status != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once /message used up the app's burst, got %d", status)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSOptions enables HTTPS on the listener. ClientCAFile additionally requires and verifies
+// client certificates (mutual TLS).
+type TLSOptions struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+func buildTLSConfig(opts *TLSOptions) (*tls.Config, error) {
+	certFile := strings.TrimSpace(opts.CertFile)
+	keyFile := strings.TrimSpace(opts.KeyFile)
+
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("%w: certFile and keyFile are required", ErrTLSConfig)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: load cert %q / key %q: %w", ErrTLSConfig, certFile, keyFile, err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+
+	clientCAFile := strings.TrimSpace(opts.ClientCAFile)
+	if clientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pemData, err := os.ReadFile(clientCAFile) //nolint:gosec // path comes from trusted config.
+	if err != nil {
+		return nil, fmt.Errorf("%w: read client CA %q: %w", ErrTLSConfig, clientCAFile, err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("%w: no certificates found in client CA %q", ErrTLSConfig, clientCAFile)
+	}
+
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return tlsConfig, nil
+}
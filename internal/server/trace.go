@@ -0,0 +1,137 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/leinardi/gotilert/internal/metrics"
+)
+
+// tracer resolves through the global provider, so spans are no-ops unless tracing.Setup ran.
+var tracer = otel.Tracer("github.com/leinardi/gotilert/internal/server")
+
+const (
+	traceparentHeader = "Traceparent"
+
+	traceIDLen = 32
+	spanIDLen  = 16
+)
+
+type traceContextKey struct{}
+
+// TraceFromContext returns the active OpenTelemetry span when tracing is enabled, else the
+// trace parsed from the request's W3C traceparent header, or the zero Trace when there is none.
+func TraceFromContext(ctx context.Context) metrics.Trace {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		return metrics.Trace{TraceID: spanContext.TraceID().String(), SpanID: spanContext.SpanID().String()}
+	}
+
+	inbound, _ := ctx.Value(traceContextKey{}).(metrics.Trace)
+
+	return inbound
+}
+
+// startServerSpan continues the caller's trace (per the global propagator) with a server span
+// named after the matched route.
+func startServerSpan(request *http.Request, route string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+
+	return tracer.Start(ctx, request.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(request.Method),
+			semconv.HTTPRoute(route),
+		),
+	)
+}
+
+func endServerSpan(span trace.Span, status int) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+
+	span.End()
+}
+
+// withTraceContext stores a valid inbound traceparent in the request context so metrics
+// can link observations to the trace as exemplars. Invalid headers are ignored.
+func withTraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
+		inbound, ok := parseTraceparent(request.Header.Get(traceparentHeader))
+		if ok {
+			request = request.WithContext(context.WithValue(request.Context(), traceContextKey{}, inbound))
+		}
+
+		next.ServeHTTP(responseWriter, request)
+	})
+}
+
+// parseTraceparent parses "version-traceid-parentid-flags" (W3C Trace Context).
+func parseTraceparent(header string) (metrics.Trace, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return metrics.Trace{}, false
+	}
+
+	version, traceID, spanID := parts[0], parts[1], parts[2]
+
+	// Version 00 has exactly four fields; "ff" is forbidden.
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return metrics.Trace{}, false
+	}
+
+	if !isTraceField(traceID, traceIDLen) || !isTraceField(spanID, spanIDLen) {
+		return metrics.Trace{}, false
+	}
+
+	return metrics.Trace{TraceID: traceID, SpanID: spanID}, true
+}
+
+// isTraceField reports whether value is length lowercase hex digits and not all zeros.
+func isTraceField(value string, length int) bool {
+	return len(value) == length && isLowerHex(value) && strings.Trim(value, "0") != ""
+}
+
+func isLowerHex(value string) bool {
+	for _, char := range value {
+		if (char < '0' || char > '9') && (char < 'a' || char > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
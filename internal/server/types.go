@@ -26,18 +26,81 @@ package server
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/templating"
 )
 
 type App struct {
 	Name                 string
 	ID                   uint32
 	AlertName            string
-	Labels               map[string]string
+	Labels               *templating.Map
+	Annotations          *templating.Map
 	SeverityFromPriority map[int]string
+	Resolve              ResolveTrigger
+
+	// MinForwardPriority overrides the default forwarding threshold when non-nil.
+	MinForwardPriority *int
+
+	// AlertnameFromTitle makes a non-empty message title the alertname instead of AlertName.
+	AlertnameFromTitle bool
+
+	// TTLFromPriority overrides the default per-priority TTLs when non-empty.
+	TTLFromPriority map[int]time.Duration
+
+	// GeneratorURL overrides the default generatorURL template when non-nil.
+	GeneratorURL *templating.Map
+
+	// GotilertIDAsAnnotation overrides the default gotilert_id placement when non-nil.
+	GotilertIDAsAnnotation *bool
+
+	// ExtrasPolicy overrides the default extras policy when non-nil.
+	ExtrasPolicy *gotify.ExtrasPolicy
+
+	// SigningSecret, when set, requires /message bodies to carry a valid HMAC-SHA256
+	// X-Gotilert-Signature.
+	SigningSecret string
+
+	// RateLimit bounds /message requests for this app (shared by all of its tokens).
+	RateLimit RateLimit
+}
+
+// ResolveTrigger marks messages that resolve previously forwarded alerts of the same app
+// instead of firing a new one. The zero value never matches.
+type ResolveTrigger struct {
+	// Priority, when set, resolves on messages with exactly this priority.
+	Priority *int
+	// ExtrasKey, when set, resolves on messages whose top-level extras[ExtrasKey] is true.
+	ExtrasKey string
+}
+
+func (trigger ResolveTrigger) Enabled() bool {
+	return trigger.Priority != nil || trigger.ExtrasKey != ""
+}
+
+// Matches reports whether req should resolve alerts rather than fire one.
+func (trigger ResolveTrigger) Matches(req gotify.MessageRequest) bool {
+	if trigger.Priority != nil && req.Priority == *trigger.Priority {
+		return true
+	}
+
+	if trigger.ExtrasKey == "" {
+		return false
+	}
+
+	switch value := req.Extras[trigger.ExtrasKey].(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(strings.TrimSpace(value), "true")
+	default:
+		return false
+	}
 }
 
 type ResolveAppFunc func(token string) (App, bool)
 
-type ForwardMessageFunc func(ctx context.Context, app App, req gotify.MessageRequest, messageID uint64) error
+type ForwardMessageFunc func(ctx context.Context, app App, req gotify.MessageRequest, messageID MessageID) error
@@ -0,0 +1,126 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+func TestListenAndServeUnixSocketReplacesStaleSocketAndCleansUp(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "gotilert.sock")
+
+	// Leave a stale socket file behind, as a crashed process would.
+	stale, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	httpServer, err := server.New(&server.Options{Addr: "unix:" + socketPath})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() { serveErr <- server.ListenAndServe(httpServer) }()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+		Timeout: time.Second,
+	}
+
+	var resp *http.Response
+
+	for range 50 {
+		resp, err = client.Get("http://gotilert/healthz")
+		if err == nil {
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err != nil {
+		t.Fatalf("GET /healthz over unix socket: %v", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	err = server.Shutdown(context.Background(), httpServer, time.Second)
+	if err != nil {
+		t.Fatalf("server.Shutdown: %v", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		t.Fatalf("expected http.ErrServerClosed, got: %v", err)
+	}
+
+	if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected socket file to be removed, got: %v", err)
+	}
+}
+
+func TestListenAndServeUnixRefusesNonSocketFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "not-a-socket")
+
+	err := os.WriteFile(path, []byte("data"), 0o600)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	httpServer, err := server.New(&server.Options{Addr: "unix:" + path})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	err = server.ListenAndServe(httpServer)
+	if !errors.Is(err, server.ErrUnixSocketPath) {
+		t.Fatalf("expected ErrUnixSocketPath, got: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/gotify"
+	"github.com/leinardi/gotilert/internal/server"
+)
+
+type fakeStatusError struct {
+	status int
+	body   string
+}
+
+func (e *fakeStatusError) Error() string   { return fmt.Sprintf("status %d", e.status) }
+func (e *fakeStatusError) StatusCode() int { return e.status }
+func (e *fakeStatusError) Body() string    { return e.body }
+
+func TestMessageMapsForwardErrorsToStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		forwardErr error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "validation error",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 400, body: "start time must be\nbefore end time"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   "alertmanager status 400: start time must be before end time",
+		},
+		{
+			name:       "unprocessable alert",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 422, body: "invalid label"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   "alertmanager status 422: invalid label",
+		},
+		{
+			name:       "wrong alerts path",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 404, body: "not found"}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   server.ErrUpstreamFailed.Error(),
+		},
+		{
+			name:       "wrong api version",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 405, body: "method not allowed"}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   server.ErrUpstreamFailed.Error(),
+		},
+		{
+			name:       "server error",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 503, body: "unavailable"}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   server.ErrUpstreamFailed.Error(),
+		},
+		{
+			name:       "upstream auth",
+			forwardErr: fmt.Errorf("post alert: %w", &fakeStatusError{status: 401, body: "unauthorized"}),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   server.ErrUpstreamFailed.Error(),
+		},
+		{
+			name:       "deadline exceeded",
+			forwardErr: fmt.Errorf("post alert: %w", context.DeadlineExceeded),
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   server.ErrUpstreamTimeout.Error(),
+		},
+		{
+			name:       "network timeout",
+			forwardErr: fmt.Errorf("post alert: %w", &net.DNSError{Err: "i/o timeout", Name: "alertmanager", IsTimeout: true}),
+			wantStatus: http.StatusGatewayTimeout,
+			wantBody:   server.ErrUpstreamTimeout.Error(),
+		},
+		{
+			name:       "async queue full",
+			forwardErr: fmt.Errorf("%w: queue", server.ErrForwardQueueFull),
+			wantStatus: http.StatusTooManyRequests,
+			wantBody:   server.ErrForwardQueueFull.Error(),
+		},
+		{
+			name:       "no forward slot",
+			forwardErr: fmt.Errorf("%w: %w", server.ErrForwardBusy, context.DeadlineExceeded),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   server.ErrForwardBusy.Error(),
+		},
+		{
+			name:       "waiting for startup readiness",
+			forwardErr: fmt.Errorf("%w: connection refused", server.ErrNotReady),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   server.ErrNotReady.Error(),
+		},
+		{
+			name:       "transport error",
+			forwardErr: errors.New("connection refused"),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   server.ErrUpstreamFailed.Error(),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			httpServer, err := server.New(&server.Options{
+				ResolveApp: func(token string) (server.App, bool) {
+					return server.App{Name: "app", ID: 1}, token == "TOKEN"
+				},
+				ForwardMessage: func(_ context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+					return testCase.forwardErr
+				},
+			})
+			if err != nil {
+				t.Fatalf("server.New: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hello"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Gotify-Key", "TOKEN")
+
+			rec := httptest.NewRecorder()
+			httpServer.Handler.ServeHTTP(rec, req)
+
+			if rec.Code != testCase.wantStatus {
+				t.Fatalf("expected status %d, got %d body=%s", testCase.wantStatus, rec.Code, rec.Body.String())
+			}
+
+			if !strings.Contains(rec.Body.String(), testCase.wantBody) {
+				t.Fatalf("expected body to contain %q, got %s", testCase.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMessageAsyncForwardAnswersAccepted(t *testing.T) {
+	t.Parallel()
+
+	httpServer, err := server.New(&server.Options{
+		ResolveApp: func(token string) (server.App, bool) {
+			return server.App{Name: "app", ID: 1}, token == "TOKEN"
+		},
+		ForwardMessage: func(_ context.Context, _ server.App, _ gotify.MessageRequest, _ server.MessageID) error {
+			return nil
+		},
+		AsyncForward: true,
+	})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.local/message", strings.NewReader(`{"message":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", "TOKEN")
+
+	rec := httptest.NewRecorder()
+	httpServer.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d body=%s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+}
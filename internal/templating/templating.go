@@ -0,0 +1,220 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package templating renders label and annotation values from Go text/template expressions.
+package templating
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// RenderFailed replaces the value of a template that fails to execute, so neither a partial
+// result nor the template source reaches Alertmanager.
+const RenderFailed = "<template error>"
+
+// emptyIfNilFunc is piped after every printing action: text/template prints missing map keys
+// (e.g. absent extras) as "<no value>", even with missingkey=zero.
+const emptyIfNilFunc = "gotilertEmptyIfNil"
+
+var funcs = template.FuncMap{emptyIfNilFunc: emptyIfNil}
+
+// Data is the context templates are evaluated against.
+type Data struct {
+	Title    string
+	Message  string
+	Priority int
+	AppName  string
+	Extras   map[string]any
+
+	// GotilertID is the per-message identifier also sent as the gotilert_id label.
+	GotilertID string
+}
+
+// Map is a compiled set of key -> value templates. Values without "{{" are kept verbatim.
+type Map struct {
+	source    map[string]string
+	static    map[string]string
+	templates map[string]*template.Template
+}
+
+// Compile parses every value once; syntax errors are returned with the offending key.
+func Compile(values map[string]string) (*Map, error) {
+	compiled := &Map{
+		source:    maps.Clone(values),
+		static:    make(map[string]string, len(values)),
+		templates: make(map[string]*template.Template),
+	}
+
+	for key, value := range values {
+		if !strings.Contains(value, "{{") {
+			compiled.static[key] = value
+
+			continue
+		}
+
+		parsed, err := template.New(key).Option("missingkey=zero").Funcs(funcs).Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+
+		for _, associated := range parsed.Templates() {
+			pipeEmptyIfNil(associated.Tree, associated.Root)
+		}
+
+		compiled.templates[key] = parsed
+	}
+
+	return compiled, nil
+}
+
+// Source returns the uncompiled values, as written in the config.
+func (compiled *Map) Source() map[string]string {
+	if compiled == nil {
+		return map[string]string{}
+	}
+
+	out := make(map[string]string, len(compiled.source))
+	maps.Copy(out, compiled.source)
+
+	return out
+}
+
+// Render evaluates the templates against data. A template that fails to execute renders as
+// RenderFailed; the joined errors are returned alongside the (complete) result.
+func (compiled *Map) Render(data *Data) (map[string]string, error) {
+	if compiled == nil {
+		return map[string]string{}, nil
+	}
+
+	out := make(map[string]string, len(compiled.static)+len(compiled.templates))
+	maps.Copy(out, compiled.static)
+
+	var renderErrs []error
+
+	for key, tmpl := range compiled.templates {
+		var builder strings.Builder
+
+		err := tmpl.Execute(&builder, data)
+		if err != nil {
+			renderErrs = append(renderErrs, fmt.Errorf("%w: key %q: %w", ErrRender, key, err))
+			out[key] = RenderFailed
+
+			continue
+		}
+
+		out[key] = builder.String()
+	}
+
+	return out, errors.Join(renderErrs...)
+}
+
+// NumericExtras returns a copy of extras with json.Number values (kept by the parser so extras
+// echo verbatim) converted to int64, or float64 when not integral, so templates can compare
+// and format them as numbers. Numbers that fit neither stay strings.
+func NumericExtras(extras map[string]any) map[string]any {
+	if extras == nil {
+		return nil
+	}
+
+	out := make(map[string]any, len(extras))
+	for key, value := range extras {
+		out[key] = numericValue(value)
+	}
+
+	return out
+}
+
+func numericValue(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			return integer
+		}
+
+		if float, err := value.Float64(); err == nil {
+			return float
+		}
+
+		return value.String()
+	case map[string]any:
+		return NumericExtras(value)
+	case []any:
+		out := make([]any, len(value))
+		for index, element := range value {
+			out[index] = numericValue(element)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+func emptyIfNil(value any) any {
+	if value == nil {
+		return ""
+	}
+
+	return value
+}
+
+// pipeEmptyIfNil appends emptyIfNilFunc to every action below node that prints its result.
+func pipeEmptyIfNil(tree *parse.Tree, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			pipeEmptyIfNil(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+
+		identifier := parse.NewIdentifier(emptyIfNilFunc).SetTree(tree).SetPos(node.Pos)
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{identifier},
+		})
+	case *parse.IfNode:
+		pipeEmptyIfNil(tree, node.List)
+		pipeEmptyIfNil(tree, node.ElseList)
+	case *parse.RangeNode:
+		pipeEmptyIfNil(tree, node.List)
+		pipeEmptyIfNil(tree, node.ElseList)
+	case *parse.WithNode:
+		pipeEmptyIfNil(tree, node.List)
+		pipeEmptyIfNil(tree, node.ElseList)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package templating_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/leinardi/gotilert/internal/templating"
+)
+
+func TestRenderEvaluatesTemplatesAndKeepsStaticValues(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := templating.Compile(map[string]string{
+		"instance": "{{ .Title }}",
+		"team":     "{{ .Extras.team }}",
+		"missing":  "x{{ .Extras.nope }}y",
+		"source":   "gotilert",
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got, err := compiled.Render(&templating.Data{
+		Title:  "nas01",
+		Extras: map[string]any{"team": "ops"},
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := map[string]string{
+		"instance": "nas01",
+		"team":     "ops",
+		"missing":  "xy",
+		"source":   "gotilert",
+	}
+
+	for key, wantValue := range want {
+		if got[key] != wantValue {
+			t.Fatalf("key %q: expected %q, got %q", key, wantValue, got[key])
+		}
+	}
+}
+
+func TestRenderMissingExtrasKeepsLiteralNoValue(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := templating.Compile(map[string]string{
+		"summary": "{{ .Message }}{{ .Extras.nope }}",
+		"nested":  "{{ if .Title }}{{ .Extras.nope }}{{ end }}|{{ with $team := .Extras.team }}{{ $team }}{{ end }}",
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got, err := compiled.Render(&templating.Data{Title: "t", Message: "disk is <no value>"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if got["summary"] != "disk is <no value>" {
+		t.Fatalf("expected the message text kept, got %q", got["summary"])
+	}
+
+	if got["nested"] != "|" {
+		t.Fatalf("expected missing extras empty in nested actions, got %q", got["nested"])
+	}
+}
+
+func TestRenderFailureUsesFixedFallback(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := templating.Compile(map[string]string{"team": `{{ index .Title "x" }}`})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got, err := compiled.Render(&templating.Data{Title: "nas01"})
+	if !errors.Is(err, templating.ErrRender) {
+		t.Fatalf("expected ErrRender, got: %v", err)
+	}
+
+	if got["team"] != templating.RenderFailed {
+		t.Fatalf("expected %q, got %q", templating.RenderFailed, got["team"])
+	}
+}
+
+func TestRenderComparesNumericExtras(t *testing.T) {
+	t.Parallel()
+
+	compiled, err := templating.Compile(map[string]string{
+		"size":  `{{ if gt .Extras.count 5 }}many{{ else }}few{{ end }}`,
+		"count": `{{ printf "%03d" .Extras.count }}`,
+		"load":  `{{ printf "%.1f" (index .Extras.host "load") }}`,
+	})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	got, err := compiled.Render(&templating.Data{Extras: templating.NumericExtras(map[string]any{
+		"count": json.Number("7"),
+		"host":  map[string]any{"load": json.Number("0.75")},
+	})})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := map[string]string{"size": "many", "count": "007", "load": "0.8"}
+	for key, wantValue := range want {
+		if got[key] != wantValue {
+			t.Fatalf("key %q: expected %q, got %q", key, wantValue, got[key])
+		}
+	}
+}
+
+func TestCompileRejectsSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := templating.Compile(map[string]string{"broken": "{{ .Title "})
+	if err == nil {
+		t.Fatalf("expected syntax error, got nil")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+// Package tracing wires optional OpenTelemetry tracing. Until Setup is called the global
+// OpenTelemetry providers are no-ops, so instrumented code costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// defaultServiceName is used unless OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES set one.
+const defaultServiceName = "gotilert"
+
+var ErrSetup = errors.New("tracing setup failed")
+
+// ShutdownFunc flushes buffered spans and stops the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup installs an OTLP/HTTP span exporter and the W3C trace-context/baggage propagators
+// as the process-wide OpenTelemetry providers.
+//
+// Everything else comes from the standard OTEL_* environment variables: the exporter reads
+// OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_TRACES_* (endpoint, headers, TLS, timeout),
+// the resource reads OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES, and the sampler reads
+// OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG.
+func Setup(ctx context.Context, version string) (ShutdownFunc, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: create otlp exporter: %w", ErrSetup, err)
+	}
+
+	// Environment attributes are merged last so OTEL_SERVICE_NAME wins over the default.
+	res, err := resource.Merge(
+		resource.NewSchemaless(
+			semconv.ServiceName(defaultServiceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: build resource: %w", ErrSetup, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			return fmt.Errorf("shutdown tracer provider: %w", err)
+		}
+
+		return nil
+	}, nil
+}
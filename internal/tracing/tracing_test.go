@@ -0,0 +1,97 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2025 Roberto Leinardi
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/leinardi/gotilert/internal/alertmanager"
+	"github.com/leinardi/gotilert/internal/tracing"
+)
+
+func TestSetupExportsSpansAndPropagatesTraceparent(t *testing.T) {
+	var exported atomic.Int32
+
+	collector := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/v1/traces" {
+			exported.Add(1)
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	var traceparent atomic.Value
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		traceparent.Store(request.Header.Get("traceparent"))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	// Setenv forbids t.Parallel; the exporter is configured purely from OTEL_* variables.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", collector.URL)
+	t.Setenv("OTEL_TRACES_SAMPLER", "always_on")
+
+	shutdown, err := tracing.Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "parent")
+
+	client, err := alertmanager.New(&alertmanager.Options{BaseURL: upstream.URL})
+	if err != nil {
+		t.Fatalf("alertmanager.New: %v", err)
+	}
+
+	err = client.PostAlerts(ctx, []alertmanager.Alert{{Labels: map[string]string{"alertname": "Test"}}})
+	if err != nil {
+		t.Fatalf("PostAlerts: %v", err)
+	}
+
+	span.End()
+
+	got, _ := traceparent.Load().(string)
+	if !strings.Contains(got, span.SpanContext().TraceID().String()) {
+		t.Fatalf("expected traceparent with trace id %s, got %q", span.SpanContext().TraceID(), got)
+	}
+
+	err = shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if exported.Load() == 0 {
+		t.Fatalf("expected spans to be exported to the OTLP endpoint")
+	}
+}